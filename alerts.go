@@ -29,20 +29,74 @@
 package main
 
 import (
+  "bytes"
   "fmt"
+  "io"
+  "os"
+  "os/exec"
+  "strings"
 )
 
 type Alerts struct {
-  Date        string
-  Expires     string
-  Description string
-  Message     string
+  Type         string `json:"type"`
+  Date         string `json:"date"`
+  Expires      string `json:"expires"`
+  Description  string `json:"description"`
+  Message      string `json:"message"`
+  Message_id   string `json:"message_id"`
+  Phenomena    string `json:"phenomena"`
+  Significance string `json:"significance"`
+  Zones        []Zone `json:"zones"`
+}
+
+type Zone struct {
+  State string `json:"state"`
+  Zone  string `json:"ZONE"`
+}
+
+// wrapText wraps each paragraph (line) of s at width columns
+func wrapText(s string, width int) string {
+  lines := make([]string, 0)
+  for _, paragraph := range strings.Split(s, "\n") {
+    line := ""
+    for _, word := range strings.Fields(paragraph) {
+      if line != "" && len(line)+1+len(word) > width {
+        lines = append(lines, line)
+        line = ""
+      }
+      if line != "" {
+        line += " "
+      }
+      line += word
+    }
+    lines = append(lines, line)
+  }
+  return strings.Join(lines, "\n")
+}
+
+// FormatAlertText returns the full text of an alert, with the literal
+// "\n" sequences the API sometimes sends turned into line breaks, and
+// wrapped at 80 columns
+func FormatAlertText(alert *Alerts) string {
+  text := strings.Replace(alert.Message, `\n`, "\n", -1)
+  return strings.TrimRight(wrapText(text, 80), "\n")
 }
 
 // printAlerts prints the alerts for a given station to standard out
 func PrintAlerts(obs *Conditions, stationId string) {
   if len(obs.Alerts) == 0 {
     fmt.Println("No active alerts")
+  } else if doalertdetail {
+    var buf bytes.Buffer
+    fmt.Fprintf(&buf, "Station: %s\n", stationId)
+    for _, a := range obs.Alerts {
+      printAlertDetail(&buf, &a)
+    }
+    if dopager {
+      Page(buf.String())
+    } else {
+      fmt.Print(buf.String())
+    }
   } else {
     fmt.Printf("Station: %s\n", stationId)
     for _, a := range obs.Alerts {
@@ -51,3 +105,47 @@ func PrintAlerts(obs *Conditions, stationId string) {
     }
   }
 }
+
+// printAlertDetail writes everything the API says about an alert to w
+func printAlertDetail(w io.Writer, a *Alerts) {
+  fmt.Fprintf(w, "### %s ###\n\n", a.Description)
+  fmt.Fprintf(w, "Issued at %s\nExpires at %s\n", a.Date, a.Expires)
+  if a.Message_id != "" {
+    fmt.Fprintln(w, "Message ID:", a.Message_id)
+  }
+  if a.Phenomena != "" {
+    fmt.Fprintf(w, "Type: %s (phenomena %s, significance %s)\n", a.Type, a.Phenomena, a.Significance)
+  }
+  if len(a.Zones) > 0 {
+    zones := make([]string, len(a.Zones))
+    for i, z := range a.Zones {
+      zones[i] = z.State + " " + z.Zone
+    }
+    fmt.Fprintln(w, "Zones:", strings.Join(zones, ", "))
+  }
+  fmt.Fprintf(w, "\n%s\n\n", FormatAlertText(a))
+}
+
+// pagerCommand returns the command to page output through: $PAGER, or
+// less if that isn't set
+func pagerCommand() string {
+  if pager := os.Getenv("PAGER"); pager != "" {
+    return pager
+  }
+  return "less"
+}
+
+// Page shows text through the pager, or prints it as is if the pager
+// can't be run
+func Page(text string) {
+  cmd := exec.Command("sh", "-c", pagerCommand())
+  cmd.Stdin = strings.NewReader(text)
+  cmd.Stdout = os.Stdout
+  cmd.Stderr = os.Stderr
+  if err := cmd.Run(); err != nil {
+    if verbose {
+      fmt.Fprintf(os.Stderr, "-pager %q: %v\n", pagerCommand(), err)
+    }
+    fmt.Print(text)
+  }
+}
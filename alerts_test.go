@@ -0,0 +1,91 @@
+/*
+* alerts_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for alerts (alerts.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:31 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "strings"
+  "testing"
+)
+
+func TestFormatAlertText(t *testing.T) {
+  alert := Alerts{Message: `...HEAT ADVISORY IN EFFECT...\nThe National Weather Service has issued a heat advisory.\n\nPRECAUTIONARY ACTIONS...\nDrink plenty of fluids.`}
+  got := FormatAlertText(&alert)
+  if strings.Contains(got, `\n`) {
+    t.Errorf("FormatAlertText left literal \\n sequences:\n%s", got)
+  }
+  lines := strings.Split(got, "\n")
+  want := []string{
+    "...HEAT ADVISORY IN EFFECT...",
+    "The National Weather Service has issued a heat advisory.",
+    "",
+    "PRECAUTIONARY ACTIONS...",
+    "Drink plenty of fluids.",
+  }
+  if len(lines) != len(want) {
+    t.Fatalf("FormatAlertText = %d lines, want %d:\n%s", len(lines), len(want), got)
+  }
+  for i := range want {
+    if lines[i] != want[i] {
+      t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+    }
+  }
+}
+
+func TestFormatAlertTextWraps(t *testing.T) {
+  alert := Alerts{Message: strings.Repeat("advisory ", 40)}
+  for _, line := range strings.Split(FormatAlertText(&alert), "\n") {
+    if len(line) > 80 {
+      t.Errorf("line is %d columns: %q", len(line), line)
+    }
+  }
+}
+
+func TestPrintAlertsPager(t *testing.T) {
+  saved, savedPager := doalertdetail, dopager
+  t.Cleanup(func() { doalertdetail, dopager = saved, savedPager })
+  doalertdetail, dopager = true, true
+  t.Setenv("PAGER", "tr a-z A-Z")
+  obs := Conditions{Alerts: []Alerts{{Description: "Heat Advisory", Message: `Stay cool.\nDrink water.`}}}
+  out := captureStdout(t, func() { PrintAlerts(&obs, "KLNK") })
+  if !strings.Contains(out, "### HEAT ADVISORY ###") || !strings.Contains(out, "STAY COOL.\nDRINK WATER.") {
+    t.Errorf("alert detail didn't go through $PAGER:\n%s", out)
+  }
+}
+
+func TestPrintAlertsPagerMissing(t *testing.T) {
+  saved, savedPager := doalertdetail, dopager
+  t.Cleanup(func() { doalertdetail, dopager = saved, savedPager })
+  doalertdetail, dopager = true, true
+  t.Setenv("PAGER", "exit 127")
+  obs := Conditions{Alerts: []Alerts{{Description: "Heat Advisory", Message: "Stay cool."}}}
+  out := captureStdout(t, func() { PrintAlerts(&obs, "KLNK") })
+  if !strings.Contains(out, "### Heat Advisory ###") {
+    t.Errorf("alert detail wasn't printed when the pager failed:\n%s", out)
+  }
+}
@@ -0,0 +1,110 @@
+/*
+* aliases.go
+*
+* This file is part of wu.  It contains functions related to
+* station aliases ($HOME/.config/wu/stations.json) and the
+* --add-alias switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 15:02:11 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "regexp"
+)
+
+var (
+  zipPattern    = regexp.MustCompile(`^\d{5}(-\d{4})?$|^[A-Za-z]\d[A-Za-z] ?\d[A-Za-z]\d$`)
+  latLonPattern = regexp.MustCompile(`^-?\d+(\.\d+)?, ?-?\d+(\.\d+)?$`)
+  icaoPattern   = regexp.MustCompile(`^[A-Z]{3,4}$`)
+  pwsPattern    = regexp.MustCompile(`^[A-Z]{4,8}\d+$`)
+)
+
+// isPWSID reports whether s is a personal weather station ID (e.g.
+// KCASANFR70 or IGERMBER42).  Weather Underground builds these from a
+// country prefix (K in the US, I elsewhere), a two-letter state or
+// country code, up to five letters of the city, and a number; so
+// KCASANFR70 is station 70 in San Francisco, CA, and an ID has four
+// to eight letters.
+func isPWSID(s string) bool {
+  return pwsPattern.MatchString(s)
+}
+
+// AliasFile returns the location of the station alias database
+func AliasFile() string {
+  return os.Getenv("HOME") + "/.config/wu/stations.json"
+}
+
+// LoadStationAliases reads the alias database at path.  A missing
+// file is not an error; it simply yields no aliases.
+func LoadStationAliases(path string) (map[string]string, error) {
+  aliases := make(map[string]string)
+  b, err := ioutil.ReadFile(path)
+  if os.IsNotExist(err) {
+    return aliases, nil
+  } else if err != nil {
+    return nil, err
+  }
+  if err := json.Unmarshal(b, &aliases); err != nil {
+    return nil, err
+  }
+  return aliases, nil
+}
+
+// AddStationAlias records name as an alias for station in the alias
+// database at path, creating the file (and its directory) if needed.
+func AddStationAlias(path, name, station string) error {
+  aliases, err := LoadStationAliases(path)
+  if err != nil {
+    return err
+  }
+  aliases[name] = station
+  b, err := json.MarshalIndent(aliases, "", "  ")
+  if err != nil {
+    return err
+  }
+  if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+    return err
+  }
+  return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// ResolveStation replaces station with its alias (if one is defined)
+// unless it already looks like a zip code, lat/long pair, airport
+// code, or PWS ID.
+func ResolveStation(station string) string {
+  if zipPattern.MatchString(station) || latLonPattern.MatchString(station) ||
+    icaoPattern.MatchString(station) || isPWSID(station) {
+    return station
+  }
+  aliases, err := LoadStationAliases(AliasFile())
+  CheckError(Classify(ConfigMissing, err))
+  if s, ok := aliases[station]; ok {
+    return s
+  }
+  return station
+}
@@ -0,0 +1,142 @@
+/*
+* aliases_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for station aliases (aliases.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:15:41 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "testing"
+)
+
+func TestResolveStationAlias(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  dir := filepath.Join(home, ".config", "wu")
+  if err := os.MkdirAll(dir, 0755); err != nil {
+    t.Fatal(err)
+  }
+  fixture := `{"home": "KLNK", "work": "KOMA", "cabin": "40.5,-105.2"}`
+  if err := ioutil.WriteFile(filepath.Join(dir, "stations.json"), []byte(fixture), 0644); err != nil {
+    t.Fatal(err)
+  }
+  tests := []struct {
+    station, want string
+  }{
+    {"home", "KLNK"},
+    {"cabin", "40.5,-105.2"},
+    {"nowhere", "nowhere"},
+    {"KOMA", "KOMA"},
+  }
+  for _, tt := range tests {
+    if got := ResolveStation(tt.station); got != tt.want {
+      t.Errorf("ResolveStation(%q) = %q, want %q", tt.station, got, tt.want)
+    }
+  }
+}
+
+func TestLoadStationAliasesMissing(t *testing.T) {
+  aliases, err := LoadStationAliases(filepath.Join(t.TempDir(), "stations.json"))
+  if err != nil || len(aliases) != 0 {
+    t.Errorf("LoadStationAliases(missing) = %v, %v; want no aliases and no error", aliases, err)
+  }
+}
+
+func TestAddStationAlias(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "wu", "stations.json")
+  if err := AddStationAlias(path, "home", "KLNK"); err != nil {
+    t.Fatal(err)
+  }
+  if err := AddStationAlias(path, "work", "KOMA"); err != nil {
+    t.Fatal(err)
+  }
+  aliases, err := LoadStationAliases(path)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if aliases["home"] != "KLNK" || aliases["work"] != "KOMA" || len(aliases) != 2 {
+    t.Errorf("aliases = %v, want home: KLNK and work: KOMA", aliases)
+  }
+}
+
+func TestIsPWSID(t *testing.T) {
+  tests := []struct {
+    s    string
+    want bool
+  }{
+    {"KCASANFR70", true},
+    {"IGERMBER42", true},
+    {"KNYBROOK1", true},
+    {"KCAS1", true},          // a one-letter city
+    {"KCASANFRA70", false},   // nine letters: longer than any city allows
+    {"IGBLONDON1234", false}, // likewise
+    {"KLNK", false},
+    {"EGLL", false},
+    {"68508", false},
+    {"Lincoln, NE", false},
+    {"kcasanfr70", false},
+    {"KCASANFR", false},
+  }
+  for _, tt := range tests {
+    if got := isPWSID(tt.s); got != tt.want {
+      t.Errorf("isPWSID(%q) = %v, want %v", tt.s, got, tt.want)
+    }
+  }
+}
+
+func TestStationPath(t *testing.T) {
+  tests := []struct {
+    station string
+    want    string
+  }{
+    {"KLNK", "KLNK"},                 // ICAO
+    {"IGERMBER42", "IGERMBER42"},     // PWS
+    {"KCASANFR70", "KCASANFR70"},     // PWS
+    {"pws:KCASANFR70", "pws:KCASANFR70"},
+    {"68508", "68508"},               // zip code
+    {"40.81,-96.70", "40.81,-96.70"}, // coordinates
+    {"Lincoln, NE", "NE/Lincoln"},    // city and state
+    {"New York, NY", "NY/New_York"},
+    {"Santa Fe, New Mexico", "NM/Santa_Fe"},
+  }
+  for _, tt := range tests {
+    if got := StationPath(tt.station); got != tt.want {
+      t.Errorf("StationPath(%q) = %q, want %q", tt.station, got, tt.want)
+    }
+  }
+}
+
+func TestPWSNote(t *testing.T) {
+  if got := pwsNote("IGERMBER42"); got != " (Personal Weather Station)" {
+    t.Errorf("pwsNote(IGERMBER42) = %q", got)
+  }
+  if got := pwsNote("KLNK"); got != "" {
+    t.Errorf("pwsNote(KLNK) = %q", got)
+  }
+}
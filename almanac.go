@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:10:51 CST 2013
+* Last Modified: Wed Oct 14 16:40:12 CDT 2026
 *
 * Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
 *
@@ -30,37 +30,44 @@ package main
 
 import (
   "fmt"
+  "io"
+  "math"
+  "os"
+  "strconv"
+  "text/tabwriter"
+  "time"
 )
 
 type Almanac struct {
-  Temp_high Temp_high
-  Temp_low  Temp_low
+  Airport_code string    `json:"airport_code"`
+  Temp_high    Temp_high `json:"temp_high"`
+  Temp_low     Temp_low  `json:"temp_low"`
 }
 
 type Temp_high struct {
-  Normal     Normal
-  Record     Record
-  Recordyear string
+  Normal     Normal `json:"normal"`
+  Record     Record `json:"record"`
+  Recordyear string `json:"recordyear"`
 }
 
 type Temp_low struct {
-  Normal     Normal
-  Record     Record
-  Recordyear string
+  Normal     Normal `json:"normal"`
+  Record     Record `json:"record"`
+  Recordyear string `json:"recordyear"`
 }
 
 type Normal struct {
-  F string
-  C string
+  F string `json:"F"`
+  C string `json:"C"`
 }
 
 type Record struct {
-  F string
-  C string
+  F string `json:"F"`
+  C string `json:"C"`
 }
 
 // printAlmanac prints the Almanac for a given station to standard out
-func PrintAlmanac(obs *Conditions, stationId string) {
+func PrintAlmanac(obs *Conditions, stationId string, units *Units) {
 
   normalHighF := obs.Almanac.Temp_high.Normal.F
   normalHighC := obs.Almanac.Temp_high.Normal.C
@@ -74,9 +81,187 @@ func PrintAlmanac(obs *Conditions, stationId string) {
   recordLowC := obs.Almanac.Temp_low.Record.C
   recordLYear := obs.Almanac.Temp_low.Recordyear
 
-  fmt.Printf("Normal high: %s\u00B0 F (%s\u00B0 C)\n", normalHighF, normalHighC)
-  fmt.Printf("Record high: %s\u00B0 F (%s\u00B0 C) [%s]\n", recordHighF, recordHighC, recordHYear)
-  fmt.Printf("Normal low : %s\u00B0 F (%s\u00B0 C)\n", normalLowF, normalLowC)
-  fmt.Printf("Record low : %s\u00B0 F (%s\u00B0 C) [%s]\n", recordLowF, recordLowC, recordLYear)
+  fmt.Printf("Normal high: %s\n", units.Temp(normalHighF, normalHighC))
+  fmt.Printf("Record high: %s [%s]\n", units.Temp(recordHighF, recordHighC), recordHYear)
+  fmt.Printf("Normal low : %s\n", units.Temp(normalLowF, normalLowC))
+  fmt.Printf("Record low : %s [%s]\n", units.Temp(recordLowF, recordLowC), recordLYear)
 
+  if doalmanacdetail {
+    fmt.Printf("\nRecords from: %s\n", obs.Almanac.Airport_code)
+    fmt.Printf("Record high: %s\n", recordDetail(recordHighF, normalHighF, recordHYear, units))
+    fmt.Printf("Record low : %s\n", recordDetail(recordLowF, normalLowF, recordLYear, units))
+  }
+
+}
+
+// recordDetail describes how far a record (in Fahrenheit) is from the
+// normal, in the units requested, and how long it has stood
+func recordDetail(recordF, normalF, year string, units *Units) string {
+  r, err1 := strconv.Atoi(recordF)
+  n, err2 := strconv.Atoi(normalF)
+  y, err3 := strconv.Atoi(year)
+  if err1 != nil || err2 != nil || err3 != nil {
+    return "unavailable"
+  }
+  side := "above"
+  diff := r - n
+  if diff < 0 {
+    side, diff = "below", -diff
+  }
+  return fmt.Sprintf("%s %s normal, set %d years ago", units.Difference(float64(diff), 0), side, time.Now().Year()-y)
+}
+
+// TempAnomaly is how far the current temperature is from the normal
+// high and low for the date (in Fahrenheit)
+type TempAnomaly struct {
+  HighDelta float64 `json:"high_delta"`
+  LowDelta  float64 `json:"low_delta"`
+}
+
+// ComputeAnomaly compares the current temperature with the almanac's
+// normal high and low, and returns false if any is unavailable
+func ComputeAnomaly(current *Current, almanac *Almanac) (TempAnomaly, bool) {
+  temp, ok := current.Temp_f.Float()
+  high, err1 := strconv.ParseFloat(almanac.Temp_high.Normal.F, 64)
+  low, err2 := strconv.ParseFloat(almanac.Temp_low.Normal.F, 64)
+  if !ok || err1 != nil || err2 != nil {
+    return TempAnomaly{}, false
+  }
+  round := func(f float64) float64 { return math.Round(f*10) / 10 }
+  return TempAnomaly{round(temp - high), round(temp - low)}, true
+}
+
+// colorEnabled reports whether w is a terminal (and $NO_COLOR isn't
+// set), so that ANSI colors will display rather than clutter a file
+func colorEnabled(w io.Writer) bool {
+  f, ok := w.(*os.File)
+  if !ok || os.Getenv("NO_COLOR") != "" {
+    return false
+  }
+  fi, err := f.Stat()
+  return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// describeAnomaly describes delta degrees (F) from the normal named by
+// which, in the units requested and in bold red (warmer) or blue
+// (colder) if color is set
+func describeAnomaly(delta float64, which string, units *Units, color bool) string {
+  var s, c string
+  switch {
+  case delta > 0.05:
+    s, c = fmt.Sprintf("%s above normal %s", signedDifference(delta, 1, units), which), "\x1b[1;31m"
+  case delta < -0.05:
+    s, c = fmt.Sprintf("%s below normal %s", units.Difference(math.Abs(delta), 1), which), "\x1b[1;34m"
+  default:
+    return "at the normal " + which
+  }
+  if color {
+    return c + s + colorReset
+  }
+  return s
+}
+
+// PrintAnomaly prints how far the current temperature is from the
+// normal high and low
+func PrintAnomaly(obs *Conditions, units *Units, w io.Writer) {
+  a, ok := ComputeAnomaly(&obs.Current_observation, &obs.Almanac)
+  if !ok {
+    fmt.Fprintln(w, "Temperature anomaly unavailable.")
+    return
+  }
+  color := colorEnabled(w)
+  fmt.Fprintf(w, "Temperature anomaly: %s, %s.\n",
+    describeAnomaly(a.HighDelta, "high", units, color), describeAnomaly(a.LowDelta, "low", units, color))
+}
+
+// Comparison is how yesterday's high and low (F) compared with the
+// normals for the date
+type Comparison struct {
+  High       float64 `json:"high"`
+  Low        float64 `json:"low"`
+  NormalHigh float64 `json:"normal_high"`
+  NormalLow  float64 `json:"normal_low"`
+  HighDelta  float64 `json:"high_delta"`
+  LowDelta   float64 `json:"low_delta"`
+}
+
+// CompareYesterdayToNormal compares yesterday's high and low with the
+// almanac's normals, and returns false if any is unavailable.  The
+// almanac only has normals for today, so this compares against the
+// normals a day later, across a month or year boundary if need be;
+// they change too slowly from day to day for that to matter.
+func CompareYesterdayToNormal(yesterday *History, almanac *Almanac) (Comparison, bool) {
+  if len(yesterday.Dailysummary) == 0 {
+    return Comparison{}, false
+  }
+  s := yesterday.Dailysummary[0]
+  high, err1 := strconv.ParseFloat(s.Maxtempi, 64)
+  low, err2 := strconv.ParseFloat(s.Mintempi, 64)
+  normalHigh, err3 := strconv.ParseFloat(almanac.Temp_high.Normal.F, 64)
+  normalLow, err4 := strconv.ParseFloat(almanac.Temp_low.Normal.F, 64)
+  if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+    return Comparison{}, false
+  }
+  return Comparison{high, low, normalHigh, normalLow, high - normalHigh, low - normalLow}, true
+}
+
+// describeDeparture says how far value was from normal, e.g. "was
+// 12 F above the historical average of 73 F"
+func describeDeparture(delta, normal float64, units *Units) string {
+  switch delta = math.Round(delta); {
+  case delta > 0:
+    return fmt.Sprintf("was %s above the historical average of %s", units.Difference(delta, 0), units.Degrees(normal, 0))
+  case delta < 0:
+    return fmt.Sprintf("was %s below the historical average of %s", units.Difference(-delta, 0), units.Degrees(normal, 0))
+  }
+  return fmt.Sprintf("was right at the historical average of %s", units.Degrees(normal, 0))
+}
+
+// PrintYesterdayVsNormal prints how yesterday's high and low compared
+// with the normals for the date
+func PrintYesterdayVsNormal(obs *Conditions, units *Units, w io.Writer) {
+  c, ok := CompareYesterdayToNormal(&obs.History, &obs.Almanac)
+  if !ok {
+    fmt.Fprintln(w, "No comparison with the normals is available for yesterday.")
+    return
+  }
+  fmt.Fprintf(w, "Yesterday's high of %s %s for this date.\n", units.Degrees(c.High, 0), describeDeparture(c.HighDelta, c.NormalHigh, units))
+  fmt.Fprintf(w, "Yesterday's low of %s %s for this date.\n", units.Degrees(c.Low, 0), describeDeparture(c.LowDelta, c.NormalLow, units))
+  if s := obs.History.Dailysummary[0]; s.Precipi != "" {
+    fmt.Fprintf(w, "Yesterday's precipitation was %s (the almanac has no normal for precipitation).\n", units.Precip(s.Precipi, s.Precipm))
+  }
+}
+
+// PrintConditionsWithHistory prints the current conditions beside the
+// normals for the date and the difference.  The almanac has normals
+// only for temperature; the other rows show what was observed.
+func PrintConditionsWithHistory(current *Current, almanac *Almanac, units *Units, w io.Writer) {
+  color := colorEnabled(w)
+  fmt.Fprintf(w, "Current conditions and normals at %s (%s)\n",
+    current.Observation_location.Full, current.Station_id)
+  tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+  fmt.Fprintln(tw, "   \tObserved\tNormal\tDeviation")
+  temp, ok := current.Temp_f.Float()
+  high, err1 := strconv.ParseFloat(almanac.Temp_high.Normal.F, 64)
+  low, err2 := strconv.ParseFloat(almanac.Temp_low.Normal.F, 64)
+  row := func(label string, normal float64) {
+    delta := math.Round((temp-normal)*10) / 10
+    d := signedDifference(delta, 1, units)
+    if color && delta > 0 {
+      d = colorRed + d + colorReset
+    } else if color && delta < 0 {
+      d = colorBlue + d + colorReset
+    }
+    fmt.Fprintf(tw, "   %s:\t%s\t%s\t%s\n", label,
+      units.Temp(string(current.Temp_f), string(current.Temp_c)), units.Degrees(normal, 0), d)
+  }
+  if ok && err1 == nil && err2 == nil {
+    row("Temperature (high)", high)
+    row("Temperature (low)", low)
+    row("Temperature (mean)", (high+low)/2)
+  }
+  fmt.Fprintf(tw, "   Precipitation:\t%s\tn/a\n", units.Precip(string(current.Precip_today_in), string(current.Precip_today_metric)))
+  fmt.Fprintf(tw, "   Humidity:\t%s\tn/a\n", units.Number(current.Relative_humidity))
+  fmt.Fprintf(tw, "   Wind:\t%s\tn/a\n", units.WindDescription(current))
+  tw.Flush()
 }
@@ -0,0 +1,242 @@
+/*
+* almanac_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the -almanac switches (almanac.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:15:47 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "regexp"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestAlmanacUnmarshal(t *testing.T) {
+  fixture := `{"almanac": {"airport_code": "KLNK",
+    "temp_high": {"normal": {"F": "75", "C": "24"}, "record": {"F": "98", "C": "37"}, "recordyear": "1936"},
+    "temp_low": {"normal": {"F": "52", "C": "11"}, "record": {"F": "35", "C": "2"}, "recordyear": "1974"}}}`
+  var obs Conditions
+  if err := json.Unmarshal([]byte(fixture), &obs); err != nil {
+    t.Fatal(err)
+  }
+  a := obs.Almanac
+  fields := map[string]string{
+    "airport_code":               a.Airport_code,
+    "temp_high.normal.F":   a.Temp_high.Normal.F,
+    "temp_high.normal.C":   a.Temp_high.Normal.C,
+    "temp_high.record.F":   a.Temp_high.Record.F,
+    "temp_high.record.C":   a.Temp_high.Record.C,
+    "temp_high.recordyear": a.Temp_high.Recordyear,
+    "temp_low.normal.F":     a.Temp_low.Normal.F,
+    "temp_low.normal.C":     a.Temp_low.Normal.C,
+    "temp_low.record.F":     a.Temp_low.Record.F,
+    "temp_low.record.C":     a.Temp_low.Record.C,
+    "temp_low.recordyear":  a.Temp_low.Recordyear,
+  }
+  for name, value := range fields {
+    if value == "" {
+      t.Errorf("%s wasn't populated", name)
+    }
+  }
+  if a.Temp_high.Normal.F != "75" || a.Temp_high.Record.C != "37" || a.Temp_low.Recordyear != "1974" {
+    t.Errorf("almanac = %+v", a)
+  }
+}
+
+func TestRecordDetail(t *testing.T) {
+  set := fmt.Sprintf("set %d years ago", time.Now().Year()-1936)
+  tests := []struct {
+    recordF, normalF, year string
+    temperature            string
+    want                   string
+  }{
+    {"98", "80", "1936", "f", "18 F above normal, " + set},
+    {"98", "80", "1936", "c", "10 C above normal, " + set},
+    {"98", "80", "1936", "k", "10 K above normal, " + set},
+    {"98", "80", "1936", "", "18 F (10 C) above normal, " + set},
+    {"-4", "14", "1936", "f", "18 F below normal, " + set},
+    {"98", "", "1936", "f", "unavailable"},
+    {"98", "80", "", "f", "unavailable"},
+  }
+  for _, tt := range tests {
+    got := recordDetail(tt.recordF, tt.normalF, tt.year, &Units{Temperature: tt.temperature})
+    if got != tt.want {
+      t.Errorf("recordDetail(%q, %q, %q) in %q = %q, want %q", tt.recordF, tt.normalF, tt.year, tt.temperature, got, tt.want)
+    }
+    if tt.temperature == "c" && strings.Contains(got, "F") {
+      t.Errorf("recordDetail in Celsius mentions Fahrenheit: %q", got)
+    }
+  }
+}
+
+func TestComputeAnomaly(t *testing.T) {
+  almanac := Almanac{Temp_high: Temp_high{Normal: Normal{F: "75"}}, Temp_low: Temp_low{Normal: Normal{F: "55"}}}
+  tests := []struct {
+    temp string
+    want TempAnomaly
+    ok   bool
+  }{
+    {"83.3", TempAnomaly{8.3, 28.3}, true},
+    {"50.0", TempAnomaly{-25, -5}, true},
+    {"75", TempAnomaly{0, 20}, true},
+    {"55", TempAnomaly{-20, 0}, true},
+    {"", TempAnomaly{}, false},
+  }
+  for _, tt := range tests {
+    got, ok := ComputeAnomaly(&Current{Temp_f: Value(tt.temp)}, &almanac)
+    if got != tt.want || ok != tt.ok {
+      t.Errorf("ComputeAnomaly(%q) = %+v, %v; want %+v, %v", tt.temp, got, ok, tt.want, tt.ok)
+    }
+  }
+  if _, ok := ComputeAnomaly(&Current{Temp_f: "70"}, &Almanac{}); ok {
+    t.Error("ComputeAnomaly without normals succeeded")
+  }
+}
+
+func TestDescribeAnomaly(t *testing.T) {
+  tests := []struct {
+    delta       float64
+    temperature string
+    color       bool
+    want        string
+  }{
+    {8.3, "f", false, "+8.3 F above normal high"},
+    {-2.1, "f", false, "2.1 F below normal high"},
+    {0, "f", false, "at the normal high"},
+    {0.04, "f", false, "at the normal high"},
+    {8.3, "f", true, "\x1b[1;31m+8.3 F above normal high" + colorReset},
+    {-2.1, "f", true, "\x1b[1;34m2.1 F below normal high" + colorReset},
+    {0, "f", true, "at the normal high"},
+    {9, "c", false, "+5.0 C above normal high"},
+    {-1.8, "c", false, "1.0 C below normal high"},
+    {9, "", false, "+9.0 F (+5.0 C) above normal high"},
+  }
+  for _, tt := range tests {
+    if got := describeAnomaly(tt.delta, "high", &Units{Temperature: tt.temperature}, tt.color); got != tt.want {
+      t.Errorf("describeAnomaly(%v in %q, color %v) = %q, want %q", tt.delta, tt.temperature, tt.color, got, tt.want)
+    }
+  }
+}
+
+func TestPrintAnomaly(t *testing.T) {
+  var obs Conditions
+  obs.Current_observation.Temp_f = "83.3"
+  obs.Almanac = Almanac{Temp_high: Temp_high{Normal: Normal{F: "75"}}, Temp_low: Temp_low{Normal: Normal{F: "85.4"}}}
+  var buf bytes.Buffer
+  PrintAnomaly(&obs, &Units{Temperature: "f"}, &buf)
+  if want := "Temperature anomaly: +8.3 F above normal high, 2.1 F below normal low.\n"; buf.String() != want {
+    t.Errorf("PrintAnomaly = %q, want %q", buf.String(), want)
+  }
+}
+
+func TestPrintConditionsWithHistory(t *testing.T) {
+  obs := wideFixture()
+  almanac := Almanac{Temp_high: Temp_high{Normal: Normal{F: "80"}}, Temp_low: Temp_low{Normal: Normal{F: "60"}}}
+  var buf bytes.Buffer
+  PrintConditionsWithHistory(&obs.Current_observation, &almanac, &Units{Temperature: "f"}, &buf)
+  out := buf.String()
+  for _, want := range []string{"72.3 F", "80 F", "-7.7 F", "+12.3 F", "+2.3 F", "Precipitation:", "Humidity:", "Wind:"} {
+    if !strings.Contains(out, want) {
+      t.Errorf("output missing %q:\n%s", want, out)
+    }
+  }
+  if strings.Contains(out, "\x1b[") {
+    t.Errorf("output not to a terminal is colored:\n%q", out)
+  }
+}
+
+func TestPrintConditionsWithHistoryMetric(t *testing.T) {
+  obs := wideFixture()
+  obs.Current_observation.Precip_today_in, obs.Current_observation.Precip_today_metric = "0.10", "2.5"
+  obs.Current_observation.Wind_kph = "19.3"
+  almanac := Almanac{Temp_high: Temp_high{Normal: Normal{F: "80"}}, Temp_low: Temp_low{Normal: Normal{F: "60"}}}
+  var buf bytes.Buffer
+  PrintConditionsWithHistory(&obs.Current_observation, &almanac, &Units{Temperature: "c", Precipitation: "mm", System: MetricOnly}, &buf)
+  out := buf.String()
+  for _, want := range []string{"22.4 C", "27 C", "-4.3 C", "+6.8 C", "2.5 mm", "19.3 km/h"} {
+    if !strings.Contains(out, want) {
+      t.Errorf("metric output missing %q:\n%s", want, out)
+    }
+  }
+  if imperial := regexp.MustCompile(`\d (F|in)\b|mph`).FindString(out); imperial != "" {
+    t.Errorf("metric output mentions %q:\n%s", imperial, out)
+  }
+}
+
+func TestPrintYesterdayVsNormal(t *testing.T) {
+  var obs Conditions
+  obs.History.Dailysummary = []Dailysummary{{Maxtempi: "85", Mintempi: "52", Precipi: "0.10", Precipm: "2.5"}}
+  obs.Almanac.Temp_high.Normal.F = "73"
+  obs.Almanac.Temp_low.Normal.F = "52"
+
+  tests := []struct {
+    units Units
+    want  []string
+  }{
+    {NewUnits(ImperialOnly), []string{
+      "Yesterday's high of 85 F was 12 F above the historical average of 73 F for this date.",
+      "Yesterday's low of 52 F was right at the historical average of 52 F for this date.",
+      "Yesterday's precipitation was 0.10 in ",
+    }},
+    {NewUnits(MetricOnly), []string{
+      "Yesterday's high of 29 C was 7 C above the historical average of 23 C for this date.",
+      "Yesterday's low of 11 C was right at the historical average of 11 C for this date.",
+      "Yesterday's precipitation was 2.5 mm ",
+    }},
+    {Units{}, []string{"was 12 F (7 C) above the historical average of 73 F (23 C)"}},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintYesterdayVsNormal(&obs, &tt.units, &buf)
+    for _, want := range tt.want {
+      if !strings.Contains(buf.String(), want) {
+        t.Errorf("%+v: got %q, want it to contain %q", tt.units, buf.String(), want)
+      }
+    }
+  }
+}
+
+func TestPrintYesterdayVsNormalBelow(t *testing.T) {
+  var obs Conditions
+  obs.History.Dailysummary = []Dailysummary{{Maxtempi: "60", Mintempi: "40"}}
+  obs.Almanac.Temp_high.Normal.F = "73"
+  obs.Almanac.Temp_low.Normal.F = "52"
+  u := NewUnits(ImperialOnly)
+  var buf bytes.Buffer
+  PrintYesterdayVsNormal(&obs, &u, &buf)
+  for _, want := range []string{"was 13 F below the historical average of 73 F", "was 12 F below the historical average of 52 F"} {
+    if !strings.Contains(buf.String(), want) {
+      t.Errorf("got %q, want it to contain %q", buf.String(), want)
+    }
+  }
+  if strings.Contains(buf.String(), "precipitation") {
+    t.Errorf("got %q, want no precipitation line", buf.String())
+  }
+}
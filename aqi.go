@@ -0,0 +1,95 @@
+/*
+* aqi.go
+*
+* This file is part of wu.  It contains functions related to
+* the --air-quality switch (air quality index).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:41:30 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+)
+
+// AQI is the air quality index, which only some API tiers report
+type AQI struct {
+  Category  string `json:"category"`
+  Index     Value  `json:"index"`
+  Pollutant string `json:"pollutant"`
+}
+
+// aqiLevels are the US EPA's AQI categories, with the highest index in
+// each and the color used for it
+var aqiLevels = []struct {
+  max      int
+  category string
+  color    string
+}{
+  {50, "Good", "\x1b[32m"},
+  {100, "Moderate", "\x1b[33m"},
+  {150, "Unhealthy for Sensitive Groups", "\x1b[38;5;208m"},
+  {200, "Unhealthy", "\x1b[31m"},
+  {300, "Very Unhealthy", "\x1b[35m"},
+  {-1, "Hazardous", "\x1b[38;5;88m"},
+}
+
+// aqiLevel returns the index into aqiLevels for an AQI value
+func aqiLevel(index int) int {
+  for i, l := range aqiLevels {
+    if index <= l.max {
+      return i
+    }
+  }
+  return len(aqiLevels) - 1
+}
+
+// AQICategory returns the EPA category for an AQI value (e.g. "Moderate")
+func AQICategory(index int) string {
+  return aqiLevels[aqiLevel(index)].category
+}
+
+// PrintAQI prints the air quality index, colored by category when w is
+// a terminal
+func PrintAQI(aqi *AQI, w io.Writer) {
+  f, ok := aqi.Index.Float()
+  if !ok {
+    fmt.Fprintln(w, "No air quality data available (your API key may not include it).")
+    return
+  }
+  index := int(math.Round(f))
+  category := aqi.Category
+  if category == "" {
+    category = AQICategory(index)
+  }
+  if colorEnabled(w) {
+    category = aqiLevels[aqiLevel(index)].color + category + colorReset
+  }
+  fmt.Fprintf(w, "Air Quality Index: %d (%s)", index, category)
+  if aqi.Pollutant != "" {
+    fmt.Fprintf(w, ", mainly %s", aqi.Pollutant)
+  }
+  fmt.Fprintln(w)
+}
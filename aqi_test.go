@@ -0,0 +1,88 @@
+/*
+* aqi_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --air-quality (aqi.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:11:35 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "testing"
+)
+
+func TestAQICategory(t *testing.T) {
+  tests := []struct {
+    index string
+    want  string
+  }{
+    {`0`, "Good"},
+    {`50`, "Good"},
+    {`51`, "Moderate"},
+    {`"100"`, "Moderate"},
+    {`101`, "Unhealthy for Sensitive Groups"},
+    {`150`, "Unhealthy for Sensitive Groups"},
+    {`151`, "Unhealthy"},
+    {`"200"`, "Unhealthy"},
+    {`201`, "Very Unhealthy"},
+    {`300`, "Very Unhealthy"},
+    {`301`, "Hazardous"},
+    {`500`, "Hazardous"},
+  }
+  for _, tt := range tests {
+    var obs Conditions
+    if err := json.Unmarshal([]byte(`{"air_quality": {"index": `+tt.index+`, "pollutant": "PM2.5"}}`), &obs); err != nil {
+      t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    PrintAQI(&obs.Air_quality, &buf)
+    want := "Air Quality Index: " + string(obs.Air_quality.Index) + " (" + tt.want + "), mainly PM2.5\n"
+    if buf.String() != want {
+      t.Errorf("PrintAQI(%s) = %q, want %q", tt.index, buf.String(), want)
+    }
+  }
+}
+
+func TestPrintAQIMissing(t *testing.T) {
+  for _, fixture := range []string{`{}`, `{"air_quality": {}}`, `{"air_quality": {"index": ""}}`} {
+    var obs Conditions
+    if err := json.Unmarshal([]byte(fixture), &obs); err != nil {
+      t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    PrintAQI(&obs.Air_quality, &buf)
+    if want := "No air quality data available (your API key may not include it).\n"; buf.String() != want {
+      t.Errorf("PrintAQI(%s) = %q, want %q", fixture, buf.String(), want)
+    }
+  }
+  var obs Conditions
+  json.Unmarshal([]byte(`{"air_quality": {"index": 0}}`), &obs)
+  var buf bytes.Buffer
+  PrintAQI(&obs.Air_quality, &buf)
+  if want := "Air Quality Index: 0 (Good)\n"; buf.String() != want {
+    t.Errorf("PrintAQI(0) = %q, want %q", buf.String(), want)
+  }
+}
@@ -30,56 +30,248 @@ package main
 
 import (
   "fmt"
+  "io"
+  "math"
   "strconv"
+  "strings"
+  "time"
 )
 
 type Moon_phase struct {
-  PercentIlluminated string
-  AgeOfMoon          string
-  Sunrise            Sunrise
-  Sunset             Sunset
+  PercentIlluminated string  `json:"percentilluminated"`
+  AgeOfMoon          string  `json:"ageofmoon"`
+  PhaseofMoon        string  `json:"phaseofmoon"`
+  Sunrise            Sunrise `json:"sunrise"`
+  Sunset             Sunset  `json:"sunset"`
 }
 
 type Sunrise struct {
-  Hour   string
-  Minute string
+  Hour   string `json:"hour"`
+  Minute string `json:"minute"`
 }
 
 type Sunset struct {
-  Hour   string
-  Minute string
+  Hour   string `json:"hour"`
+  Minute string `json:"minute"`
 }
 
-// printAstro prints the lunar and solar informtion for a given station to standard out
-func PrintAstro(obs *Conditions, stationId string) {
-
-  var age, _ = strconv.Atoi(obs.Moon_phase.AgeOfMoon)
-  var moonDesc string
-
-  // Calculate traditional description of lunar phase
-
+// moonPhaseName returns the traditional description of the lunar
+// phase for the age of the moon in days
+func moonPhaseName(age int) string {
   switch {
   case age < 2:
-    moonDesc = "New moon"
+    return "New moon"
   case age < 6:
-    moonDesc = "Waxing crescent"
+    return "Waxing crescent"
   case age < 9:
-    moonDesc = "First quarter"
+    return "First quarter"
   case age < 13:
-    moonDesc = "Waxing gibbous"
+    return "Waxing gibbous"
   case age < 17:
-    moonDesc = "Full moon"
+    return "Full moon"
   case age < 20:
-    moonDesc = "Waning gibbous"
+    return "Waning gibbous"
   case age < 24:
-    moonDesc = "Last quarter"
+    return "Last quarter"
   case age < 28:
-    moonDesc = "Waning crescent"
+    return "Waning crescent"
   }
+  return "New moon"
+}
+
+// printAstro prints the lunar and solar informtion for a given station to standard out
+func PrintAstro(obs *Conditions, stationId string) {
+
+  var age, _ = strconv.Atoi(obs.Moon_phase.AgeOfMoon)
+  moonDesc := moonPhaseName(age)
   sr := obs.Moon_phase.Sunrise
   ss := obs.Moon_phase.Sunset
   percent := obs.Moon_phase.PercentIlluminated
   fmt.Printf("Moon Phase: %s (%s%% illuminated)\n", moonDesc, percent)
   fmt.Printf("Sunrise   : %s:%s\n", sr.Hour, sr.Minute)
   fmt.Printf("Sunset    : %s:%s\n", ss.Hour, ss.Minute)
+  if doastrodetail {
+    PrintTwilight(obs)
+  }
+}
+
+// PrintMoonIllumination prints just the percentage of the moon that is
+// illuminated (without a newline if quiet is set)
+func PrintMoonIllumination(obs *Conditions, w io.Writer, quiet bool) {
+  fmt.Fprint(w, obs.Moon_phase.PercentIlluminated)
+  if !quiet {
+    fmt.Fprintln(w)
+  }
+}
+
+// The age of the moon (in days) at each principal phase
+const (
+  synodicMonth = 29.53
+  firstQuarter = synodicMonth / 4
+  fullMoon     = synodicMonth / 2
+  lastQuarter  = synodicMonth * 3 / 4
+)
+
+// moonPhases describes each named phase: how the sentence opens, and
+// the principal phases before and after it (with their ages)
+var moonPhases = map[string]struct {
+  desc             string
+  prev, next       string
+  prevAge, nextAge float64
+}{
+  "new moon":        {"new", "", "first quarter", 0, firstQuarter},
+  "waxing crescent": {"a waxing crescent", "new moon", "first quarter", 0, firstQuarter},
+  "first quarter":   {"at first quarter", "", "full moon", firstQuarter, fullMoon},
+  "waxing gibbous":  {"a waxing gibbous", "first quarter", "full moon", firstQuarter, fullMoon},
+  "full moon":       {"full", "", "last quarter", fullMoon, lastQuarter},
+  "full":            {"full", "", "last quarter", fullMoon, lastQuarter},
+  "waning gibbous":  {"a waning gibbous", "full moon", "last quarter", fullMoon, lastQuarter},
+  "last quarter":    {"at last quarter", "", "new moon", lastQuarter, synodicMonth},
+  "third quarter":   {"at last quarter", "", "new moon", lastQuarter, synodicMonth},
+  "waning crescent": {"a waning crescent", "last quarter", "new moon", lastQuarter, synodicMonth},
+}
+
+// days renders a number of days, e.g. "1 day" or "3 days"
+func days(n int) string {
+  if n == 1 {
+    return "1 day"
+  }
+  return fmt.Sprintf("%d days", n)
+}
+
+// MoonPhaseDescription describes the phase of the moon in a sentence,
+// e.g. "The moon is a waxing gibbous, 3 days past first quarter and 4
+// days until full moon."  ageOfMoon (in days) places the moon within
+// its phase; if it's unknown (negative) the age is estimated from the
+// illumination (a fraction from 0 to 1) instead.
+func MoonPhaseDescription(phaseString string, ageOfMoon int, illumination float64) string {
+  phase := strings.ToLower(phaseString)
+  if _, ok := moonPhases[phase]; !ok {
+    if ageOfMoon < 0 {
+      return "The moon's phase is unknown."
+    }
+    phase = strings.ToLower(moonPhaseName(ageOfMoon))
+  }
+  p := moonPhases[phase]
+  age := float64(ageOfMoon)
+  if ageOfMoon < 0 {
+    // Illumination is (1 - cos(angle)) / 2, where the angle runs from
+    // 0 at new moon to 180 degrees at full
+    age = math.Acos(1-2*illumination) / math.Pi * fullMoon
+    if p.prevAge >= fullMoon {
+      age = synodicMonth - age
+    }
+  }
+  until := int(math.Max(0, math.Round(p.nextAge-age)))
+  if p.prev == "" {
+    return fmt.Sprintf("The moon is %s, %s until %s.", p.desc, days(until), p.next)
+  }
+  past := int(math.Max(0, math.Round(age-p.prevAge)))
+  return fmt.Sprintf("The moon is %s, %s past %s and %s until %s.", p.desc, days(past), p.prev, days(until), p.next)
+}
+
+// PrintMoonPhaseText prints MoonPhaseDescription for the current moon
+func PrintMoonPhaseText(obs *Conditions, w io.Writer) {
+  m := obs.Moon_phase
+  age, err := strconv.Atoi(m.AgeOfMoon)
+  if err != nil {
+    age = -1
+  }
+  illumination, _ := strconv.ParseFloat(m.PercentIlluminated, 64)
+  fmt.Fprintln(w, MoonPhaseDescription(m.PhaseofMoon, age, illumination/100))
+}
+
+// TwilightTimes holds the times at which the sun crosses the
+// elevations of interest on a given day.  A zero time means the sun
+// doesn't reach that elevation (polar day or night).
+type TwilightTimes struct {
+  SolarNoon              time.Time
+  Sunrise                time.Time
+  Sunset                 time.Time
+  CivilDawn              time.Time
+  CivilDusk              time.Time
+  NauticalDawn           time.Time
+  NauticalDusk           time.Time
+  AstronomicalDawn       time.Time
+  AstronomicalDusk       time.Time
+  GoldenHourMorningEnd   time.Time
+  GoldenHourEveningStart time.Time
+}
+
+// SolarTimes computes sunrise, sunset, twilight, and golden hour for
+// the given location and day, using the standard sunrise equation
+// (see http://en.wikipedia.org/wiki/Sunrise_equation).  Longitude is
+// positive east of Greenwich.  All times are in UTC.
+func SolarTimes(lat, lon float64, date time.Time) TwilightTimes {
+  const rad = math.Pi / 180
+
+  // Days since J2000, corrected to mean solar noon at lon
+  y, m, d := date.Date()
+  noon := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+  n := math.Floor(float64(noon.Unix())/86400 + 2440587.5 - 2451545.0 + 0.0008)
+  jstar := n - lon/360
+
+  anomaly := math.Mod(357.5291+0.98560028*jstar, 360)
+  center := 1.9148*math.Sin(anomaly*rad) + 0.02*math.Sin(2*anomaly*rad) + 0.0003*math.Sin(3*anomaly*rad)
+  ecliptic := math.Mod(anomaly+center+180+102.9372, 360)
+  transit := 2451545.0 + jstar + 0.0053*math.Sin(anomaly*rad) - 0.0069*math.Sin(2*ecliptic*rad)
+  declination := math.Asin(math.Sin(ecliptic*rad) * math.Sin(23.4397*rad))
+
+  julianToTime := func(j float64) time.Time {
+    return time.Unix(int64(math.Floor((j-2440587.5)*86400+0.5)), 0).UTC()
+  }
+
+  // crossing returns the morning and evening times at which the sun
+  // is at the given elevation
+  crossing := func(elevation float64) (time.Time, time.Time) {
+    cosH := (math.Sin(elevation*rad) - math.Sin(lat*rad)*math.Sin(declination)) /
+      (math.Cos(lat*rad) * math.Cos(declination))
+    if cosH < -1 || cosH > 1 {
+      return time.Time{}, time.Time{}
+    }
+    hourAngle := math.Acos(cosH) / rad
+    return julianToTime(transit - hourAngle/360), julianToTime(transit + hourAngle/360)
+  }
+
+  var t TwilightTimes
+  t.SolarNoon = julianToTime(transit)
+  t.Sunrise, t.Sunset = crossing(-0.833)
+  t.CivilDawn, t.CivilDusk = crossing(-6)
+  t.NauticalDawn, t.NauticalDusk = crossing(-12)
+  t.AstronomicalDawn, t.AstronomicalDusk = crossing(-18)
+  t.GoldenHourMorningEnd, t.GoldenHourEveningStart = crossing(6)
+  return t
+}
+
+// PrintTwilight prints twilight and golden hour times for the
+// station's location (which requires a geolookup)
+func PrintTwilight(obs *Conditions) {
+  lat, latErr := strconv.ParseFloat(obs.Location.Lat, 64)
+  lon, lonErr := strconv.ParseFloat(obs.Location.Lon, 64)
+  if latErr != nil || lonErr != nil {
+    fmt.Println("Twilight times unavailable (unknown station location)")
+    return
+  }
+  loc, err := time.LoadLocation(obs.Location.Tz_long)
+  if err != nil {
+    loc = time.Local
+  }
+  t := SolarTimes(lat, lon, time.Now().In(loc))
+
+  clock := func(morning, evening time.Time) string {
+    if morning.IsZero() {
+      return "none"
+    }
+    return morning.In(loc).Format("15:04") + " - " + evening.In(loc).Format("15:04")
+  }
+  fmt.Printf("Solar noon: %s\n", t.SolarNoon.In(loc).Format("15:04"))
+  fmt.Printf("Twilight  : civil %s, nautical %s, astronomical %s\n",
+    clock(t.CivilDawn, t.CivilDusk), clock(t.NauticalDawn, t.NauticalDusk),
+    clock(t.AstronomicalDawn, t.AstronomicalDusk))
+  if t.Sunrise.IsZero() || t.GoldenHourMorningEnd.IsZero() {
+    fmt.Println("Golden hr : none")
+  } else {
+    fmt.Printf("Golden hr : %s and %s\n", clock(t.Sunrise, t.GoldenHourMorningEnd),
+      clock(t.GoldenHourEveningStart, t.Sunset))
+  }
 }
@@ -0,0 +1,123 @@
+/*
+* astro_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the moon phase and solar time calculations (astro.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:06 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "testing"
+  "time"
+)
+
+// Reference times are from the US Naval Observatory's tables
+func TestSolarTimes(t *testing.T) {
+  tests := []struct {
+    name            string
+    lat, lon        float64
+    date            string
+    sunrise, sunset string
+  }{
+    {"New York summer solstice", 40.7128, -74.0060, "2023-06-21", "2023-06-21T09:25:00Z", "2023-06-22T00:31:00Z"},
+    {"London winter solstice", 51.5074, -0.1278, "2023-12-21", "2023-12-21T08:04:00Z", "2023-12-21T15:53:00Z"},
+  }
+  for _, tt := range tests {
+    date, _ := time.Parse("2006-01-02", tt.date)
+    got := SolarTimes(tt.lat, tt.lon, date)
+    for _, c := range []struct {
+      label string
+      got   time.Time
+      want  string
+    }{{"sunrise", got.Sunrise, tt.sunrise}, {"sunset", got.Sunset, tt.sunset}} {
+      want, _ := time.Parse(time.RFC3339, c.want)
+      if d := c.got.Sub(want); d < -2*time.Minute || d > 2*time.Minute {
+        t.Errorf("%s: %s = %s, want %s ±2m", tt.name, c.label, c.got.Format(time.RFC3339), c.want)
+      }
+    }
+    if !(got.AstronomicalDawn.Before(got.NauticalDawn) && got.NauticalDawn.Before(got.CivilDawn) &&
+      got.CivilDawn.Before(got.Sunrise) && got.Sunset.Before(got.CivilDusk)) {
+      t.Errorf("%s: twilight times out of order: %+v", tt.name, got)
+    }
+  }
+}
+
+func TestSolarTimesPolarNight(t *testing.T) {
+  date, _ := time.Parse("2006-01-02", "2023-12-21")
+  got := SolarTimes(78.22, 15.65, date) // Longyearbyen
+  if !got.Sunrise.IsZero() || !got.Sunset.IsZero() {
+    t.Errorf("polar night: sunrise %s, sunset %s, want none", got.Sunrise, got.Sunset)
+  }
+}
+
+func TestPrintMoonIllumination(t *testing.T) {
+  tests := []struct {
+    percent string
+    quiet   bool
+    want    string
+  }{
+    {"68", false, "68\n"},
+    {"68", true, "68"},
+    {"0", true, "0"},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    obs := &Conditions{Moon_phase: Moon_phase{PercentIlluminated: tt.percent}}
+    PrintMoonIllumination(obs, &buf, tt.quiet)
+    if buf.String() != tt.want {
+      t.Errorf("PrintMoonIllumination(%s, quiet %v) = %q, want %q", tt.percent, tt.quiet, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestMoonPhaseDescription(t *testing.T) {
+  tests := []struct {
+    phase        string
+    age          int
+    illumination float64
+    want         string
+  }{
+    {"New Moon", 0, 0, "The moon is new, 7 days until first quarter."},
+    {"Waxing Crescent", 4, 0.2, "The moon is a waxing crescent, 4 days past new moon and 3 days until first quarter."},
+    {"First Quarter", 7, 0.5, "The moon is at first quarter, 8 days until full moon."},
+    {"Waxing Gibbous", 11, 0.8, "The moon is a waxing gibbous, 4 days past first quarter and 4 days until full moon."},
+    {"Full Moon", 15, 1, "The moon is full, 7 days until last quarter."},
+    {"Waning Gibbous", 18, 0.9, "The moon is a waning gibbous, 3 days past full moon and 4 days until last quarter."},
+    {"Last Quarter", 22, 0.5, "The moon is at last quarter, 8 days until new moon."},
+    {"Waning Crescent", 26, 0.15, "The moon is a waning crescent, 4 days past last quarter and 4 days until new moon."},
+    // Without the age, it's estimated from the illumination
+    {"Waxing Gibbous", -1, 0.68, "The moon is a waxing gibbous, 2 days past first quarter and 6 days until full moon."},
+    {"Waning Gibbous", -1, 0.68, "The moon is a waning gibbous, 6 days past full moon and 2 days until last quarter."},
+    // Without a recognized phase, it's named from the age
+    {"", 11, 0.8, "The moon is a waxing gibbous, 4 days past first quarter and 4 days until full moon."},
+    {"", -1, 0.5, "The moon's phase is unknown."},
+  }
+  for _, tt := range tests {
+    if got := MoonPhaseDescription(tt.phase, tt.age, tt.illumination); got != tt.want {
+      t.Errorf("MoonPhaseDescription(%q, %d, %v) = %q, want %q", tt.phase, tt.age, tt.illumination, got, tt.want)
+    }
+  }
+}
@@ -0,0 +1,72 @@
+/*
+* badge.go
+*
+* This file is part of wu.  It contains functions related to
+* the --conditions-markdown-badge switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:44:51 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "net/url"
+  "strings"
+)
+
+// badgeColor picks a shields.io color for a temperature (F)
+func badgeColor(temp float64) string {
+  switch {
+  case temp < 50:
+    return "blue"
+  case temp > 85:
+    return "red"
+  }
+  return "orange"
+}
+
+// shieldsEscape escapes the dashes and underscores that shields.io
+// treats specially, and turns spaces into underscores
+func shieldsEscape(s string) string {
+  s = strings.NewReplacer("-", "--", "_", "__").Replace(s)
+  return url.QueryEscape(strings.Replace(s, " ", "_", -1))
+}
+
+// ShieldsBadgeURL returns the URL of a shields.io badge showing a
+// temperature (F) and condition, e.g. "Weather | 72°F Sunny"
+func ShieldsBadgeURL(temp float64, condition string) string {
+  message := fmt.Sprintf("%.0f°F %s", temp, condition)
+  return fmt.Sprintf("https://img.shields.io/badge/Weather-%s-%s?style=flat", shieldsEscape(message), badgeColor(temp))
+}
+
+// PrintMarkdownBadge prints a Markdown image of the badge for the
+// current conditions
+func PrintMarkdownBadge(current *Current, w io.Writer) {
+  temp, ok := current.Temp_f.Float()
+  if !ok {
+    fmt.Fprintln(w, "No temperature available for a badge.")
+    return
+  }
+  fmt.Fprintf(w, "![Weather](%s)\n", ShieldsBadgeURL(temp, current.Weather))
+}
@@ -0,0 +1,78 @@
+/*
+* badge_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --conditions-markdown-badge (badge.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:51 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "net/url"
+  "strings"
+  "testing"
+)
+
+func TestShieldsBadgeURL(t *testing.T) {
+  tests := []struct {
+    temp      float64
+    condition string
+    message   string
+    color     string
+  }{
+    {72, "Sunny", "72%C2%B0F_Sunny", "orange"},
+    {32.4, "Light Snow", "32%C2%B0F_Light_Snow", "blue"},
+    {98, "Partly Cloudy", "98%C2%B0F_Partly_Cloudy", "red"},
+    {50, "Rain-Snow_Mix", "50%C2%B0F_Rain--Snow__Mix", "orange"},
+    {85, "Clear", "85%C2%B0F_Clear", "orange"},
+  }
+  for _, tt := range tests {
+    got := ShieldsBadgeURL(tt.temp, tt.condition)
+    u, err := url.Parse(got)
+    if err != nil {
+      t.Errorf("ShieldsBadgeURL(%g, %q) = %q: %v", tt.temp, tt.condition, got, err)
+      continue
+    }
+    if u.Host != "img.shields.io" || u.RawQuery != "style=flat" {
+      t.Errorf("ShieldsBadgeURL(%g, %q) = %q", tt.temp, tt.condition, got)
+    }
+    if want := "/badge/Weather-" + tt.message + "-" + tt.color; u.EscapedPath() != want {
+      t.Errorf("ShieldsBadgeURL(%g, %q) path = %q, want %q", tt.temp, tt.condition, u.EscapedPath(), want)
+    }
+  }
+}
+
+func TestPrintMarkdownBadge(t *testing.T) {
+  var buf bytes.Buffer
+  PrintMarkdownBadge(&Current{Temp_f: "72.3", Weather: "Sunny"}, &buf)
+  if want := "![Weather](https://img.shields.io/badge/Weather-72%C2%B0F_Sunny-orange?style=flat)\n"; buf.String() != want {
+    t.Errorf("PrintMarkdownBadge = %q, want %q", buf.String(), want)
+  }
+  buf.Reset()
+  PrintMarkdownBadge(&Current{Temp_f: "", Weather: "Sunny"}, &buf)
+  if !strings.HasPrefix(buf.String(), "No temperature") {
+    t.Errorf("PrintMarkdownBadge without a temperature = %q", buf.String())
+  }
+}
@@ -0,0 +1,207 @@
+/*
+* bestday.go
+*
+* This file is part of wu.  It contains functions related to
+* the --forecast-best-day and --forecast-weekend-score switches
+* (scoring forecast days for an activity).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:57:12 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "sort"
+  "strings"
+)
+
+// activity describes what makes a day good for something: the ideal
+// high (F), and the weight given to the temperature, to staying dry
+// (or, with Snow, getting snow), and to calm winds
+type activity struct {
+  Name      string
+  IdealTemp float64
+  Temp      float64
+  Dry       float64
+  Snow      float64
+  Calm      float64
+}
+
+var activities = map[string]activity{
+  "outdoor":   {"outdoor activities", 75, 0.4, 0.4, 0, 0.2},
+  "cycling":   {"cycling", 65, 0.3, 0.4, 0, 0.3},
+  "gardening": {"gardening", 68, 0.5, 0.3, 0, 0.2},
+  "ski":       {"skiing", 25, 0.3, 0, 0.5, 0.2},
+}
+
+// activityNames returns the modes -forecast-best-day accepts
+func activityNames() []string {
+  names := make([]string, 0, len(activities))
+  for name := range activities {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  return names
+}
+
+// ScoreForecastDay rates a forecast day from 0 to 100 for mode (one of
+// activities): the closer the high is to ideal, the lower the chance
+// of rain (or higher the chance of snow), and the lighter the wind,
+// the better
+func ScoreForecastDay(day Simpleforecastday, mode string) float64 {
+  a, ok := activities[mode]
+  if !ok {
+    return 0
+  }
+  high, _ := day.High.Fahrenheit.Float()
+  pop, _ := day.Pop.Float()
+  wind, _ := day.Avewind.Mph.Float()
+  temp := math.Max(0, 1-math.Abs(high-a.IdealTemp)/30)
+  snow := 0.0
+  if iconCategory(day.Icon) == skySnow {
+    snow = pop / 100
+  }
+  dry := 1 - pop/100
+  calm := math.Max(0, 1-wind/30)
+  return 100 * (a.Temp*temp + a.Dry*dry + a.Snow*snow + a.Calm*calm)
+}
+
+// BestForecastDay returns the index of the highest-scoring day for
+// mode (the earliest, in a tie), or -1 if there are no days
+func BestForecastDay(days []Simpleforecastday, mode string) int {
+  best, bestScore := -1, -1.0
+  for i, d := range days {
+    if score := ScoreForecastDay(d, mode); score > bestScore {
+      best, bestScore = i, score
+    }
+  }
+  return best
+}
+
+// PrintBestDay prints the best day of the forecast for mode
+func PrintBestDay(obs *Conditions, mode string, units *Units, w io.Writer) {
+  days := obs.Forecast.Simpleforecast.Forecastday
+  i := BestForecastDay(days, mode)
+  if i < 0 {
+    fmt.Fprintln(w, "No forecast available.")
+    return
+  }
+  d := days[i]
+  precip := "rain"
+  if activities[mode].Snow > 0 {
+    precip = "snow"
+  }
+  temp, wind := string(d.High.Fahrenheit)+"°F", string(d.Avewind.Mph)+" mph"
+  if units.Metric() {
+    temp, wind = string(d.High.Celsius)+"°C", string(d.Avewind.Kph)+" km/h"
+  }
+  fmt.Fprintf(w, "Best day for %s: %s, %s (score: %.0f/100 — %s, %s%% %s, %s winds)\n",
+    activities[mode].Name, d.Date.Weekday, d.Date.Time().Format("Jan 2"), ScoreForecastDay(d, mode),
+    temp, d.Pop, precip, wind)
+}
+
+// WeekendScore rates a Saturday and Sunday together for outdoor
+// activities
+type WeekendScore struct {
+  Score       int    `json:"score"`
+  Description string `json:"description"`
+}
+
+// ScoreWeekend rates a weekend from 0 to 100: the closer the average
+// high is to 70 F, the lower the chance of rain on the wetter of the
+// two days, and the lighter the wind, the better
+func ScoreWeekend(sat, sun Simpleforecastday) WeekendScore {
+  satHigh, _ := sat.High.Fahrenheit.Float()
+  sunHigh, _ := sun.High.Fahrenheit.Float()
+  satPop, _ := sat.Pop.Float()
+  sunPop, _ := sun.Pop.Float()
+  satWind, _ := sat.Avewind.Mph.Float()
+  sunWind, _ := sun.Avewind.Mph.Float()
+  high := (satHigh + sunHigh) / 2
+  pop := math.Max(satPop, sunPop)
+  temp := math.Max(0, 1-math.Abs(high-70)/30)
+  dry := 1 - pop/100
+  calm := math.Max(0, 1-(satWind+sunWind)/2/30)
+  score := int(math.Round(100 * (0.2*temp + 0.65*dry + 0.15*calm)))
+  return WeekendScore{score, describeWeekend(high, pop, iconCategory(sat.Icon), iconCategory(sun.Icon))}
+}
+
+// describeWeekend sums up a weekend's average high, chance of rain,
+// and skies (e.g. "Warm, mostly clear")
+func describeWeekend(high, pop float64, satSky, sunSky string) string {
+  if pop >= 60 {
+    return "Rain likely"
+  }
+  var feel string
+  switch {
+  case high >= 85:
+    feel = "Hot"
+  case high >= 70:
+    feel = "Warm"
+  case high >= 55:
+    feel = "Mild"
+  case high >= 40:
+    feel = "Cool"
+  default:
+    feel = "Cold"
+  }
+  switch {
+  case pop >= 30:
+    return feel + ", chance of showers"
+  case satSky == skySunny || sunSky == skySunny:
+    return feel + ", mostly clear"
+  }
+  return feel + ", cloudy"
+}
+
+// UpcomingWeekends scores each Saturday and Sunday pair in days
+func UpcomingWeekends(days []Simpleforecastday) []WeekendScore {
+  var weekends []WeekendScore
+  for i := 0; i+1 < len(days); i++ {
+    if days[i].Date.Weekday == "Saturday" && days[i+1].Date.Weekday == "Sunday" {
+      weekends = append(weekends, ScoreWeekend(days[i], days[i+1]))
+    }
+  }
+  return weekends
+}
+
+// PrintWeekendScores prints the score for each weekend in the forecast
+func PrintWeekendScores(obs *Conditions, w io.Writer) {
+  weekends := UpcomingWeekends(obs.Forecast.Simpleforecast.Forecastday)
+  if len(weekends) == 0 {
+    fmt.Fprintln(w, "No weekend in the forecast.")
+    return
+  }
+  names := []string{"This weekend", "Next weekend"}
+  scores := make([]string, len(weekends))
+  for i, s := range weekends {
+    name := "The weekend after"
+    if i < len(names) {
+      name = names[i]
+    }
+    scores[i] = fmt.Sprintf("%s: %d/100 (%s)", name, s.Score, s.Description)
+  }
+  fmt.Fprintf(w, "Upcoming weekends: %s.\n", strings.Join(scores, " | "))
+}
@@ -0,0 +1,138 @@
+/*
+* bestday_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for -forecast-best-day (bestday.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:15:44 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "testing"
+  "time"
+)
+
+// bestDayFixture is a week in which Wednesday is a fine, calm 75F,
+// Saturday a snowy 25F, and the rest wet, windy, and middling
+func bestDayFixture() []Simpleforecastday {
+  return forecastDays(forecastMonday,
+    dayIcons("rain", "tstorms", "clear", "cloudy", "rain", "snow"), dayHighs("58", "62", "75", "55", "50", "25"),
+    dayPops("80", "70", "5", "40", "90", "90"), dayWinds("20", "18", "8", "22", "25", "6"))
+}
+
+func TestBestForecastDay(t *testing.T) {
+  days := bestDayFixture()
+  tests := []struct {
+    mode string
+    want string
+  }{
+    {"outdoor", "Wednesday"},
+    {"cycling", "Wednesday"},
+    {"gardening", "Wednesday"},
+    {"ski", "Saturday"},
+  }
+  for _, tt := range tests {
+    i := BestForecastDay(days, tt.mode)
+    if i < 0 || days[i].Date.Weekday != tt.want {
+      t.Errorf("BestForecastDay(%s) = %d, want %s", tt.mode, i, tt.want)
+    }
+  }
+  if i := BestForecastDay(nil, "outdoor"); i != -1 {
+    t.Errorf("BestForecastDay(nil) = %d, want -1", i)
+  }
+}
+
+func TestScoreForecastDay(t *testing.T) {
+  perfect := Simpleforecastday{High: Simple_temp{Fahrenheit: "75"}, Pop: "0", Avewind: Simple_wind{Mph: "0"}}
+  if got := ScoreForecastDay(perfect, "outdoor"); got != 100 {
+    t.Errorf("a perfect outdoor day scored %v, want 100", got)
+  }
+  if got := ScoreForecastDay(perfect, "kayaking"); got != 0 {
+    t.Errorf("an unknown mode scored %v, want 0", got)
+  }
+  for _, d := range bestDayFixture() {
+    for mode := range activities {
+      if s := ScoreForecastDay(d, mode); s < 0 || s > 100 {
+        t.Errorf("%s scored %v for %s", d.Date.Weekday, s, mode)
+      }
+    }
+  }
+}
+
+func TestPrintBestDay(t *testing.T) {
+  var obs Conditions
+  obs.Forecast.Simpleforecast.Forecastday = bestDayFixture()
+  var buf bytes.Buffer
+  PrintBestDay(&obs, "outdoor", &Units{Temperature: "f"}, &buf)
+  want := "Best day for outdoor activities: Wednesday, Aug 16 (score: 93/100 — 75°F, 5% rain, 8 mph winds)\n"
+  if buf.String() != want {
+    t.Errorf("PrintBestDay = %q, want %q", buf.String(), want)
+  }
+}
+
+func TestScoreWeekend(t *testing.T) {
+  saturday := time.Date(2023, 8, 19, 0, 0, 0, 0, time.UTC)
+  tests := []struct {
+    name    string
+    weekend []Simpleforecastday
+    ok      func(int) bool
+    want    string
+  }{
+    {"perfect", forecastDays(saturday, dayIcons("clear", "clear"), dayHighs("75", "75"), dayPops("5", "5"), dayWinds("5", "5")),
+      func(s int) bool { return s > 90 }, "Warm, mostly clear"},
+    {"rainy", forecastDays(saturday, dayIcons("rain", "rain"), dayHighs("55", "55"), dayPops("80", "80"), dayWinds("10", "10")),
+      func(s int) bool { return s < 40 }, "Rain likely"},
+    {"one wet day", forecastDays(saturday, dayIcons("clear", "chancerain"), dayHighs("70", "70"), dayPops("0", "40"), dayWinds("5", "5")),
+      func(s int) bool { return s < 80 }, "Warm, chance of showers"},
+  }
+  for _, tt := range tests {
+    got := ScoreWeekend(tt.weekend[0], tt.weekend[1])
+    if !tt.ok(got.Score) || got.Description != tt.want {
+      t.Errorf("%s weekend: %+v, want description %q", tt.name, got, tt.want)
+    }
+  }
+}
+
+func TestPrintWeekendScores(t *testing.T) {
+  var obs Conditions
+  obs.Forecast.Simpleforecast.Forecastday = forecastDays(time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC),
+    dayIcons("clear", "clear", "clear", "rain", "rain", "rain", "rain", "rain", "rain", "rain"),
+    dayHighs("75", "75", "75", "55", "55", "55", "55", "55", "55", "55"),
+    dayPops("5", "5", "5", "80", "80", "80", "80", "80", "80", "80"),
+    dayWinds("5", "5", "5", "10", "10", "10", "10", "10", "10", "10"))
+  var buf bytes.Buffer
+  PrintWeekendScores(&obs, &buf)
+  want := "Upcoming weekends: This weekend: 91/100 (Warm, mostly clear) | Next weekend: 33/100 (Rain likely).\n"
+  if buf.String() != want {
+    t.Errorf("got %q, want %q", buf.String(), want)
+  }
+
+  obs.Forecast.Simpleforecast.Forecastday = obs.Forecast.Simpleforecast.Forecastday[2:8]
+  buf.Reset()
+  PrintWeekendScores(&obs, &buf)
+  if want := "No weekend in the forecast.\n"; buf.String() != want {
+    t.Errorf("got %q, want %q", buf.String(), want)
+  }
+}
@@ -0,0 +1,478 @@
+/*
+* cache.go
+*
+* This file is part of wu.  It contains functions related to
+* the observation cache ($HOME/.cache/wu) and the --conditions-trend,
+* --conditions-diff-previous, and --forecast-delta switches.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 19:58:30 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "math"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// cacheAge is how long observations are kept in the cache
+const cacheAge = 24 * time.Hour
+
+// CacheDir returns the directory holding cached observations
+func CacheDir() string {
+  if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+    return filepath.Join(dir, "wu")
+  }
+  return filepath.Join(os.Getenv("HOME"), ".cache", "wu")
+}
+
+// cacheFile returns the cache file for a station
+func cacheFile(stationId string) string {
+  name := strings.NewReplacer("/", "_", " ", "_", ",", "_").Replace(stationId)
+  return filepath.Join(CacheDir(), name+".json")
+}
+
+// observationTime returns when an observation was made
+func observationTime(current *Current) (time.Time, bool) {
+  epoch, err := strconv.ParseInt(current.Observation_epoch, 10, 64)
+  return time.Unix(epoch, 0), err == nil
+}
+
+// LoadObservations reads the cached observations for a station, oldest
+// first.  A missing or unreadable cache simply yields nothing.
+func LoadObservations(stationId string) []Current {
+  var cached []Current
+  if b, err := ioutil.ReadFile(cacheFile(stationId)); err == nil {
+    json.Unmarshal(b, &cached)
+  }
+  return cached
+}
+
+// CacheObservation adds current to the station's cache, dropping
+// duplicates and anything older than cacheAge
+func CacheObservation(stationId string, current *Current) error {
+  now, ok := observationTime(current)
+  if !ok {
+    return nil
+  }
+  kept := make([]Current, 0)
+  for _, c := range LoadObservations(stationId) {
+    if t, ok := observationTime(&c); ok && now.Sub(t) < cacheAge && t.Before(now) {
+      kept = append(kept, c)
+    }
+  }
+  kept = append(kept, *current)
+  b, err := json.Marshal(kept)
+  if err != nil {
+    return err
+  }
+  if err := os.MkdirAll(CacheDir(), 0755); err != nil {
+    return err
+  }
+  return ioutil.WriteFile(cacheFile(stationId), b, 0644)
+}
+
+// Trend is the change in each measurement between two observations
+// (in Fahrenheit, percent, inches, and miles per hour).  A measurement
+// missing from either observation is reported as not ok.
+type Trend struct {
+  Elapsed    time.Duration `json:"elapsed_ns"`
+  TempF      float64       `json:"temp_f"`
+  Humidity   float64       `json:"relative_humidity"`
+  PressureIn float64       `json:"pressure_in"`
+  WindMph    float64       `json:"wind_mph"`
+  TempOk     bool          `json:"temp_ok"`
+  HumidityOk bool          `json:"humidity_ok"`
+  PressureOk bool          `json:"pressure_ok"`
+  WindOk     bool          `json:"wind_ok"`
+}
+
+// delta returns b - a (to two decimal places) if both are numbers
+func delta(a, b string) (float64, bool) {
+  x, err1 := strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+  y, err2 := strconv.ParseFloat(strings.TrimSuffix(b, "%"), 64)
+  return math.Round((y-x)*100) / 100, err1 == nil && err2 == nil
+}
+
+// ComputeTrend compares the current observation with an earlier one
+func ComputeTrend(current, historical *Current) Trend {
+  var t Trend
+  now, _ := observationTime(current)
+  then, _ := observationTime(historical)
+  t.Elapsed = now.Sub(then)
+  t.TempF, t.TempOk = delta(string(historical.Temp_f), string(current.Temp_f))
+  t.Humidity, t.HumidityOk = delta(historical.Relative_humidity, current.Relative_humidity)
+  t.PressureIn, t.PressureOk = delta(historical.Pressure_in, current.Pressure_in)
+  t.WindMph, t.WindOk = delta(string(historical.Wind_mph), string(current.Wind_mph))
+  return t
+}
+
+// TrendBaseline picks the cached observation to compare current with:
+// the latest one at least window old, or failing that the oldest
+func TrendBaseline(cached []Current, current *Current, window time.Duration) (*Current, bool) {
+  now, ok := observationTime(current)
+  if !ok {
+    return nil, false
+  }
+  var baseline *Current
+  for i := range cached {
+    t, ok := observationTime(&cached[i])
+    if !ok || !t.Before(now) {
+      continue
+    }
+    if baseline == nil {
+      baseline = &cached[i]
+    }
+    if now.Sub(t) >= window {
+      baseline = &cached[i]
+    }
+  }
+  return baseline, baseline != nil
+}
+
+// describeDuration renders d as e.g. "2 hours" or "1 hour 30 minutes"
+func describeDuration(d time.Duration) string {
+  plural := func(n int, unit string) string {
+    if n == 1 {
+      return fmt.Sprintf("%d %s", n, unit)
+    }
+    return fmt.Sprintf("%d %ss", n, unit)
+  }
+  m := int(d.Round(time.Minute) / time.Minute)
+  switch {
+  case m < 60:
+    return plural(m, "minute")
+  case m%60 == 0:
+    return plural(m/60, "hour")
+  }
+  return plural(m/60, "hour") + " " + plural(m%60, "minute")
+}
+
+// direction describes the sign of a change
+func direction(d float64) (string, string) {
+  switch {
+  case d > 0:
+    return "rising", "↑"
+  case d < 0:
+    return "falling", "↓"
+  }
+  return "steady", "→"
+}
+
+// PrintTrend prints the direction of change for each measurement
+func PrintTrend(trend Trend, current, historical *Current, units *Units, w io.Writer) {
+  fmt.Fprintf(w, "Trend over the last %s:\n", describeDuration(trend.Elapsed))
+  line := func(name string, ok bool, d float64, was, now string) {
+    if !ok {
+      return
+    }
+    word, arrow := direction(d)
+    fmt.Fprintf(w, "   %s %s is %s (was %s, now %s)\n", arrow, name, word, was, now)
+  }
+  line("Temperature", trend.TempOk, trend.TempF,
+    units.Temp(string(historical.Temp_f), string(historical.Temp_c)), units.Temp(string(current.Temp_f), string(current.Temp_c)))
+  line("Humidity", trend.HumidityOk, trend.Humidity,
+    units.Number(historical.Relative_humidity), units.Number(current.Relative_humidity))
+  line("Pressure", trend.PressureOk, trend.PressureIn,
+    units.Number(historical.Pressure_in+" in"), units.Number(current.Pressure_in+" in"))
+  line("Wind speed", trend.WindOk, trend.WindMph,
+    units.Number(string(historical.Wind_mph)+" mph"), units.Number(string(current.Wind_mph)+" mph"))
+}
+
+// PreviousObservation returns the latest cached observation made
+// before current
+func PreviousObservation(cached []Current, current *Current) (*Current, bool) {
+  now, ok := observationTime(current)
+  if !ok {
+    return nil, false
+  }
+  var previous *Current
+  for i := range cached {
+    if t, ok := observationTime(&cached[i]); ok && t.Before(now) {
+      previous = &cached[i]
+    }
+  }
+  return previous, previous != nil
+}
+
+// FieldDelta is the change in one measurement between two observations
+type FieldDelta struct {
+  Name  string  `json:"name"`
+  Old   float64 `json:"old"`
+  New   float64 `json:"new"`
+  Delta float64 `json:"delta"`
+}
+
+// DiffCurrents returns the measurements (in Fahrenheit, percent,
+// inches, miles, and miles per hour) that differ between prev and
+// curr.  A measurement missing from either is left out.
+func DiffCurrents(prev, curr *Current) []FieldDelta {
+  fields := []struct {
+    name     string
+    old, new string
+  }{
+    {"Temperature", string(prev.Temp_f), string(curr.Temp_f)},
+    {"Dew point", string(prev.Dewpoint_f), string(curr.Dewpoint_f)},
+    {"Humidity", prev.Relative_humidity, curr.Relative_humidity},
+    {"Pressure", prev.Pressure_in, curr.Pressure_in},
+    {"Wind", string(prev.Wind_mph), string(curr.Wind_mph)},
+    {"Visibility", prev.Visibility_mi, curr.Visibility_mi},
+    {"Precipitation today", string(prev.Precip_today_in), string(curr.Precip_today_in)},
+  }
+  var deltas []FieldDelta
+  for _, f := range fields {
+    d, ok := delta(f.old, f.new)
+    if !ok || d == 0 {
+      continue
+    }
+    old, _ := strconv.ParseFloat(strings.TrimSuffix(f.old, "%"), 64)
+    deltas = append(deltas, FieldDelta{f.name, old, math.Round((old+d)*100) / 100, d})
+  }
+  return deltas
+}
+
+// describeDelta renders a FieldDelta in units as e.g. "Temperature up
+// 3.0 F" or "Wind picked up from 8 to 14 mph"
+func describeDelta(d FieldDelta, units *Units) string {
+  word := "up"
+  if d.Delta < 0 {
+    word = "down"
+  }
+  amount := math.Abs(d.Delta)
+  format := func(f float64, decimals int) string { return strconv.FormatFloat(f, 'f', decimals, 64) }
+  // either formats a measurement in imperial or metric units, or both,
+  // as units.System has it
+  either := func(imperial, metric string) string {
+    switch units.System {
+    case ImperialOnly:
+      return units.Number(imperial)
+    case MetricOnly:
+      return units.Number(metric)
+    }
+    return units.Number(imperial + " (" + metric + ")")
+  }
+  switch d.Name {
+  case "Temperature", "Dew point":
+    return fmt.Sprintf("%s %s %s", d.Name, word, units.Difference(amount, 1))
+  case "Humidity":
+    return units.Number(fmt.Sprintf("%s %s %g%%", d.Name, word, amount))
+  case "Pressure":
+    var p string
+    switch units.Pressure {
+    case "inhg":
+      p = format(amount, 2) + " inHg"
+    case "mb":
+      p = format(amount*mbPerInHg, 1) + " mb"
+    case "kpa":
+      p = format(InHgToKPa(amount), 2) + " kPa"
+    case "atm":
+      p = format(InHgToAtm(amount), 3) + " atm"
+    default:
+      p = format(amount, 2) + " inHg (" + format(amount*mbPerInHg, 1) + " mb)"
+    }
+    return units.Number(fmt.Sprintf("%s %s %s", d.Name, word, p))
+  case "Wind":
+    word = "picked up"
+    if d.Delta < 0 {
+      word = "eased"
+    }
+    return fmt.Sprintf("Wind %s from %s", word, either(
+      fmt.Sprintf("%g to %g mph", d.Old, d.New),
+      fmt.Sprintf("%s to %s km/h", format(d.Old*kmPerMile, 0), format(d.New*kmPerMile, 0))))
+  case "Visibility":
+    return fmt.Sprintf("%s %s %s", d.Name, word, either(
+      fmt.Sprintf("%g mi", amount), format(amount*kmPerMile, 1)+" km"))
+  }
+  return fmt.Sprintf("%s %s %s", d.Name, word, units.Precip(format(amount, 2), format(amount*25.4, 1)))
+}
+
+// PrintDiffPrevious prints what has changed between the previous and
+// current observations
+func PrintDiffPrevious(current, previous *Current, units *Units, w io.Writer) {
+  now, _ := observationTime(current)
+  then, _ := observationTime(previous)
+  since := describeDuration(now.Sub(then)) + " ago"
+  deltas := DiffCurrents(previous, current)
+  if len(deltas) == 0 {
+    fmt.Fprintf(w, "No change since %s.\n", since)
+    return
+  }
+  changes := make([]string, len(deltas))
+  for i, d := range deltas {
+    changes[i] = describeDelta(d, units)
+  }
+  fmt.Fprintf(w, "Since %s: %s.\n", since, strings.Join(changes, ", "))
+}
+
+// forecastCacheAge is how long forecasts are kept in the cache: long
+// enough to compare with the forecast of a day ago
+const forecastCacheAge = 48 * time.Hour
+
+// CachedForecast is a forecast as it stood when it was fetched
+type CachedForecast struct {
+  Fetched int64               `json:"fetched"`
+  Days    []Simpleforecastday `json:"days"`
+}
+
+// forecastCacheFile returns the forecast cache file for a station
+func forecastCacheFile(stationId string) string {
+  return strings.TrimSuffix(cacheFile(stationId), ".json") + "_forecast.json"
+}
+
+// LoadForecasts reads the cached forecasts for a station, oldest first
+func LoadForecasts(stationId string) []CachedForecast {
+  var cached []CachedForecast
+  if b, err := ioutil.ReadFile(forecastCacheFile(stationId)); err == nil {
+    json.Unmarshal(b, &cached)
+  }
+  return cached
+}
+
+// CacheForecast adds days to the station's forecast cache (as fetched
+// now), dropping anything older than forecastCacheAge
+func CacheForecast(stationId string, days []Simpleforecastday, now time.Time) error {
+  if len(days) == 0 {
+    return nil
+  }
+  kept := make([]CachedForecast, 0)
+  for _, f := range LoadForecasts(stationId) {
+    if now.Sub(time.Unix(f.Fetched, 0)) < forecastCacheAge {
+      kept = append(kept, f)
+    }
+  }
+  kept = append(kept, CachedForecast{now.Unix(), days})
+  b, err := json.Marshal(kept)
+  if err != nil {
+    return err
+  }
+  if err := os.MkdirAll(CacheDir(), 0755); err != nil {
+    return err
+  }
+  return ioutil.WriteFile(forecastCacheFile(stationId), b, 0644)
+}
+
+// ForecastBaseline picks the cached forecast to compare with: the
+// latest fetched at least window before now, or failing that the oldest
+func ForecastBaseline(cached []CachedForecast, now time.Time, window time.Duration) (*CachedForecast, bool) {
+  var baseline *CachedForecast
+  for i := range cached {
+    t := time.Unix(cached[i].Fetched, 0)
+    if !t.Before(now) {
+      continue
+    }
+    if baseline == nil || now.Sub(t) >= window {
+      baseline = &cached[i]
+    }
+  }
+  return baseline, baseline != nil
+}
+
+// forecastChange is how many degrees (F) the high must move for
+// DiffForecasts to report it, and forecastDeltaDays how many days of
+// the forecast -forecast-delta compares
+const (
+  forecastChange    = 3
+  forecastDeltaDays = 7
+)
+
+// ForecastDelta is a day whose forecast has changed
+type ForecastDelta struct {
+  Title      string  `json:"title"`
+  OldSummary string  `json:"old"`
+  NewSummary string  `json:"new"`
+  HighChange float64 `json:"high_change_f"`
+}
+
+// DiffForecasts compares the days of two forecasts (matched by date)
+// and returns the ones whose conditions changed, or whose high moved
+// more than forecastChange degrees, summed up in units
+func DiffForecasts(old, new []Simpleforecastday, units *Units) []ForecastDelta {
+  before := make(map[string]Simpleforecastday)
+  for _, d := range old {
+    before[d.Date.Time().Format("20060102")] = d
+  }
+  var deltas []ForecastDelta
+  for _, d := range new {
+    o, ok := before[d.Date.Time().Format("20060102")]
+    if !ok {
+      continue
+    }
+    change, ok := delta(string(o.High.Fahrenheit), string(d.High.Fahrenheit))
+    if !ok {
+      change = 0
+    }
+    if o.Conditions == d.Conditions && math.Abs(change) <= forecastChange {
+      continue
+    }
+    deltas = append(deltas, ForecastDelta{d.Date.Weekday, forecastSummary(o, units), forecastSummary(d, units), change})
+  }
+  return deltas
+}
+
+// forecastSummary sums up a forecast day (e.g. "Sunny, High 82 F")
+func forecastSummary(d Simpleforecastday, units *Units) string {
+  return fmt.Sprintf("%s, High %s", d.Conditions, units.Temp(string(d.High.Fahrenheit), string(d.High.Celsius)))
+}
+
+// signedDifference formats a change in temperature with its sign, on
+// each unit shown (e.g. "+7 F (+4 C)")
+func signedDifference(f float64, decimals int, units *Units) string {
+  s := units.Difference(f, decimals)
+  if f > 0 {
+    s = "+" + strings.Replace(s, "(", "(+", 1)
+  }
+  return s
+}
+
+// PrintForecastDelta prints how the forecast has changed since baseline
+func PrintForecastDelta(obs *Conditions, baseline *CachedForecast, units *Units, w io.Writer) {
+  if baseline == nil {
+    fmt.Fprintln(w, "No earlier forecast cached yet; run wu again later to see how the forecast changes.")
+    return
+  }
+  days := obs.Forecast.Simpleforecast.Forecastday
+  if len(days) > forecastDeltaDays {
+    days = days[:forecastDeltaDays]
+  }
+  since := describeDuration(time.Since(time.Unix(baseline.Fetched, 0))) + " ago"
+  deltas := DiffForecasts(baseline.Days, days, units)
+  if len(deltas) == 0 {
+    fmt.Fprintf(w, "No significant changes since the forecast of %s.\n", since)
+    return
+  }
+  fmt.Fprintf(w, "Changes since the forecast of %s:\n", since)
+  for _, d := range deltas {
+    now := d.NewSummary
+    if d.HighChange != 0 {
+      now += " (" + signedDifference(d.HighChange, 0, units) + ")"
+    }
+    fmt.Fprintf(w, "   %s's forecast changed: was '%s', now '%s'\n", d.Title, d.OldSummary, now)
+  }
+}
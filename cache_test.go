@@ -0,0 +1,231 @@
+/*
+* cache_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the cached observations behind --conditions-trend (cache.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:13 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "strconv"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestComputeTrend(t *testing.T) {
+  then := Current{Observation_epoch: "1378054800", Temp_f: "68.0", Relative_humidity: "55%",
+    Pressure_in: "30.12", Wind_mph: "4.0"}
+  now := Current{Observation_epoch: "1378062000", Temp_f: "72.3", Relative_humidity: "48%",
+    Pressure_in: "30.05", Wind_mph: "9.5"}
+  got := ComputeTrend(&now, &then)
+  want := Trend{
+    Elapsed:    2 * time.Hour,
+    TempF:      4.3,
+    Humidity:   -7,
+    PressureIn: -0.07,
+    WindMph:    5.5,
+    TempOk:     true,
+    HumidityOk: true,
+    PressureOk: true,
+    WindOk:     true,
+  }
+  if got != want {
+    t.Errorf("ComputeTrend = %+v, want %+v", got, want)
+  }
+  now.Wind_mph = "NA"
+  if got := ComputeTrend(&now, &then); got.WindOk {
+    t.Errorf("ComputeTrend with no current wind reported a wind change of %v", got.WindMph)
+  }
+}
+
+func TestTrendBaseline(t *testing.T) {
+  at := func(hoursAgo float64) Current {
+    seconds := int64(hoursAgo * 3600)
+    return Current{Observation_epoch: strconv.FormatInt(1378062000-seconds, 10)}
+  }
+  current := at(0)
+  tests := []struct {
+    name   string
+    cached []Current
+    window time.Duration
+    want   string
+    ok     bool
+  }{
+    {"latest at least two hours old", []Current{at(5), at(2.5), at(2), at(1)}, 2 * time.Hour, at(2).Observation_epoch, true},
+    {"oldest when nothing is old enough", []Current{at(1.5), at(1)}, 2 * time.Hour, at(1.5).Observation_epoch, true},
+    {"shorter window", []Current{at(2), at(1)}, time.Hour, at(1).Observation_epoch, true},
+    {"only the current observation", []Current{at(0)}, 2 * time.Hour, "", false},
+    {"no cache", nil, 2 * time.Hour, "", false},
+  }
+  for _, tt := range tests {
+    got, ok := TrendBaseline(tt.cached, &current, tt.window)
+    if ok != tt.ok || (ok && got.Observation_epoch != tt.want) {
+      t.Errorf("%s: TrendBaseline = %v, %v, want %s, %v", tt.name, got, ok, tt.want, tt.ok)
+    }
+  }
+}
+
+func TestDiffCurrents(t *testing.T) {
+  prev := Current{Observation_epoch: "1378060200", Temp_f: "69.0", Dewpoint_f: "50", Relative_humidity: "48%",
+    Pressure_in: "30.05", Wind_mph: "8", Visibility_mi: "10.0", Precip_today_in: "0.00"}
+  curr := prev
+  curr.Observation_epoch = "1378062000"
+  curr.Temp_f = "72.0"
+  curr.Wind_mph = "14"
+
+  deltas := DiffCurrents(&prev, &curr)
+  want := []FieldDelta{{"Temperature", 69, 72, 3}, {"Wind", 8, 14, 6}}
+  if len(deltas) != len(want) {
+    t.Fatalf("DiffCurrents = %+v, want %+v", deltas, want)
+  }
+  for i := range want {
+    if deltas[i] != want[i] {
+      t.Errorf("delta %d = %+v, want %+v", i, deltas[i], want[i])
+    }
+  }
+
+  u := NewUnits(ImperialOnly)
+  var buf bytes.Buffer
+  PrintDiffPrevious(&curr, &prev, &u, &buf)
+  if want := "Since 30 minutes ago: Temperature up 3.0 F, Wind picked up from 8 to 14 mph.\n"; buf.String() != want {
+    t.Errorf("PrintDiffPrevious = %q, want %q", buf.String(), want)
+  }
+
+  buf.Reset()
+  PrintDiffPrevious(&prev, &prev, &u, &buf)
+  if !bytes.HasPrefix(buf.Bytes(), []byte("No change since ")) {
+    t.Errorf("PrintDiffPrevious with no change = %q, want \"No change since ...\"", buf.String())
+  }
+}
+
+func TestDescribeDelta(t *testing.T) {
+  imperial, metric, both := NewUnits(ImperialOnly), NewUnits(MetricOnly), Units{}
+  tests := []struct {
+    delta FieldDelta
+    units *Units
+    want  string
+  }{
+    {FieldDelta{"Temperature", 69, 72, 3}, &imperial, "Temperature up 3.0 F"},
+    {FieldDelta{"Temperature", 69, 72, 3}, &metric, "Temperature up 1.7 C"},
+    {FieldDelta{"Dew point", 55, 50, -5}, &both, "Dew point down 5.0 F (2.8 C)"},
+    {FieldDelta{"Humidity", 48, 43, -5}, &metric, "Humidity down 5%"},
+    {FieldDelta{"Pressure", 30.05, 29.95, -0.1}, &imperial, "Pressure down 0.10 inHg"},
+    {FieldDelta{"Pressure", 30.05, 29.95, -0.1}, &metric, "Pressure down 3.4 mb"},
+    {FieldDelta{"Pressure", 30.05, 29.95, -0.1}, &both, "Pressure down 0.10 inHg (3.4 mb)"},
+    {FieldDelta{"Pressure", 30.05, 29.95, -0.1}, &Units{Pressure: "kpa"}, "Pressure down 0.34 kPa"},
+    {FieldDelta{"Wind", 8, 14, 6}, &metric, "Wind picked up from 13 to 23 km/h"},
+    {FieldDelta{"Wind", 14, 8, -6}, &both, "Wind eased from 14 to 8 mph (23 to 13 km/h)"},
+    {FieldDelta{"Visibility", 10, 7, -3}, &imperial, "Visibility down 3 mi"},
+    {FieldDelta{"Visibility", 10, 7, -3}, &metric, "Visibility down 4.8 km"},
+    {FieldDelta{"Precipitation today", 0, 0.1, 0.1}, &imperial, "Precipitation today up 0.10 in"},
+    {FieldDelta{"Precipitation today", 0, 0.1, 0.1}, &metric, "Precipitation today up 2.5 mm"},
+  }
+  for _, tt := range tests {
+    if got := describeDelta(tt.delta, tt.units); got != tt.want {
+      t.Errorf("describeDelta(%+v, %+v) = %q, want %q", tt.delta, *tt.units, got, tt.want)
+    }
+  }
+}
+
+func TestDiffForecasts(t *testing.T) {
+  old := forecastDays(forecastMonday, dayConditions("Sunny", "Sunny", "Cloudy", "Rain", "Clear"),
+    dayHighs("82", "80", "75", "70", "72"), dayHighsC("28", "27", "24", "21", "22"))
+  new := forecastDays(forecastMonday, dayConditions("Partly Cloudy", "Sunny", "Cloudy", "Thunderstorm", "Clear"),
+    dayHighs("75", "82", "76", "70", "72"), dayHighsC("24", "28", "24", "21", "22"))
+  // Tuesday's high moved only 2 F and Wednesday's 1 F, so neither counts
+  u := NewUnits(ImperialOnly)
+  deltas := DiffForecasts(old, new, &u)
+  want := []ForecastDelta{
+    {"Monday", "Sunny, High 82 F", "Partly Cloudy, High 75 F", -7},
+    {"Thursday", "Rain, High 70 F", "Thunderstorm, High 70 F", 0},
+  }
+  if len(deltas) != len(want) {
+    t.Fatalf("DiffForecasts = %+v, want %+v", deltas, want)
+  }
+  for i := range want {
+    if deltas[i] != want[i] {
+      t.Errorf("delta %d = %+v, want %+v", i, deltas[i], want[i])
+    }
+  }
+
+  metric := NewUnits(MetricOnly)
+  if got := DiffForecasts(old, new, &metric); len(got) != 2 || got[0].NewSummary != "Partly Cloudy, High 24 C" {
+    t.Errorf("DiffForecasts in metric = %+v, want Monday at 24 C", got)
+  }
+}
+
+func TestPrintForecastDelta(t *testing.T) {
+  old := forecastDays(forecastMonday, dayConditions("Sunny", "Rain"), dayHighs("82", "60"), dayHighsC("28", "16"))
+  var obs Conditions
+  obs.Forecast.Simpleforecast.Forecastday = forecastDays(forecastMonday,
+    dayConditions("Partly Cloudy", "Rain"), dayHighs("75", "67"), dayHighsC("24", "19"))
+  baseline := &CachedForecast{time.Now().Add(-24 * time.Hour).Unix(), old}
+  tests := []struct {
+    units Units
+    want  []string
+  }{
+    {NewUnits(ImperialOnly), []string{
+      "   Monday's forecast changed: was 'Sunny, High 82 F', now 'Partly Cloudy, High 75 F (-7 F)'\n",
+      "   Tuesday's forecast changed: was 'Rain, High 60 F', now 'Rain, High 67 F (+7 F)'\n",
+    }},
+    {NewUnits(MetricOnly), []string{
+      "   Monday's forecast changed: was 'Sunny, High 28 C', now 'Partly Cloudy, High 24 C (-4 C)'\n",
+    }},
+    {Units{}, []string{"now 'Rain, High 67 F (19 C) (+7 F (+4 C))'"}},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintForecastDelta(&obs, baseline, &tt.units, &buf)
+    if !strings.HasPrefix(buf.String(), "Changes since the forecast of ") {
+      t.Errorf("%+v: got %q", tt.units, buf.String())
+    }
+    for _, want := range tt.want {
+      if !strings.Contains(buf.String(), want) {
+        t.Errorf("%+v: got %q, want it to contain %q", tt.units, buf.String(), want)
+      }
+    }
+  }
+
+  var buf bytes.Buffer
+  u := NewUnits(ImperialOnly)
+  PrintForecastDelta(&obs, nil, &u, &buf)
+  if !strings.HasPrefix(buf.String(), "No earlier forecast cached yet") {
+    t.Errorf("with no baseline: got %q", buf.String())
+  }
+}
+
+func TestForecastDeltaBaselineReadOnce(t *testing.T) {
+  serveAPI(t, `{"forecast": {"simpleforecast": {"forecastday": [
+    {"date": {"epoch": "1688230800", "weekday": "Saturday"}, "high": {"fahrenheit": "85", "celsius": "29"}, "conditions": "Clear"}]}}}`)
+  out := captureStdout(t, func() { weather([]string{"forecast", "forecastdelta"}, "KLNK") })
+  if !strings.Contains(out, "No earlier forecast cached yet") {
+    t.Errorf("the first -forecast -forecast-delta compared against the forecast it had just cached:\n%s", out)
+  }
+  if n := len(LoadForecasts("KLNK")); n != 1 {
+    t.Errorf("cached the forecast %d times, want once", n)
+  }
+}
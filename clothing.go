@@ -0,0 +1,84 @@
+/*
+* clothing.go
+*
+* This file is part of wu.  It contains functions related to
+* the --forecast-clothing switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:24:13 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "strconv"
+  "strings"
+)
+
+// ClothingSuggestion suggests what to wear for a day with the given
+// high and low (F), chance of precipitation, and conditions
+func ClothingSuggestion(highF, lowF float64, pop int, conditions string) string {
+  var advice []string
+  switch {
+  case strings.Contains(strings.ToLower(conditions), "snow"):
+    advice = append(advice, "Wear boots and heavy layers.")
+  case highF > 85:
+    advice = append(advice, "Wear light clothing.")
+  case highF >= 65:
+    advice = append(advice, "A t-shirt should be comfortable.")
+  case highF >= 45:
+    advice = append(advice, "Bring a jacket.")
+  default:
+    advice = append(advice, "Wear a heavy coat.")
+  }
+  if pop > 50 {
+    advice = append(advice, "Bring an umbrella.")
+  }
+  if highF-lowF >= 20 {
+    advice = append(advice, "Dress in layers.")
+  }
+  return strings.Join(advice, " ")
+}
+
+// PrintClothing prints ClothingSuggestion for the first day of the
+// forecast
+func PrintClothing(obs *Conditions, metric bool, w io.Writer) {
+  days := obs.Forecast.Simpleforecast.Forecastday
+  if len(days) == 0 {
+    fmt.Fprintln(w, "No forecast available.")
+    return
+  }
+  d := days[0]
+  high, ok1 := d.High.Fahrenheit.Float()
+  low, ok2 := d.Low.Fahrenheit.Float()
+  if !ok1 || !ok2 {
+    fmt.Fprintln(w, "No forecast temperatures available.")
+    return
+  }
+  pop, _ := strconv.Atoi(string(d.Pop))
+  temps := fmt.Sprintf("%s°F / %s°F", d.High.Fahrenheit, d.Low.Fahrenheit)
+  if metric {
+    temps = fmt.Sprintf("%s°C / %s°C", d.High.Celsius, d.Low.Celsius)
+  }
+  fmt.Fprintf(w, "Forecast: %s — %s\n", temps, ClothingSuggestion(high, low, pop, d.Conditions))
+}
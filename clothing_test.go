@@ -0,0 +1,81 @@
+/*
+* clothing_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --forecast-clothing (clothing.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:16:46 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "testing"
+)
+
+func TestClothingSuggestion(t *testing.T) {
+  tests := []struct {
+    high, low  float64
+    pop        int
+    conditions string
+    want       string
+  }{
+    {95, 78, 10, "Clear", "Wear light clothing."},
+    {86, 70, 0, "Sunny", "Wear light clothing."},
+    {85, 70, 0, "Sunny", "A t-shirt should be comfortable."},
+    {65, 50, 20, "Partly Cloudy", "A t-shirt should be comfortable."},
+    {64, 50, 20, "Partly Cloudy", "Bring a jacket."},
+    {45, 30, 0, "Overcast", "Bring a jacket."},
+    {44, 30, 0, "Overcast", "Wear a heavy coat."},
+    {10, -5, 0, "Clear", "Wear a heavy coat."},
+    {68, 45, 60, "Rain", "A t-shirt should be comfortable. Bring an umbrella. Dress in layers."},
+    {58, 48, 50, "Chance of Rain", "Bring a jacket."},
+    {58, 48, 51, "Chance of Rain", "Bring a jacket. Bring an umbrella."},
+    {30, 20, 80, "Snow Showers", "Wear boots and heavy layers. Bring an umbrella."},
+  }
+  for _, tt := range tests {
+    if got := ClothingSuggestion(tt.high, tt.low, tt.pop, tt.conditions); got != tt.want {
+      t.Errorf("ClothingSuggestion(%g, %g, %d, %q) = %q, want %q", tt.high, tt.low, tt.pop, tt.conditions, got, tt.want)
+    }
+  }
+}
+
+func TestPrintClothing(t *testing.T) {
+  var obs Conditions
+  obs.Forecast.Simpleforecast.Forecastday = []Simpleforecastday{{
+    High: Simple_temp{Fahrenheit: "68", Celsius: "20"}, Low: Simple_temp{Fahrenheit: "45", Celsius: "7"},
+    Pop: "60", Conditions: "Rain",
+  }}
+  var buf bytes.Buffer
+  PrintClothing(&obs, false, &buf)
+  want := "Forecast: 68°F / 45°F — A t-shirt should be comfortable. Bring an umbrella. Dress in layers.\n"
+  if buf.String() != want {
+    t.Errorf("PrintClothing = %q, want %q", buf.String(), want)
+  }
+  buf.Reset()
+  PrintClothing(&obs, true, &buf)
+  want = "Forecast: 20°C / 7°C — A t-shirt should be comfortable. Bring an umbrella. Dress in layers.\n"
+  if buf.String() != want {
+    t.Errorf("metric PrintClothing = %q, want %q", buf.String(), want)
+  }
+}
@@ -30,79 +30,467 @@ package main
 
 import (
   "fmt"
+  "io"
+  "os"
+  "os/exec"
   "regexp"
 	"strconv"
 	"strings"
+  "text/tabwriter"
+  "time"
+  "unicode/utf8"
 )
 
 type Current struct {
-  Observation_time     string
-  Observation_location Location
-  Station_id           string
-  Weather              string
-  Temperature_string   string
-  Relative_humidity    string
-  Wind_string          string
-  Pressure_mb          string
-  Pressure_in          string
-  Pressure_trend       string
-  Dewpoint_string      string
-  Heat_index_string    string
-  Windchill_string     string
-  Visibility_mi        string
-  Precip_today_string  string
+  Observation_time      string   `json:"observation_time"`
+  Observation_epoch     string   `json:"observation_epoch"`
+  Observation_epoch_int int64    `json:"observation_epoch_int,omitempty"`
+  Local_time_rfc822     string   `json:"local_time_rfc822"`
+  Local_tz_short        string   `json:"local_tz_short"`
+  Local_tz_long         string   `json:"local_tz_long"`
+  Observation_location  Location `json:"observation_location"`
+  Station_id            string   `json:"station_id"`
+  Weather               string   `json:"weather"`
+  Icon                  string   `json:"icon"`
+  Temperature_string    string   `json:"temperature_string"`
+  Temp_f                Value    `json:"temp_f"`
+  Temp_c                Value    `json:"temp_c"`
+  Relative_humidity     string   `json:"relative_humidity"`
+  Wind_string           string   `json:"wind_string"`
+  Wind_dir              string   `json:"wind_dir"`
+  Wind_degrees          Value    `json:"wind_degrees"`
+  Wind_mph              Value    `json:"wind_mph"`
+  Wind_gust_mph         Value    `json:"wind_gust_mph"`
+  Wind_kph              Value    `json:"wind_kph"`
+  Wind_gust_kph         Value    `json:"wind_gust_kph"`
+  Pressure_mb           string   `json:"pressure_mb"`
+  Pressure_in           string   `json:"pressure_in"`
+  Pressure_trend        string   `json:"pressure_trend"`
+  Dewpoint_string       string   `json:"dewpoint_string"`
+  Dewpoint_f            Value    `json:"dewpoint_f"`
+  Dewpoint_c            Value    `json:"dewpoint_c"`
+  Dew_point_comfort     string   `json:"dew_point_comfort,omitempty"`
+  Heat_index_string     string   `json:"heat_index_string"`
+  Windchill_string      string   `json:"windchill_string"`
+  Feelslike_string      string   `json:"feelslike_string"`
+  Visibility_mi         string   `json:"visibility_mi"`
+  Visibility_km         Value    `json:"visibility_km"`
+  Solarradiation        Value    `json:"solarradiation"`
+  UV                    Value    `json:"uv"`
+  Precip_today_string   string   `json:"precip_today_string"`
+  Precip_today_in       Value    `json:"precip_today_in"`
+  Precip_today_metric   Value    `json:"precip_today_metric"`
 }
 
 type Location struct {
-  Full string
+  Full      string `json:"full"`
+  City      string `json:"city"`
+  State     string `json:"state"`
+  Country   string `json:"country"`
+  Latitude  Value  `json:"latitude"`
+  Longitude Value  `json:"longitude"`
+  Elevation string `json:"elevation"`
 }
 
-// printConditions prints the conditions to standard output
-func PrintConditions(obs *Conditions) {
+// DewpointF returns the dew point in Fahrenheit, and false if the
+// station doesn't report one
+func (current *Current) DewpointF() (float64, bool) {
+  if current.Dewpoint_f != "" {
+    return current.Dewpoint_f.Float()
+  }
+  dp, err := strconv.ParseFloat(strings.Split(current.Dewpoint_string, " ")[0], 64)
+  return dp, err == nil
+}
+
+// DewPointComfort describes how a dew point (F) feels
+func DewPointComfort(dewpointF float64) string {
+  switch {
+  case dewpointF < 55:
+    return "Dry"
+  case dewpointF < 60:
+    return "Comfortable"
+  case dewpointF < 65:
+    return "Sticky"
+  case dewpointF < 70:
+    return "Uncomfortable"
+  case dewpointF <= 75:
+    return "Oppressive"
+  }
+  return "Dangerous"
+}
+
+// PrintDewPointComfort prints the dew point and how it feels
+func PrintDewPointComfort(obs *Conditions, units *Units, w io.Writer) {
   current := obs.Current_observation
-  fmt.Printf("Current conditions at %s (%s)\n%s\n",
-    current.Observation_location.Full, current.Station_id, current.Observation_time)
-  fmt.Println("   Temperature:", current.Temperature_string)
-  if current.Heat_index_string != "NA" {
-    fmt.Println("   Heat Index: ", current.Heat_index_string)
+  dp, ok := current.DewpointF()
+  if !ok {
+    fmt.Fprintln(w, "Dew point unavailable.")
+    return
+  }
+  dewpoint := units.Number(current.Dewpoint_string)
+  if current.Dewpoint_f != "" {
+    dewpoint = units.Temp(string(current.Dewpoint_f), string(current.Dewpoint_c))
+  }
+  fmt.Fprintf(w, "Dewpoint: %s (%s)\n", dewpoint, DewPointComfort(dp))
+}
+
+// PrintObservationEpoch prints the time of the observation as a Unix
+// timestamp, or returns an error if the station didn't report one
+func PrintObservationEpoch(obs *Conditions, w io.Writer) error {
+  epoch, err := strconv.ParseInt(obs.Current_observation.Observation_epoch, 10, 64)
+  if err != nil {
+    return fmt.Errorf("observation time unavailable (%q)", obs.Current_observation.Observation_epoch)
+  }
+  fmt.Fprintln(w, epoch)
+  return nil
+}
+
+// LocalTime returns the time at the station, in the station's time
+// zone if it can be found
+func LocalTime(current *Current) (time.Time, bool) {
+  t, err := time.Parse(time.RFC1123Z, current.Local_time_rfc822)
+  if err != nil {
+    return t, false
+  }
+  if loc, err := time.LoadLocation(current.Local_tz_long); err == nil && current.Local_tz_long != "" {
+    t = t.In(loc)
+  }
+  return t, true
+}
+
+// PrintLocalTime prints the local time at the reporting station
+func PrintLocalTime(obs *Conditions, w io.Writer) {
+  current := obs.Current_observation
+  t, ok := LocalTime(&current)
+  if !ok {
+    fmt.Fprintln(w, "Local time unavailable.")
+    return
+  }
+  zone := t.Format("MST")
+  if strings.HasPrefix(zone, "+") || strings.HasPrefix(zone, "-") {
+    if current.Local_tz_short != "" {
+      zone = current.Local_tz_short
+    }
+  }
+  fmt.Fprintf(w, "Local time at %s: %s %s\n", current.Observation_location.Full,
+    t.Format("Monday, January 2, 2006 3:04 PM"), zone)
+}
+
+// isNullish reports whether s is one of the values the API reports for
+// a measurement a station doesn't take: empty, "NA", "--", -999, or
+// -9999 (however many decimal places it has, e.g. "-9999.00").  Zero
+// is a real reading (0 F, a calm wind), so it isn't nullish; only
+// -omit-zero leaves it out (see omitted).
+func isNullish(s string) bool {
+  s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+  switch s {
+  case "", "NA", "--":
+    return true
+  }
+  f, err := strconv.ParseFloat(s, 64)
+  return err == nil && (f == -999 || f == -9999)
+}
+
+// omitted reports whether a line showing value should be left out
+// under -omit-zero: if it is nullish, or zero (as stations without a
+// UV or solar radiation sensor report those)
+func omitted(value string) bool {
+  if !omitzero {
+    return false
+  }
+  f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+  return isNullish(value) || err == nil && f == 0
+}
+
+// StaleDataError reports an observation older than
+// -conditions-age-warning allows
+type StaleDataError struct {
+  Age    time.Duration
+  MaxAge time.Duration
+}
+
+func (e *StaleDataError) Error() string {
+  return fmt.Sprintf("Observation is %.0f minutes old (threshold: %.0f minutes)",
+    e.Age.Minutes(), e.MaxAge.Minutes())
+}
+
+// CheckObservationAge returns a *StaleDataError if the observation
+// made at epochStr (a Unix timestamp) is more than maxAgeMinutes old
+func CheckObservationAge(epochStr string, maxAgeMinutes int) error {
+  epoch, err := strconv.ParseInt(epochStr, 10, 64)
+  if err != nil {
+    return fmt.Errorf("observation time unavailable: %q", epochStr)
+  }
+  age := time.Since(time.Unix(epoch, 0))
+  maxAge := time.Duration(maxAgeMinutes) * time.Minute
+  if age > maxAge {
+    return &StaleDataError{age, maxAge}
+  }
+  return nil
+}
+
+// pwsNote returns " (Personal Weather Station)" if id is a PWS ID
+func pwsNote(id string) string {
+  if isPWSID(id) {
+    return " (Personal Weather Station)"
+  }
+  return ""
+}
+
+// hourlyTemps returns the first temperature reported in each hour of
+// observations, in Celsius if metric is set
+func hourlyTemps(observations []Observations, metric bool) []float64 {
+  temps := make([]float64, 0)
+  lastHour := ""
+  for _, o := range observations {
+    t := o.Tempi
+    if metric {
+      t = o.Tempm
+    }
+    v, err := strconv.ParseFloat(t, 64)
+    if err != nil || o.Date.Hour == lastHour {
+      continue
+    }
+    lastHour = o.Date.Hour
+    temps = append(temps, v)
+  }
+  return temps
+}
+
+// temperatureChart returns " <sparkline> (today's trend)" for
+// -conditions-chart, or "" when there are fewer than 3 hours to chart
+func temperatureChart(obs *Conditions, metric bool) string {
+  temps := hourlyTemps(obs.today, metric)
+  if len(temps) < 3 {
+    return ""
+  }
+  return " " + sparkline(temps) + " (today's trend)"
+}
+
+// PrintConditions prints the current conditions
+func PrintConditions(obs *Conditions, units *Units, w io.Writer) {
+  current := obs.Current_observation
+  fmt.Fprintf(w, "Current conditions at %s (%s)%s\n%s\n",
+    current.Observation_location.Full, current.Station_id, pwsNote(current.Station_id), current.Observation_time)
+  if dostationdist {
+    PrintStationDistance(obs, w)
+  }
+  if current.Temp_f != "" {
+    if !omitted(string(current.Temp_f)) {
+      fmt.Fprintln(w, "   Temperature:", units.Temp(string(current.Temp_f), string(current.Temp_c))+temperatureChart(obs, units.Metric()))
+    }
+  } else {
+    fmt.Fprintln(w, "   Temperature:", units.Number(current.Temperature_string))
   }
-  fmt.Println("   Sky Conditions:", current.Weather)
-  fmt.Println("   Wind:", current.Wind_string)
-  pstring := fmt.Sprintf("   Pressure: %s in (%s mb) and", current.Pressure_in, current.Pressure_mb)
+  if current.Heat_index_string != "NA" && !omitted(current.Heat_index_string) {
+    fmt.Fprintln(w, "   Heat Index: ", units.Number(current.Heat_index_string))
+  }
+  if dohumidex || current.Observation_location.Country == "CA" {
+    PrintHumidex(&current, w)
+  }
+  if doheatindex {
+    PrintHeatIndex(&current, units.Metric(), w)
+  }
+  if !omitted(current.Weather) {
+    fmt.Fprintln(w, "   Sky Conditions:", skyConditions(&current))
+  }
+  if !omitted(current.Wind_string) {
+    fmt.Fprintln(w, "   Wind:", units.WindDescription(&current))
+  }
+  if gust, ok := currentGust(&current, units.Metric()); ok {
+    unit := "mph"
+    if units.Metric() {
+      unit = "km/h"
+    }
+    fmt.Fprintln(w, "   Wind Gusts:", units.Number(fmt.Sprintf("%.0f %s", gust, unit)))
+  }
+  if omitted(current.Pressure_in) {
+    current.Pressure_trend = ""
+  }
+  pstring := fmt.Sprintf("   Pressure: %s and", units.Press(current.Pressure_in, current.Pressure_mb))
   switch current.Pressure_trend {
   case "+":
-    fmt.Println(pstring, "rising")
+    fmt.Fprintln(w, pstring, "rising")
   case "-":
-    fmt.Println(pstring, "falling")
+    fmt.Fprintln(w, pstring, "falling")
   case "0":
-    fmt.Println(pstring, "holding steady")
-  }
-  fmt.Println("   Relative humidity:", current.Relative_humidity)
-	fmt.Print("   Dewpoint: ", current.Dewpoint_string)
-	dp_components := strings.Split(current.Dewpoint_string, " ")
-	dp, _ := strconv.Atoi(dp_components[0])
-	if dp < 50 {
-		fmt.Println(" (dry)")
-	} else if dp >= 50 && dp <= 54 {
-			fmt.Println(" (very comfortable)")
-	} else if dp >= 55 && dp <= 59 {
-			fmt.Println(" (comfortable)")
-	} else if dp >= 60 && dp <= 64 {
-			fmt.Println(" (okay for most)")
-	} else if dp >= 65 && dp <= 69 {
-			fmt.Println(" (somewhat uncomfortable)")
-	} else if dp >= 70 && dp <= 74 {
-			fmt.Println(" (very humid)")
-	} else if dp >= 75 && dp <= 80 {
-			fmt.Println(" (oppressive)")
-	} else if dp >= 80 {
-			fmt.Println(" (dangerously high)")
-		}
-  if current.Windchill_string != "NA" {
-    fmt.Println("   Windchill: ", current.Windchill_string)
-  }
-  fmt.Printf("   Visibility: %s miles\n", current.Visibility_mi)
-  if m, _ := regexp.MatchString("0.0", current.Precip_today_string); !m {
-    fmt.Println("   Precipitation today: ", current.Precip_today_string)
+    fmt.Fprintln(w, pstring, "holding steady")
+  }
+  if dopressurefcst {
+    PrintPressureForecast(&current, obs.pressureBaseline, w)
+  }
+  if !omitted(current.Relative_humidity) {
+    fmt.Fprintln(w, "   Relative humidity:", units.Number(current.Relative_humidity))
+  }
+  if current.Dewpoint_f == "" || !omitted(string(current.Dewpoint_f)) {
+    if current.Dewpoint_f != "" {
+      fmt.Fprint(w, "   Dewpoint: ", units.Temp(string(current.Dewpoint_f), string(current.Dewpoint_c)))
+    } else {
+      fmt.Fprint(w, "   Dewpoint: ", units.Number(current.Dewpoint_string))
+    }
+    if dp, ok := current.DewpointF(); ok {
+      fmt.Fprintf(w, " (%s)\n", DewPointComfort(dp))
+    } else {
+      fmt.Fprintln(w)
+    }
+  }
+  if current.Windchill_string != "NA" && !omitted(current.Windchill_string) {
+    fmt.Fprintln(w, "   Windchill: ", units.Number(current.Windchill_string))
+  }
+  if !omitted(current.Visibility_mi) || !omitted(string(current.Visibility_km)) {
+    fmt.Fprintln(w, "   Visibility:", units.Visibility(&current))
+  }
+  if m, _ := regexp.MatchString("0.0", current.Precip_today_string); !m && !omitted(current.Precip_today_string) {
+    if current.Precip_today_in != "" {
+      fmt.Fprintln(w, "   Precipitation today: ", units.Precip(string(current.Precip_today_in), string(current.Precip_today_metric)))
+    } else {
+      fmt.Fprintln(w, "   Precipitation today: ", units.Number(current.Precip_today_string))
+    }
+  }
+}
+
+// PrintConditionsAllUnits prints the current conditions with every
+// measurement in each of the units wu knows, in aligned columns
+func PrintConditionsAllUnits(obs *Conditions, w io.Writer) {
+  current := obs.Current_observation
+  fmt.Fprintf(w, "Current conditions at %s (%s)\n%s\n",
+    current.Observation_location.Full, current.Station_id, current.Observation_time)
+  tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+  row := func(label string, values ...string) {
+    fmt.Fprintf(tw, "   %s:\t%s\n", label, units.Number(strings.Join(values, "\t/ ")))
+  }
+  temp := func(label string, f, c Value) {
+    if cel, ok := c.Float(); ok && !omitted(string(f)) {
+      row(label, string(f)+" °F", string(c)+" °C", fmt.Sprintf("%.1f K", cel+273.15))
+    }
+  }
+  temp("Temperature", current.Temp_f, current.Temp_c)
+  temp("Dewpoint", current.Dewpoint_f, current.Dewpoint_c)
+  if mph, ok := current.Wind_mph.Float(); ok {
+    row("Wind", string(current.Wind_mph)+" mph", string(current.Wind_kph)+" km/h",
+      fmt.Sprintf("Force %d", Beaufort(mph)))
+  }
+  if mph, ok := currentGust(&current, false); ok {
+    row("Wind Gusts", string(current.Wind_gust_mph)+" mph", string(current.Wind_gust_kph)+" km/h",
+      fmt.Sprintf("Force %d", Beaufort(mph)))
+  }
+  if in, err := strconv.ParseFloat(current.Pressure_in, 64); err == nil {
+    row("Pressure", current.Pressure_in+" inHg", current.Pressure_mb+" mb",
+      fmt.Sprintf("%.1f kPa", InHgToKPa(in)), fmt.Sprintf("%.3f atm", InHgToAtm(in)))
+  }
+  if current.Visibility_mi != "" && !omitted(current.Visibility_mi) {
+    row("Visibility", current.Visibility_mi+" mi", string(current.Visibility_km)+" km")
+  }
+  if current.Precip_today_in != "" && !omitted(string(current.Precip_today_in)) {
+    row("Precipitation today", string(current.Precip_today_in)+" in", string(current.Precip_today_metric)+" mm")
+  }
+  tw.Flush()
+}
+
+// wideMinimum is the narrowest terminal -conditions-wide will use two
+// columns on
+const wideMinimum = 100
+
+// terminalWidth returns the width of the terminal, from $COLUMNS or
+// stty, or 80 if it can't be determined
+func terminalWidth() int {
+  if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+    return n
+  }
+  f, err := os.Open("/dev/tty")
+  if err != nil {
+    return 80
+  }
+  defer f.Close()
+  cmd := exec.Command("stty", "size")
+  cmd.Stdin = f
+  out, err := cmd.Output()
+  if err != nil {
+    return 80
+  }
+  fields := strings.Fields(string(out))
+  if len(fields) == 2 {
+    if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+      return n
+    }
+  }
+  return 80
+}
+
+// sideBySide lays out two columns of lines next to each other, and
+// reports false if they don't fit in width
+func sideBySide(left, right []string, width int) ([]string, bool) {
+  leftWidth := 0
+  for _, l := range left {
+    if n := utf8.RuneCountInString(l); n > leftWidth {
+      leftWidth = n
+    }
+  }
+  leftWidth += 4
+  rows := len(left)
+  if len(right) > rows {
+    rows = len(right)
+  }
+  lines := make([]string, rows)
+  for i := range lines {
+    var l, r string
+    if i < len(left) {
+      l = left[i]
+    }
+    if i < len(right) {
+      r = right[i]
+    }
+    line := strings.TrimRight(l+strings.Repeat(" ", leftWidth-utf8.RuneCountInString(l))+r, " ")
+    if utf8.RuneCountInString(line) > width {
+      return nil, false
+    }
+    lines[i] = line
+  }
+  return lines, true
+}
+
+// skyConditions returns the sky conditions, translated for
+// -conditions-translate
+func skyConditions(current *Current) string {
+  if translatelang == "" {
+    return current.Weather
+  }
+  return translateCondition(current.Weather, translatelang)
+}
+
+// PrintConditionsWide prints the current conditions in two columns
+// (temperature and moisture on the left, wind and sky on the right)
+// when the terminal is wide enough, and as usual otherwise
+func PrintConditionsWide(obs *Conditions, units *Units, w io.Writer) {
+  current := obs.Current_observation
+  var left, right []string
+  add := func(lines []string, value, line string) []string {
+    if omitted(value) {
+      return lines
+    }
+    return append(lines, line)
+  }
+  left = add(left, string(current.Temp_f), "Temperature: "+units.Temp(string(current.Temp_f), string(current.Temp_c)))
+  left = add(left, current.Relative_humidity, "Relative humidity: "+units.Number(current.Relative_humidity))
+  left = add(left, string(current.Dewpoint_f), "Dewpoint: "+units.Temp(string(current.Dewpoint_f), string(current.Dewpoint_c)))
+  left = add(left, current.Pressure_in, "Pressure: "+units.Press(current.Pressure_in, current.Pressure_mb))
+  right = append(right, "Sky Conditions: "+skyConditions(&current), "Wind: "+units.WindDescription(&current))
+  right = add(right, current.Visibility_mi, "Visibility: "+units.Visibility(&current))
+  if current.UV != "" && !omitted(string(current.UV)) {
+    right = append(right, "UV index: "+units.Number(string(current.UV)))
+  }
+  if current.Solarradiation != "" && current.Solarradiation != "--" && !omitted(string(current.Solarradiation)) {
+    right = append(right, "Solar radiation: "+units.Number(string(current.Solarradiation)+" W/m²"))
+  }
+  width := terminalWidth()
+  lines, ok := sideBySide(left, right, width-3)
+  if width < wideMinimum || !ok {
+    PrintConditions(obs, units, w)
+    return
+  }
+  fmt.Fprintf(w, "Current conditions at %s (%s)\n%s\n",
+    current.Observation_location.Full, current.Station_id, current.Observation_time)
+  for _, line := range lines {
+    fmt.Fprintln(w, "   "+line)
   }
 }
@@ -0,0 +1,462 @@
+/*
+* conditions_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the current conditions (conditions.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:18:51 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "errors"
+  "os"
+  "os/exec"
+  "strconv"
+  "strings"
+  "testing"
+  "time"
+  "unicode/utf8"
+)
+
+func TestDewPointComfort(t *testing.T) {
+  tests := []struct {
+    dewpoint float64
+    want     string
+  }{
+    {40, "Dry"},
+    {54.9, "Dry"},
+    {55, "Comfortable"},
+    {59.9, "Comfortable"},
+    {60, "Sticky"},
+    {64.9, "Sticky"},
+    {65, "Uncomfortable"},
+    {69.9, "Uncomfortable"},
+    {70, "Oppressive"},
+    {75, "Oppressive"},
+    {75.1, "Dangerous"},
+    {80, "Dangerous"},
+  }
+  for _, tt := range tests {
+    if got := DewPointComfort(tt.dewpoint); got != tt.want {
+      t.Errorf("DewPointComfort(%v) = %q, want %q", tt.dewpoint, got, tt.want)
+    }
+  }
+}
+
+func TestPrintDewPointComfort(t *testing.T) {
+  tests := []struct {
+    current Current
+    want    string
+  }{
+    {Current{Dewpoint_f: "62", Dewpoint_c: "17"}, "Dewpoint: 62 F (17 C) (Sticky)\n"},
+    {Current{Dewpoint_string: "50 F (10 C)"}, "Dewpoint: 50 F (10 C) (Dry)\n"},
+    {Current{Dewpoint_string: "NA"}, "Dew point unavailable.\n"},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintDewPointComfort(&Conditions{Current_observation: tt.current}, &Units{}, &buf)
+    if got := buf.String(); got != tt.want {
+      t.Errorf("PrintDewPointComfort(%+v) = %q, want %q", tt.current, got, tt.want)
+    }
+  }
+}
+
+func TestPrintObservationEpoch(t *testing.T) {
+  min := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+  max := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+  tests := []struct {
+    epoch string
+    ok    bool
+  }{
+    {"1378062000", true},
+    {"1700000000", true},
+    {"", false},
+    {"NA", false},
+    {"1378062000.5", false},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    obs := &Conditions{Current_observation: Current{Observation_epoch: tt.epoch}}
+    err := PrintObservationEpoch(obs, &buf)
+    if (err == nil) != tt.ok {
+      t.Errorf("PrintObservationEpoch(%q) error = %v, want ok = %v", tt.epoch, err, tt.ok)
+      continue
+    }
+    if !tt.ok {
+      if buf.Len() != 0 {
+        t.Errorf("PrintObservationEpoch(%q) printed %q", tt.epoch, buf.String())
+      }
+      continue
+    }
+    n, err := strconv.ParseInt(strings.TrimSuffix(buf.String(), "\n"), 10, 64)
+    if err != nil || n < min || n > max {
+      t.Errorf("PrintObservationEpoch(%q) = %q, want a timestamp between 2010 and 2100", tt.epoch, buf.String())
+    }
+  }
+}
+
+// wideFixture is an observation with something in every field
+// -conditions-wide shows
+func wideFixture() *Conditions {
+  var obs Conditions
+  obs.Current_observation = Current{
+    Observation_location: Location{Full: "Downtown Lincoln, Lincoln, Nebraska"},
+    Station_id:           "KLNK",
+    Observation_time:     "Last Updated on September 1, 1:53 PM CDT",
+    Weather:              "Partly Cloudy",
+    Temp_f:               "72.3",
+    Temp_c:               "22.4",
+    Relative_humidity:    "45%",
+    Dewpoint_f:           "50",
+    Dewpoint_c:           "10",
+    Pressure_in:          "29.92",
+    Pressure_mb:          "1013",
+    Wind_string:          "From the SSW at 12.0 MPH Gusting to 20.0 MPH",
+    Wind_dir:             "SSW",
+    Wind_mph:             "12.0",
+    Wind_gust_mph:        "20.0",
+    Visibility_mi:        "10.0",
+    UV:                   "4",
+    Solarradiation:       "650",
+  }
+  return &obs
+}
+
+func TestPrintConditionsWide(t *testing.T) {
+  t.Setenv("COLUMNS", "120")
+  var buf bytes.Buffer
+  PrintConditionsWide(wideFixture(), &Units{Temperature: "f"}, &buf)
+  out := buf.String()
+  sideBySide := false
+  for _, line := range strings.Split(out, "\n") {
+    if n := utf8.RuneCountInString(line); n > 120 {
+      t.Errorf("line is %d columns wide: %q", n, line)
+    }
+    if strings.Contains(line, "Temperature: ") && strings.Contains(line, "Sky Conditions: ") {
+      sideBySide = true
+    }
+  }
+  if !sideBySide {
+    t.Errorf("temperature and sky conditions aren't side by side:\n%s", out)
+  }
+  for _, want := range []string{"Dewpoint: ", "Pressure: ", "Wind: ", "Visibility: ", "UV index: ", "Solar radiation: "} {
+    if !strings.Contains(out, want) {
+      t.Errorf("no %q in\n%s", want, out)
+    }
+  }
+}
+
+func TestPrintConditionsWideNarrow(t *testing.T) {
+  t.Setenv("COLUMNS", "80")
+  var buf bytes.Buffer
+  out := captureStdout(t, func() { PrintConditionsWide(wideFixture(), &Units{Temperature: "c"}, &buf) })
+  if out != "" {
+    t.Errorf("the narrow layout went to standard output rather than w:\n%s", out)
+  }
+  out = buf.String()
+  if !strings.Contains(out, "Temperature: 22.4 C") {
+    t.Errorf("the narrow layout ignored the units it was given:\n%s", out)
+  }
+  for _, line := range strings.Split(out, "\n") {
+    if strings.Contains(line, "Temperature: ") && strings.Contains(line, "Sky Conditions: ") {
+      t.Errorf("narrow layout has two columns: %q", line)
+    }
+  }
+  if !strings.Contains(out, "Temperature: ") {
+    t.Errorf("narrow layout is missing the conditions:\n%s", out)
+  }
+}
+
+func TestPrintLocalTime(t *testing.T) {
+  tests := []struct {
+    rfc822, tzShort, tzLong string
+    want                    string
+  }{
+    {"Mon, 02 Jan 2006 15:04:05 -0600", "CST", "America/Chicago", "Local time at Lincoln, Nebraska: Monday, January 2, 2006 3:04 PM CST\n"},
+    {"Sun, 01 Sep 2013 13:53:00 -0500", "CDT", "", "Local time at Lincoln, Nebraska: Sunday, September 1, 2013 1:53 PM CDT\n"},
+    {"Sun, 01 Sep 2013 13:53:00 -0500", "", "", "Local time at Lincoln, Nebraska: Sunday, September 1, 2013 1:53 PM -0500\n"},
+    {"", "CST", "America/Chicago", "Local time unavailable.\n"},
+    {"yesterday", "CST", "", "Local time unavailable.\n"},
+  }
+  for _, tt := range tests {
+    var obs Conditions
+    obs.Current_observation = Current{Local_time_rfc822: tt.rfc822, Local_tz_short: tt.tzShort, Local_tz_long: tt.tzLong,
+      Observation_location: Location{Full: "Lincoln, Nebraska"}}
+    var buf bytes.Buffer
+    PrintLocalTime(&obs, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintLocalTime(%q) = %q, want %q", tt.rfc822, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestIsNullish(t *testing.T) {
+  tests := []struct {
+    s    string
+    want bool
+  }{
+    {"", true},
+    {"NA", true},
+    {"--", true},
+    {"-9999", true},
+    {" -9999 ", true},
+    {"-9999%", true},
+    {"-9999.00", true},
+    {"-9999.0", true},
+    {"-999", true},
+    {"-999.0", true},
+    {"0", false},
+    {"0.0", false},
+    {"72.3", false},
+    {"-9", false},
+    {"45%", false},
+  }
+  for _, tt := range tests {
+    if got := isNullish(tt.s); got != tt.want {
+      t.Errorf("isNullish(%q) = %v, want %v", tt.s, got, tt.want)
+    }
+  }
+}
+
+func TestOmitZero(t *testing.T) {
+  savedOmit, savedUnits := omitzero, units
+  t.Cleanup(func() { omitzero, units = savedOmit, savedUnits })
+  omitzero = true
+  units = Units{Temperature: "f"}
+  obs := wideFixture()
+  obs.Current_observation.UV = "0"
+  obs.Current_observation.Solarradiation = "-9999"
+  obs.Current_observation.Relative_humidity = "NA"
+
+  data := OperationData(obs, "conditions").(map[string]interface{})
+  if _, ok := data["uv"]; ok {
+    t.Errorf("JSON has a zero UV: %v", data["uv"])
+  }
+  if _, ok := data["solarradiation"]; ok {
+    t.Errorf("JSON has an unreported solar radiation: %v", data["solarradiation"])
+  }
+  if data["temp_f"] != "72.3" {
+    t.Errorf("JSON temp_f = %v, want 72.3", data["temp_f"])
+  }
+
+  t.Setenv("COLUMNS", "120")
+  var buf bytes.Buffer
+  PrintConditionsWide(obs, &units, &buf)
+  if out := buf.String(); strings.Contains(out, "UV index") || strings.Contains(out, "Solar radiation") || !strings.Contains(out, "Temperature: 72.3 F") {
+    t.Errorf("wide layout didn't omit only the unreported fields:\n%s", out)
+  }
+
+  obs.Current_observation.Temp_f = "0"
+  out := captureStdout(t, func() { PrintConditions(obs, &units, os.Stdout) })
+  if strings.Contains(out, "Temperature:") || strings.Contains(out, "Relative humidity") {
+    t.Errorf("a zero temperature or an unreported humidity was shown:\n%s", out)
+  }
+
+  // Without -omit-zero, a reading of zero is shown like any other
+  omitzero = false
+  out = captureStdout(t, func() { PrintConditions(obs, &units, os.Stdout) })
+  if !strings.Contains(out, "Temperature: 0 F") {
+    t.Errorf("a temperature of 0 F was omitted without -omit-zero:\n%s", out)
+  }
+}
+
+func TestOmitted(t *testing.T) {
+  saved := omitzero
+  t.Cleanup(func() { omitzero = saved })
+  tests := []struct {
+    value string
+    want  bool
+  }{
+    {"", true},
+    {"NA", true},
+    {"--", true},
+    {"0", true},
+    {"0.0", true},
+    {"0%", true},
+    {"-999", true},
+    {"-9999", true},
+    {"-9999.00", true},
+    {"72.3", false},
+    {"-9", false},
+    {"45%", false},
+    {"Partly Cloudy", false},
+  }
+  for _, tt := range tests {
+    omitzero = true
+    if got := omitted(tt.value); got != tt.want {
+      t.Errorf("omitted(%q) = %v, want %v", tt.value, got, tt.want)
+    }
+    omitzero = false
+    if omitted(tt.value) {
+      t.Errorf("omitted(%q) without -omit-zero = true, want false", tt.value)
+    }
+  }
+}
+
+func TestVisibilityFallback(t *testing.T) {
+  tests := []struct {
+    km, mi string
+    want   string
+  }{
+    {"16.1", "10.0", "Visibility: 16.1 km"},
+    {"", "10.0", "Visibility: 10.0 miles"},
+    {"NA", "10.0", "Visibility: 10.0 miles"},
+    {"-9999", "10.0", "Visibility: 10.0 miles"},
+  }
+  for _, tt := range tests {
+    obs := wideFixture()
+    obs.Current_observation.Visibility_km = Value(tt.km)
+    obs.Current_observation.Visibility_mi = tt.mi
+    out := captureStdout(t, func() { PrintConditions(obs, &Units{Temperature: "c", System: MetricOnly}, os.Stdout) })
+    if !strings.Contains(out, tt.want+"\n") {
+      t.Errorf("visibility_km %q: want %q in\n%s", tt.km, tt.want, out)
+    }
+  }
+}
+
+func TestCheckObservationAge(t *testing.T) {
+  now := time.Now().Unix()
+  tests := []struct {
+    epoch string
+    max   int
+    stale bool
+    err   bool
+  }{
+    {strconv.FormatInt(now-10*60, 10), 30, false, false},
+    {strconv.FormatInt(now-45*60, 10), 30, true, false},
+    {strconv.FormatInt(now-45*60, 10), 60, false, false},
+    {"1378062000", 30, true, false},
+    {"", 30, false, true},
+    {"soon", 30, false, true},
+  }
+  for _, tt := range tests {
+    err := CheckObservationAge(tt.epoch, tt.max)
+    stale, ok := err.(*StaleDataError)
+    if ok != tt.stale || (err != nil && !ok) != tt.err {
+      t.Errorf("CheckObservationAge(%q, %d) = %v", tt.epoch, tt.max, err)
+    }
+    if ok && (stale.MaxAge != time.Duration(tt.max)*time.Minute || stale.Age <= stale.MaxAge) {
+      t.Errorf("CheckObservationAge(%q, %d) = %+v", tt.epoch, tt.max, stale)
+    }
+  }
+}
+
+// ageHelperEnv, when set, makes TestAgeHelper run the advisory checks
+// with -conditions-age-warning 30 against an observation made at that
+// epoch, so that the exit status can be checked from another process
+const ageHelperEnv = "WU_TEST_OBSERVATION_EPOCH"
+
+func TestAgeHelper(t *testing.T) {
+  epoch, ok := os.LookupEnv(ageHelperEnv)
+  if !ok {
+    return
+  }
+  agewarn = 30
+  var obs Conditions
+  obs.Current_observation.Observation_epoch = epoch
+  CheckAdvisories(&obs)
+  os.Exit(0)
+}
+
+func TestConditionsAgeWarning(t *testing.T) {
+  tests := []struct {
+    epoch  string
+    status int
+    stderr string
+  }{
+    {strconv.FormatInt(time.Now().Unix()-60, 10), 0, ""},
+    {strconv.FormatInt(time.Now().Unix()-2*60*60, 10), 3, "⚠ Observation is 120 minutes old (threshold: 30 minutes)\n"},
+    {"", 0, "Observation age check skipped: observation time unavailable: \"\"\n"},
+  }
+  for _, tt := range tests {
+    cmd := exec.Command(os.Args[0], "-test.run=^TestAgeHelper$")
+    cmd.Env = append(os.Environ(), ageHelperEnv+"="+tt.epoch)
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+    err := cmd.Run()
+    status := 0
+    var exit *exec.ExitError
+    if errors.As(err, &exit) {
+      status = exit.ExitCode()
+    } else if err != nil {
+      t.Fatal(err)
+    }
+    if status != tt.status || stderr.String() != tt.stderr {
+      t.Errorf("epoch %q: status %d, stderr %q; want %d, %q", tt.epoch, status, stderr.String(), tt.status, tt.stderr)
+    }
+  }
+}
+
+func TestPrintConditionsAllUnits(t *testing.T) {
+  obs := wideFixture()
+  obs.Current_observation.Temp_f, obs.Current_observation.Temp_c = "72", "22.2"
+  obs.Current_observation.Wind_kph = "19.3"
+  var buf bytes.Buffer
+  PrintConditionsAllUnits(obs, &buf)
+  out := buf.String()
+  var temperature string
+  for _, line := range strings.Split(out, "\n") {
+    if strings.HasPrefix(line, "   Temperature:") {
+      temperature = line
+    }
+  }
+  for _, want := range []string{"72 °F", "22.2 °C", "295.3 K"} {
+    if !strings.Contains(temperature, want) {
+      t.Errorf("no %q in %q", want, temperature)
+    }
+  }
+  for _, want := range []string{"12.0 mph", "19.3 km/h", "Force 3", "29.92 inHg", "1013 mb", "101.3 kPa", "1.000 atm"} {
+    if !strings.Contains(out, want) {
+      t.Errorf("no %q in\n%s", want, out)
+    }
+  }
+  // The values line up in columns
+  col := -1
+  for _, line := range strings.Split(out, "\n") {
+    if i := strings.Index(line, ":"); i >= 0 && strings.HasPrefix(line, "   ") {
+      start := i + 1 + len(line[i+1:]) - len(strings.TrimLeft(line[i+1:], " "))
+      if col >= 0 && start != col {
+        t.Errorf("values don't line up:\n%s", out)
+        break
+      }
+      col = start
+    }
+  }
+}
+
+func TestTemperatureChart(t *testing.T) {
+  observation := func(hour, tempi string) Observations {
+    return Observations{Date: Date{Hour: hour}, Tempi: tempi}
+  }
+  var obs Conditions
+  obs.today = []Observations{observation("06", "58"), observation("07", "60")}
+  if chart := temperatureChart(&obs, false); chart != "" {
+    t.Errorf("temperatureChart with 2 hours = %q, want nothing", chart)
+  }
+  obs.today = append(obs.today, observation("07", "61"), observation("08", "64"), observation("09", ""))
+  if chart := temperatureChart(&obs, false); chart != " ▁▃█ (today's trend)" {
+    t.Errorf("temperatureChart = %q, want one block for each hour", chart)
+  }
+}
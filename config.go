@@ -0,0 +1,142 @@
+/*
+* config.go
+*
+* This file is part of wu.  It contains functions related to
+* the --config-init switch (interactive creation of $HOME/.condrc).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:58:20 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bufio"
+  "encoding/json"
+  "fmt"
+  "io"
+  "io/ioutil"
+  "os"
+  "os/exec"
+  "regexp"
+  "strings"
+)
+
+// Weather Underground API keys are 32 letters and digits
+var apiKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]{32}$`)
+
+// ConfFile returns the location of the configuration file
+func ConfFile() string {
+  return os.Getenv("HOME") + "/.condrc"
+}
+
+// InitConfig asks for an API key, default station, and preferred units,
+// reading answers from r and writing prompts to w, and saves them to
+// the configuration file
+func InitConfig(r io.Reader, w io.Writer) error {
+  in := bufio.NewReader(r)
+  ask := func(prompt string) (string, error) {
+    fmt.Fprint(w, prompt)
+    answer, err := in.ReadString('\n')
+    if err != nil && (err != io.EOF || answer == "") {
+      return "", err
+    }
+    return strings.TrimSpace(answer), nil
+  }
+
+  if _, err := os.Stat(ConfFile()); err == nil {
+    answer, err := ask(ConfFile() + " already exists.  Overwrite it? [y/N] ")
+    if err != nil {
+      return err
+    }
+    if strings.ToLower(answer) != "y" && strings.ToLower(answer) != "yes" {
+      fmt.Fprintln(w, "Configuration unchanged.")
+      return nil
+    }
+  }
+
+  var c Config
+  for {
+    restore := hideInput(r)
+    key, err := ask("Weather Underground API key: ")
+    restore()
+    if err != nil {
+      return err
+    }
+    if apiKeyPattern.MatchString(key) {
+      c.Key = key
+      break
+    }
+    fmt.Fprintln(w, "\nThat doesn't look like an API key (32 letters and digits).")
+  }
+  fmt.Fprintln(w)
+
+  station, err := ask("Default station [" + defaultStation + "]: ")
+  if err != nil {
+    return err
+  }
+  if station == "" {
+    station = defaultStation
+  }
+  c.Station = station
+
+  for {
+    u, err := ask("Preferred units (imperial, metric, or both) [both]: ")
+    if err != nil {
+      return err
+    }
+    if u == "imperial" || u == "metric" {
+      c.Units = u
+      break
+    } else if u == "" || u == "both" {
+      break
+    }
+  }
+
+  b, err := json.MarshalIndent(c, "", "  ")
+  if err != nil {
+    return err
+  }
+  if err := ioutil.WriteFile(ConfFile(), append(b, '\n'), 0600); err != nil {
+    return err
+  }
+  fmt.Fprintln(w, "Wrote "+ConfFile())
+  return nil
+}
+
+// hideInput turns off terminal echo while the API key is typed (when r
+// is a terminal) and returns a function that turns it back on
+func hideInput(r io.Reader) func() {
+  f, ok := r.(*os.File)
+  if !ok {
+    return func() {}
+  }
+  if fi, err := f.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+    return func() {}
+  }
+  stty := func(arg string) {
+    cmd := exec.Command("stty", arg)
+    cmd.Stdin = f
+    cmd.Run()
+  }
+  stty("-echo")
+  return func() { stty("echo") }
+}
@@ -0,0 +1,118 @@
+/*
+* config_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the configuration wizard (config.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:49 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+)
+
+const testKey = "0123456789abcdef0123456789ABCDEF"
+
+func TestInitConfig(t *testing.T) {
+  tests := []struct {
+    name     string
+    existing string
+    input    string
+    want     *Config // nil if the file should be left alone
+  }{
+    {"defaults", "", testKey + "\n\n\n", &Config{Key: testKey, Station: defaultStation}},
+    {"answers", "", testKey + "\nKMSP\nmetric\n", &Config{Key: testKey, Station: "KMSP", Units: "metric"}},
+    {"bad key retried", "", "0123456789abcdef\nnot a key\n" + testKey + "\nKMSP\nimperial\n", &Config{Key: testKey, Station: "KMSP", Units: "imperial"}},
+    {"bad units retried", "", testKey + "\nKMSP\nkelvin\nboth\n", &Config{Key: testKey, Station: "KMSP"}},
+    {"overwrite", `{"key":"old"}`, "y\n" + testKey + "\nKMSP\n\n", &Config{Key: testKey, Station: "KMSP"}},
+    {"keep", `{"key":"old"}`, "n\n", nil},
+    {"malformed", `{"key":`, "yes\n" + testKey + "\n\n\n", &Config{Key: testKey, Station: defaultStation}},
+  }
+  for _, tt := range tests {
+    home := t.TempDir()
+    t.Setenv("HOME", home)
+    path := filepath.Join(home, ".condrc")
+    if tt.existing != "" {
+      if err := ioutil.WriteFile(path, []byte(tt.existing), 0600); err != nil {
+        t.Fatal(err)
+      }
+    }
+    var out bytes.Buffer
+    if err := InitConfig(bytes.NewReader([]byte(tt.input)), &out); err != nil {
+      t.Errorf("%s: InitConfig: %v", tt.name, err)
+      continue
+    }
+    b, err := ioutil.ReadFile(path)
+    if err != nil {
+      t.Errorf("%s: %v", tt.name, err)
+      continue
+    }
+    if tt.want == nil {
+      if string(b) != tt.existing || !strings.Contains(out.String(), "Configuration unchanged.") {
+        t.Errorf("%s: file = %q, output %q; want it unchanged", tt.name, b, out.String())
+      }
+      continue
+    }
+    var got Config
+    if err := json.Unmarshal(b, &got); err != nil {
+      t.Errorf("%s: wrote %q: %v", tt.name, b, err)
+      continue
+    }
+    if got != *tt.want {
+      t.Errorf("%s: wrote %+v, want %+v", tt.name, got, *tt.want)
+    }
+    if fi, _ := os.Stat(path); fi.Mode().Perm() != 0600 {
+      t.Errorf("%s: mode = %v, want 0600", tt.name, fi.Mode().Perm())
+    }
+  }
+}
+
+func TestInitConfigEOF(t *testing.T) {
+  t.Setenv("HOME", t.TempDir())
+  var out bytes.Buffer
+  if err := InitConfig(bytes.NewReader([]byte("short\n")), &out); err == nil {
+    t.Error("InitConfig succeeded without a valid key")
+  }
+}
+
+// A malformed .condrc mustn't stop wu before -config-init can fix it
+func TestReadConfMalformed(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  if err := ioutil.WriteFile(filepath.Join(home, ".condrc"), []byte(`{"key":`), 0600); err != nil {
+    t.Fatal(err)
+  }
+  defer func(c Config, n bool, e error) { conf, noconf, confErr = c, n, e }(conf, noconf, confErr)
+  conf, noconf, confErr = Config{}, false, nil
+  ReadConf()
+  if noconf || confErr == nil {
+    t.Errorf("ReadConf: noconf = %v, confErr = %v; want the parse error saved", noconf, confErr)
+  }
+}
@@ -0,0 +1,167 @@
+/*
+* csv.go
+*
+* This file is part of wu.  It contains functions related to
+* the --format csv and tsv switches, --csv-header, and
+* --station-list-csv.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:37:02 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/csv"
+  "fmt"
+  "io"
+  "os"
+  "strconv"
+)
+
+// newCSVWriter returns a CSV writer for format ("csv" or "tsv")
+func newCSVWriter(format string, w io.Writer) *csv.Writer {
+  cw := csv.NewWriter(w)
+  if format == "tsv" {
+    cw.Comma = '\t'
+  }
+  return cw
+}
+
+// csvColumns are the columns of --format csv, in order.  The columns
+// are the same whatever the station reports, so that rows from
+// different runs (or stations) line up; missing values are left empty.
+var csvColumns = []struct {
+  name  string
+  value func(c *Current) string
+}{
+  {"observation_epoch", func(c *Current) string { return c.Observation_epoch }},
+  {"station_id", func(c *Current) string { return c.Station_id }},
+  {"weather", func(c *Current) string { return c.Weather }},
+  {"temp_f", func(c *Current) string { return string(c.Temp_f) }},
+  {"temp_c", func(c *Current) string { return string(c.Temp_c) }},
+  {"relative_humidity", func(c *Current) string { return c.Relative_humidity }},
+  {"wind_dir", func(c *Current) string { return c.Wind_dir }},
+  {"wind_degrees", func(c *Current) string { return string(c.Wind_degrees) }},
+  {"wind_mph", func(c *Current) string { return string(c.Wind_mph) }},
+  {"wind_gust_mph", func(c *Current) string { return string(c.Wind_gust_mph) }},
+  {"wind_kph", func(c *Current) string { return string(c.Wind_kph) }},
+  {"wind_gust_kph", func(c *Current) string { return string(c.Wind_gust_kph) }},
+  {"pressure_mb", func(c *Current) string { return c.Pressure_mb }},
+  {"pressure_in", func(c *Current) string { return c.Pressure_in }},
+  {"pressure_trend", func(c *Current) string { return c.Pressure_trend }},
+  {"dewpoint_f", func(c *Current) string { return string(c.Dewpoint_f) }},
+  {"dewpoint_c", func(c *Current) string { return string(c.Dewpoint_c) }},
+  {"heat_index_string", func(c *Current) string { return c.Heat_index_string }},
+  {"windchill_string", func(c *Current) string { return c.Windchill_string }},
+  {"visibility_mi", func(c *Current) string { return c.Visibility_mi }},
+  {"visibility_km", func(c *Current) string { return string(c.Visibility_km) }},
+  {"uv", func(c *Current) string { return string(c.UV) }},
+  {"precip_today_in", func(c *Current) string { return string(c.Precip_today_in) }},
+  {"precip_today_metric", func(c *Current) string { return string(c.Precip_today_metric) }},
+}
+
+// PrintConditionsCSV writes the current conditions as a single row,
+// preceded by a row of column names if header is set
+func PrintConditionsCSV(obs *Conditions, format string, header bool, w io.Writer) error {
+  names := make([]string, len(csvColumns))
+  row := make([]string, len(csvColumns))
+  for i, col := range csvColumns {
+    names[i] = col.name
+    row[i] = col.value(&obs.Current_observation)
+  }
+  cw := newCSVWriter(format, w)
+  if header {
+    cw.Write(names)
+  }
+  cw.Write(row)
+  cw.Flush()
+  return cw.Error()
+}
+
+// PrintHistoryCSV writes one row per day of a --history-range,
+// preceded by a row of column names if header is set.  Days with no
+// data have empty values.
+func PrintHistoryCSV(days []HistoryDay, format string, header bool, w io.Writer) error {
+  cw := newCSVWriter(format, w)
+  if header {
+    cw.Write([]string{"date", "maxtempi", "maxtempm", "mintempi", "mintempm", "precipi", "precipm"})
+  }
+  for _, day := range days {
+    s := day.Summary
+    cw.Write([]string{day.Date.Format("2006-01-02"), s.Maxtempi, s.Maxtempm, s.Mintempi, s.Mintempm, s.Precipi, s.Precipm})
+  }
+  cw.Flush()
+  return cw.Error()
+}
+
+// stationRow returns the CSV columns for a nearby station of the
+// given type ("airport" or "pws")
+func stationRow(obs *Conditions, s *Station, stationType string) []string {
+  id, name := s.Icao, s.City+" Airport"
+  if stationType == "pws" {
+    id, name = s.Id, s.Neighborhood
+  }
+  distance := string(s.Distance_mi)
+  if distance == "" {
+    lat, ok1 := s.Lat.Float()
+    lon, ok2 := s.Lon.Float()
+    qlat, err1 := strconv.ParseFloat(obs.Location.Lat, 64)
+    qlon, err2 := strconv.ParseFloat(obs.Location.Lon, 64)
+    if ok1 && ok2 && err1 == nil && err2 == nil {
+      distance = fmt.Sprintf("%.1f", HaversineDistance(qlat, qlon, lat, lon)/kmPerMile)
+    }
+  }
+  return []string{id, name, s.City, s.State, s.Country, string(s.Lat), string(s.Lon), distance, stationType}
+}
+
+// ExportStationsCSV writes the nearby stations from geolookup to w as
+// CSV, airports first.  With no stations it writes just the header.
+func ExportStationsCSV(obs *Conditions, w io.Writer) error {
+  cw := csv.NewWriter(w)
+  cw.Write([]string{"station_id", "station_name", "city", "state", "country", "lat", "lon", "distance_mi", "station_type"})
+  nearby := obs.Location.Nearby_weather_stations
+  for i := range nearby.Airport.Station {
+    cw.Write(stationRow(obs, &nearby.Airport.Station[i], "airport"))
+  }
+  for i := range nearby.Pws.Station {
+    cw.Write(stationRow(obs, &nearby.Pws.Station[i], "pws"))
+  }
+  cw.Flush()
+  return cw.Error()
+}
+
+// ExportStationsCSVFile writes ExportStationsCSV to the file at path,
+// or to standard output if path is empty
+func ExportStationsCSVFile(obs *Conditions, path string) error {
+  if path == "" {
+    return ExportStationsCSV(obs, os.Stdout)
+  }
+  f, err := os.Create(path)
+  if err != nil {
+    return err
+  }
+  if err := ExportStationsCSV(obs, f); err != nil {
+    f.Close()
+    return err
+  }
+  return f.Close()
+}
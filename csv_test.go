@@ -0,0 +1,183 @@
+/*
+* csv_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --format csv and tsv (csv.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:11:42 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/csv"
+  "encoding/json"
+  "io/ioutil"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestPrintConditionsCSV(t *testing.T) {
+  var obs Conditions
+  obs.Current_observation = Current{Station_id: "KLNK", Temp_f: "72.3", Weather: "Clear, \"mostly\""}
+  tests := []struct {
+    format string
+    header bool
+    rows   int
+  }{
+    {"csv", false, 1},
+    {"csv", true, 2},
+    {"tsv", false, 1},
+    {"tsv", true, 2},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    if err := PrintConditionsCSV(&obs, tt.format, tt.header, &buf); err != nil {
+      t.Fatal(err)
+    }
+    r := csv.NewReader(&buf)
+    if tt.format == "tsv" {
+      r.Comma = '\t'
+    }
+    rows, err := r.ReadAll()
+    if err != nil {
+      t.Fatalf("%s output doesn't parse: %v", tt.format, err)
+    }
+    if len(rows) != tt.rows {
+      t.Errorf("PrintConditionsCSV(%s, header=%v) wrote %d rows, want %d", tt.format, tt.header, len(rows), tt.rows)
+      continue
+    }
+    data := rows[len(rows)-1]
+    if len(data) != len(csvColumns) {
+      t.Errorf("%s row has %d columns, want %d", tt.format, len(data), len(csvColumns))
+    }
+    if tt.header && (rows[0][1] != "station_id" || data[1] != "KLNK" || data[2] != obs.Current_observation.Weather) {
+      t.Errorf("%s header %q and row %q don't line up", tt.format, rows[0][:3], data[:3])
+    }
+  }
+}
+
+func TestPrintConditionsCSVSameColumns(t *testing.T) {
+  // A station that reports less still writes every column
+  var full, sparse Conditions
+  full.Current_observation = Current{Station_id: "KLNK", Temp_f: "72.3", UV: "4", Pressure_in: "29.92"}
+  sparse.Current_observation = Current{Station_id: "KOMA", UV: "5"}
+  var buf bytes.Buffer
+  PrintConditionsCSV(&full, "csv", true, &buf)
+  PrintConditionsCSV(&sparse, "csv", false, &buf)
+  rows, err := csv.NewReader(&buf).ReadAll()
+  if err != nil {
+    t.Fatal(err)
+  }
+  uv := -1
+  for i, name := range rows[0] {
+    if name == "uv" {
+      uv = i
+    }
+  }
+  if uv < 0 || rows[1][uv] != "4" || rows[2][uv] != "5" {
+    t.Errorf("uv column isn't stable across rows: %q", rows)
+  }
+}
+
+func TestPrintHistoryCSV(t *testing.T) {
+  days := []HistoryDay{
+    {Date: time.Date(2013, 9, 1, 0, 0, 0, 0, time.UTC), Summary: Dailysummary{Maxtempi: "88", Precipi: "0.10"}},
+    {Date: time.Date(2013, 9, 2, 0, 0, 0, 0, time.UTC)},
+  }
+  for _, header := range []bool{false, true} {
+    var buf bytes.Buffer
+    if err := PrintHistoryCSV(days, "csv", header, &buf); err != nil {
+      t.Fatal(err)
+    }
+    rows, err := csv.NewReader(&buf).ReadAll()
+    if err != nil {
+      t.Fatal(err)
+    }
+    want := len(days)
+    if header {
+      want++
+    }
+    if len(rows) != want {
+      t.Errorf("PrintHistoryCSV(header=%v) wrote %d rows, want %d", header, len(rows), want)
+    }
+    if last := rows[len(rows)-1]; last[0] != "2013-09-02" || last[1] != "" {
+      t.Errorf("a day with no data = %q", last)
+    }
+  }
+}
+
+func TestExportStationsCSV(t *testing.T) {
+  var obs Conditions
+  err := json.Unmarshal([]byte(`{"location": {"lat": "40.81", "lon": "-96.70", "nearby_weather_stations": {
+    "airport": {"station": [{"city": "Lincoln", "state": "NE", "country": "US", "icao": "KLNK", "lat": "40.85", "lon": "-96.75"}]},
+    "pws": {"station": [
+      {"id": "KNELINCO12", "neighborhood": "Near South", "city": "Lincoln", "state": "NE", "country": "US",
+        "lat": 40.80, "lon": -96.69, "distance_mi": 1},
+      {"id": "KNELINCO40", "neighborhood": "Havelock, \"east\"", "city": "Lincoln", "state": "NE", "country": "US",
+        "lat": "40.85", "lon": "-96.62", "distance_mi": "5"}]}}}}`), &obs)
+  if err != nil {
+    t.Fatal(err)
+  }
+  var buf bytes.Buffer
+  if err := ExportStationsCSV(&obs, &buf); err != nil {
+    t.Fatal(err)
+  }
+  rows, err := csv.NewReader(&buf).ReadAll()
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(rows) != 4 {
+    t.Fatalf("CSV has %d rows, want 4: %q", len(rows), rows)
+  }
+  for i, row := range rows {
+    if len(row) != 9 {
+      t.Errorf("row %d has %d fields, want 9: %q", i, len(row), row)
+    }
+  }
+  if rows[0][0] != "station_id" || rows[0][8] != "station_type" {
+    t.Errorf("header = %q", rows[0])
+  }
+  if strings.Join(rows[1], ",") != "KLNK,Lincoln Airport,Lincoln,NE,US,40.85,-96.75,3.8,airport" {
+    t.Errorf("airport row = %q", rows[1])
+  }
+  if rows[2][0] != "KNELINCO12" || rows[2][7] != "1" || rows[3][1] != `Havelock, "east"` || rows[3][8] != "pws" {
+    t.Errorf("pws rows = %q, %q", rows[2], rows[3])
+  }
+}
+
+func TestExportStationsCSVEmpty(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "stations.csv")
+  if err := ExportStationsCSVFile(&Conditions{}, path); err != nil {
+    t.Fatal(err)
+  }
+  b, err := ioutil.ReadFile(path)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if want := "station_id,station_name,city,state,country,lat,lon,distance_mi,station_type\n"; string(b) != want {
+    t.Errorf("empty station list = %q, want just the header", b)
+  }
+}
@@ -0,0 +1,126 @@
+/*
+* emoji.go
+*
+* This file is part of wu.  It contains functions related to
+* the --emoji-summary switch (a one-character weather status).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:48:22 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "os"
+  "strings"
+  "unicode"
+)
+
+// iconEmoji maps the API's icon names to an emoji and a two-letter
+// ASCII code (after METAR's) for terminals without UTF-8
+var iconEmoji = map[string][2]string{
+  "clear":          {"☀️", "CL"},
+  "sunny":          {"☀️", "CL"},
+  "mostlysunny":    {"🌤", "MS"},
+  "partlysunny":    {"⛅", "PC"},
+  "partlycloudy":   {"⛅", "PC"},
+  "mostlycloudy":   {"🌥", "MC"},
+  "cloudy":         {"☁️", "OV"},
+  "hazy":           {"🌫", "HZ"},
+  "fog":            {"🌫", "FG"},
+  "chancerain":     {"🌦", "RA"},
+  "rain":           {"🌧", "RA"},
+  "chancetstorms":  {"⛈", "TS"},
+  "tstorms":        {"⛈", "TS"},
+  "chancesleet":    {"🌨", "PL"},
+  "sleet":          {"🌨", "PL"},
+  "chanceflurries": {"🌨", "SN"},
+  "flurries":       {"🌨", "SN"},
+  "chancesnow":     {"🌨", "SN"},
+  "snow":           {"❄️", "SN"},
+}
+
+// nightEmoji replaces the emoji for clear night-time icons
+var nightEmoji = map[string]string{"clear": "🌙", "sunny": "🌙", "mostlysunny": "🌙"}
+
+// utf8Locale returns the locale from the environment, and whether it
+// uses UTF-8
+func utf8Locale() (string, bool) {
+  for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+    if lang := os.Getenv(name); lang != "" {
+      l := strings.ToLower(lang)
+      return lang, strings.Contains(l, "utf-8") || strings.Contains(l, "utf8")
+    }
+  }
+  return "", false
+}
+
+// BestIcon returns the emoji for icon (e.g. "nt_partlycloudy"), or its
+// ASCII code unless supportUTF8 is set.  The "C" and "POSIX" locales
+// (lang) always get ASCII, as status bars often run under them.
+func BestIcon(icon, lang string, supportUTF8 bool) string {
+  night := strings.HasPrefix(icon, "nt_")
+  icon = strings.TrimPrefix(icon, "nt_")
+  e, ok := iconEmoji[icon]
+  if !ok {
+    e = [2]string{"❓", "??"}
+  }
+  if !supportUTF8 || lang == "C" || lang == "POSIX" {
+    return e[1]
+  }
+  if n, ok := nightEmoji[icon]; ok && night {
+    return n
+  }
+  return e[0]
+}
+
+// PrintEmojiSummary prints the current conditions as a single emoji
+// (with no newline under -quiet, for status bars)
+func PrintEmojiSummary(obs *Conditions, w io.Writer) {
+  lang, supportUTF8 := utf8Locale()
+  icon := BestIcon(obs.Current_observation.Icon, lang, supportUTF8)
+  if quiet {
+    fmt.Fprint(w, icon)
+  } else {
+    fmt.Fprintln(w, icon)
+  }
+}
+
+// displayWidth returns the number of terminal columns s takes up.
+// Emoji and East Asian wide characters take two; variation selectors,
+// joiners, and combining marks take none.
+func displayWidth(s string) int {
+  width := 0
+  for _, r := range s {
+    switch {
+    case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || unicode.Is(unicode.Variation_Selector, r):
+    case r >= 0x2600 && r <= 0x27BF, r >= 0x1F000, // symbols and emoji
+      r >= 0x1100 && r <= 0x115F, r >= 0x2E80 && r <= 0xA4CF, r >= 0xAC00 && r <= 0xD7A3,
+      r >= 0xF900 && r <= 0xFAFF, r >= 0xFF00 && r <= 0xFF60, r >= 0xFFE0 && r <= 0xFFE6:
+      width += 2
+    default:
+      width++
+    }
+  }
+  return width
+}
@@ -0,0 +1,99 @@
+/*
+* emoji_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for -emoji-summary (emoji.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:38 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "testing"
+  "unicode"
+)
+
+func TestBestIconASCII(t *testing.T) {
+  icons := []string{"unknown", "nt_clear"}
+  for icon := range iconEmoji {
+    icons = append(icons, icon, "nt_"+icon)
+  }
+  for _, icon := range icons {
+    got := BestIcon(icon, "en_US", false)
+    if len(got) != 2 {
+      t.Errorf("BestIcon(%q) without UTF-8 = %q, want a two-letter code", icon, got)
+    }
+    for _, r := range got {
+      if r > unicode.MaxASCII {
+        t.Errorf("BestIcon(%q) without UTF-8 = %q, which isn't ASCII", icon, got)
+      }
+    }
+  }
+}
+
+func TestBestIcon(t *testing.T) {
+  tests := []struct {
+    icon, lang  string
+    supportUTF8 bool
+    want        string
+  }{
+    {"tstorms", "en_US.UTF-8", true, "⛈"},
+    {"tstorms", "en_US", false, "TS"},
+    {"snow", "en_US.UTF-8", false, "SN"},
+    {"rain", "C", true, "RA"},
+    {"fog", "POSIX", true, "FG"},
+    {"partlycloudy", "en_US.UTF-8", true, "⛅"},
+    {"nt_clear", "en_US.UTF-8", true, "🌙"},
+    {"nt_partlycloudy", "en_US.UTF-8", true, "⛅"},
+    {"nt_clear", "C", true, "CL"},
+    {"volcano", "en_US.UTF-8", true, "❓"},
+  }
+  for _, tt := range tests {
+    if got := BestIcon(tt.icon, tt.lang, tt.supportUTF8); got != tt.want {
+      t.Errorf("BestIcon(%q, %q, %v) = %q, want %q", tt.icon, tt.lang, tt.supportUTF8, got, tt.want)
+    }
+  }
+}
+
+func TestPrintEmojiSummary(t *testing.T) {
+  saved := quiet
+  t.Cleanup(func() { quiet = saved })
+  t.Setenv("LC_ALL", "")
+  t.Setenv("LC_CTYPE", "")
+  t.Setenv("LANG", "C")
+  var obs Conditions
+  obs.Current_observation.Icon = "cloudy"
+  for _, q := range []bool{false, true} {
+    quiet = q
+    var buf bytes.Buffer
+    PrintEmojiSummary(&obs, &buf)
+    want := "OV\n"
+    if q {
+      want = "OV"
+    }
+    if buf.String() != want {
+      t.Errorf("quiet %v: printed %q, want %q", q, buf.String(), want)
+    }
+  }
+}
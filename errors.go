@@ -0,0 +1,145 @@
+/*
+* errors.go
+*
+* This file is part of wu.  It contains functions related to
+* error reporting and the --script-mode switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:21:50 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "errors"
+  "fmt"
+  "os"
+  "regexp"
+)
+
+// ErrorCode classifies a failure for --script-mode.  Each code is
+// also the exit status used for that kind of failure.
+type ErrorCode int
+
+const (
+  ConfigMissing ErrorCode = iota + 1
+  NetworkError
+  APIError
+  InvalidInput
+  QuotaExceeded
+)
+
+var errorCodeNames = map[ErrorCode]string{
+  ConfigMissing: "CONFIG_MISSING",
+  NetworkError:  "NETWORK_ERROR",
+  APIError:      "API_ERROR",
+  InvalidInput:  "INVALID_INPUT",
+  QuotaExceeded: "QUOTA_EXCEEDED",
+}
+
+func (c ErrorCode) String() string {
+  return errorCodeNames[c]
+}
+
+// ScriptError is an error tagged with the ErrorCode it should be
+// reported as
+type ScriptError struct {
+  Code ErrorCode
+  Err  error
+}
+
+func (e *ScriptError) Error() string {
+  return e.Err.Error()
+}
+
+func (e *ScriptError) Unwrap() error {
+  return e.Err
+}
+
+// Classify tags err (if there is one) with code
+func Classify(code ErrorCode, err error) error {
+  if err == nil {
+    return nil
+  }
+  return &ScriptError{code, err}
+}
+
+// codeOf returns the ErrorCode for err.  Errors that were never
+// classified are blamed on the API, since that's where most of them
+// come from (e.g. malformed responses).
+func codeOf(err error) ErrorCode {
+  var se *ScriptError
+  if errors.As(err, &se) {
+    return se.Code
+  }
+  return APIError
+}
+
+// CheckError exits on error with a message
+func CheckError(err error) {
+  if err != nil {
+    if scriptmode {
+      code := codeOf(err)
+      fmt.Fprintf(os.Stderr, "%s: %v\n", code, err)
+      os.Exit(int(code))
+    }
+    fmt.Fprintf(os.Stderr, "Fatal error\n%v\n", err)
+    os.Exit(1)
+  }
+}
+
+// Fail prints msg and exits.  Outside of script mode this is how wu
+// has always reported usage problems (on stdout, with status 0); in
+// script mode msg is reported like any other error.
+func Fail(code ErrorCode, msg string) {
+  if scriptmode {
+    fmt.Fprintf(os.Stderr, "%s: %s\n", code, msg)
+    os.Exit(int(code))
+  }
+  fmt.Println(msg)
+  os.Exit(0)
+}
+
+// Response is the header of every API reply.  Error is filled in
+// when the API refuses a request (bad key, unknown location, etc.).
+type Response struct {
+  Error Response_error `json:"error"`
+}
+
+type Response_error struct {
+  Type        string `json:"type"`
+  Description string `json:"description"`
+}
+
+var quotaPattern = regexp.MustCompile(`(?i)limit|quota`)
+
+// Err returns the API's error (if any) as a ScriptError
+func (r Response) Err() error {
+  e := r.Error
+  if e.Type == "" {
+    return nil
+  }
+  err := fmt.Errorf("%s: %s", e.Type, e.Description)
+  if quotaPattern.MatchString(e.Type + " " + e.Description) {
+    return Classify(QuotaExceeded, err)
+  }
+  return Classify(APIError, err)
+}
@@ -0,0 +1,185 @@
+/*
+* errors_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for error reporting and --script-mode (errors.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:58 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "errors"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "os/exec"
+  "strconv"
+  "testing"
+)
+
+// exitHelperEnv, when set, makes TestExitHelper report an error the
+// way wu does and exit, so the exit status and output can be checked
+// from another process.  It holds the ErrorCode, and exitHelperScript
+// whether to use script mode.
+const (
+  exitHelperEnv    = "WU_TEST_EXIT_CODE"
+  exitHelperScript = "WU_TEST_SCRIPT_MODE"
+  exitHelperFail   = "WU_TEST_FAIL"
+)
+
+func TestExitHelper(t *testing.T) {
+  code, err := strconv.Atoi(os.Getenv(exitHelperEnv))
+  if err != nil {
+    return
+  }
+  scriptmode = os.Getenv(exitHelperScript) != ""
+  if os.Getenv(exitHelperFail) != "" {
+    Fail(ErrorCode(code), "Usage: wu -something")
+  }
+  CheckError(Classify(ErrorCode(code), errors.New("something went wrong")))
+  os.Exit(99)
+}
+
+// runExitHelper runs TestExitHelper in a new process and returns its
+// exit status, standard out, and standard error
+func runExitHelper(t *testing.T, code ErrorCode, script, fail bool) (int, string, string) {
+  cmd := exec.Command(os.Args[0], "-test.run=^TestExitHelper$")
+  cmd.Env = append(os.Environ(), exitHelperEnv+"="+strconv.Itoa(int(code)))
+  if script {
+    cmd.Env = append(cmd.Env, exitHelperScript+"=1")
+  }
+  if fail {
+    cmd.Env = append(cmd.Env, exitHelperFail+"=1")
+  }
+  var stdout, stderr bytes.Buffer
+  cmd.Stdout, cmd.Stderr = &stdout, &stderr
+  err := cmd.Run()
+  status := 0
+  var exit *exec.ExitError
+  if errors.As(err, &exit) {
+    status = exit.ExitCode()
+  } else if err != nil {
+    t.Fatal(err)
+  }
+  return status, stdout.String(), stderr.String()
+}
+
+func TestCheckErrorScriptMode(t *testing.T) {
+  tests := []struct {
+    code   ErrorCode
+    status int
+    stderr string
+  }{
+    {ConfigMissing, 1, "CONFIG_MISSING: something went wrong\n"},
+    {NetworkError, 2, "NETWORK_ERROR: something went wrong\n"},
+    {APIError, 3, "API_ERROR: something went wrong\n"},
+    {InvalidInput, 4, "INVALID_INPUT: something went wrong\n"},
+    {QuotaExceeded, 5, "QUOTA_EXCEEDED: something went wrong\n"},
+  }
+  for _, tt := range tests {
+    status, stdout, stderr := runExitHelper(t, tt.code, true, false)
+    if status != tt.status || stderr != tt.stderr || stdout != "" {
+      t.Errorf("%s: exit %d, stdout %q, stderr %q; want exit %d, stderr %q", tt.code, status, stdout, stderr, tt.status, tt.stderr)
+    }
+    // Without script mode every error exits 1 as it always has
+    status, _, stderr = runExitHelper(t, tt.code, false, false)
+    if status != 1 || stderr != "Fatal error\nsomething went wrong\n" {
+      t.Errorf("%s without script mode: exit %d, stderr %q", tt.code, status, stderr)
+    }
+  }
+}
+
+func TestFail(t *testing.T) {
+  status, stdout, stderr := runExitHelper(t, InvalidInput, true, true)
+  if status != 4 || stderr != "INVALID_INPUT: Usage: wu -something\n" || stdout != "" {
+    t.Errorf("script mode: exit %d, stdout %q, stderr %q", status, stdout, stderr)
+  }
+  status, stdout, stderr = runExitHelper(t, InvalidInput, false, true)
+  if status != 0 || stdout != "Usage: wu -something\n" || stderr != "" {
+    t.Errorf("without script mode: exit %d, stdout %q, stderr %q", status, stdout, stderr)
+  }
+}
+
+func TestResponseErr(t *testing.T) {
+  tests := []struct {
+    err  Response_error
+    code ErrorCode // 0 for no error
+  }{
+    {Response_error{}, 0},
+    {Response_error{"keynotfound", "this key does not exist"}, APIError},
+    {Response_error{"querynotfound", "No cities match your search query"}, APIError},
+    {Response_error{"invalidkey", "daily request limit exceeded"}, QuotaExceeded},
+  }
+  for _, tt := range tests {
+    err := Response{tt.err}.Err()
+    if tt.code == 0 {
+      if err != nil {
+        t.Errorf("Err() for %+v = %v, want nil", tt.err, err)
+      }
+      continue
+    }
+    if err == nil || codeOf(err) != tt.code {
+      t.Errorf("Err() for %+v = %v, want a %s", tt.err, err, tt.code)
+    }
+  }
+}
+
+func TestFetchStatus(t *testing.T) {
+  tests := []struct {
+    status int
+    code   ErrorCode // 0 for success
+  }{
+    {http.StatusOK, 0},
+    {http.StatusTooManyRequests, QuotaExceeded},
+    {http.StatusInternalServerError, APIError},
+    {http.StatusNotFound, APIError},
+  }
+  for _, tt := range tests {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      w.WriteHeader(tt.status)
+      w.Write([]byte("{}"))
+    }))
+    b, err := Fetch(srv.URL)
+    srv.Close()
+    if tt.code == 0 {
+      if err != nil || string(b) != "{}" {
+        t.Errorf("Fetch with status %d = %q, %v", tt.status, b, err)
+      }
+      continue
+    }
+    if err == nil || codeOf(err) != tt.code {
+      t.Errorf("Fetch with status %d: error %v, want a %s", tt.status, err, tt.code)
+    }
+  }
+}
+
+func TestFetchNetworkError(t *testing.T) {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+  srv.Close()
+  b, err := Fetch(srv.URL)
+  if err == nil || b != nil || codeOf(err) != NetworkError {
+    t.Errorf("Fetch of a closed server = %q, %v; want a %s", b, err, NetworkError)
+  }
+}
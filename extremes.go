@@ -0,0 +1,165 @@
+/*
+* extremes.go
+*
+* This file is part of wu.  It contains functions related to
+* the --history-extremes switch (monthly and all-time records).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:40:15 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "strconv"
+  "sync"
+  "time"
+)
+
+// Extremes holds the most extreme values found across a station's
+// monthly summaries, and the month in which each occurred
+type Extremes struct {
+  HottestTemp   float64
+  HottestDate   string
+  ColdestTemp   float64
+  ColdestDate   string
+  WettestPrecip float64
+  WettestDate   string
+}
+
+// FetchMonthlySummaries retrieves a planner summary for each month of
+// the year, no more than -history-rate a minute.  (The almanac only
+// covers today's date, but the planner reports the extremes over the
+// whole period of record.)  The planner is limited to 30 days, so
+// longer months omit their last day.  A request that fails stops the
+// rest and its error is returned.
+func FetchMonthlySummaries(stationId string) ([]*Conditions, error) {
+  months := make([]*Conditions, 12)
+  var wg sync.WaitGroup
+  var mu sync.Mutex
+  var firstErr error
+  fail := func(month time.Month, err error) {
+    mu.Lock()
+    defer mu.Unlock()
+    if firstErr == nil {
+      firstErr = Classify(codeOf(err), fmt.Errorf("planner for %s: %v", month, err))
+    }
+  }
+  failed := func() bool {
+    mu.Lock()
+    defer mu.Unlock()
+    return firstErr != nil
+  }
+
+  throttle := time.NewTicker(time.Minute / time.Duration(historyrate))
+  defer throttle.Stop()
+  for m := 1; m <= 12; m++ {
+    months[m-1] = new(Conditions)
+    if m > 1 {
+      <-throttle.C
+    }
+    if failed() {
+      break
+    }
+    wg.Add(1)
+    go func(m int, obs *Conditions) {
+      defer wg.Done()
+      first := time.Date(2001, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+      last := first.AddDate(0, 1, -1)
+      if last.Day() > 30 {
+        last = last.AddDate(0, 0, -1)
+      }
+      b, err := Fetch(BuildURL([]string{"planner_" + first.Format("0102") + last.Format("0102")}, stationId))
+      if err != nil {
+        fail(first.Month(), err)
+        return
+      }
+      if err := parseJSON(b, obs); err != nil {
+        fail(first.Month(), err)
+        return
+      }
+      if err := obs.Response.Err(); err != nil {
+        fail(first.Month(), err)
+      }
+    }(m, months[m-1])
+  }
+  wg.Wait()
+  return months, firstErr
+}
+
+// FindExtremes finds the hottest, coldest, and wettest of the monthly
+// summaries (which are in calendar order)
+func FindExtremes(almanacs []*Conditions) Extremes {
+  var e Extremes
+  first := true
+  for i, obs := range almanacs {
+    month := time.Month(i + 1).String()
+    high, hErr := strconv.ParseFloat(obs.Trip.Temp_high.Max.F, 64)
+    low, lErr := strconv.ParseFloat(obs.Trip.Temp_low.Min.F, 64)
+    if hErr != nil || lErr != nil {
+      continue
+    }
+    if first || high > e.HottestTemp {
+      e.HottestTemp, e.HottestDate = high, month
+    }
+    if first || low < e.ColdestTemp {
+      e.ColdestTemp, e.ColdestDate = low, month
+    }
+    if precip, err := strconv.ParseFloat(obs.Trip.Precip.Max.In, 64); err == nil && precip > e.WettestPrecip {
+      e.WettestPrecip, e.WettestDate = precip, month
+    }
+    first = false
+  }
+  return e
+}
+
+// PrintExtremes prints a table of monthly records followed by the
+// station's all-time records
+func PrintExtremes(almanacs []*Conditions, stationId string, units *Units) {
+  fmt.Printf("Monthly records for %s\n", stationId)
+  fmt.Printf("   %-10s %-16s %-16s %s\n", "Month", "Record high", "Record low", "Wettest")
+  byMonth := make(map[string]Trip)
+  for i, obs := range almanacs {
+    byMonth[time.Month(i+1).String()] = obs.Trip
+    t := obs.Trip
+    if t.Temp_high.Max.F == "" {
+      fmt.Printf("   %-10s no data available\n", time.Month(i+1))
+      continue
+    }
+    fmt.Printf("   %-10s %-16s %-16s %s\n", time.Month(i+1),
+      units.Temp(t.Temp_high.Max.F, t.Temp_high.Max.C), units.Temp(t.Temp_low.Min.F, t.Temp_low.Min.C),
+      units.Precip(t.Precip.Max.In, t.Precip.Max.Mm()))
+  }
+  e := FindExtremes(almanacs)
+  if e.HottestDate == "" {
+    fmt.Println("No records available")
+    return
+  }
+  fmt.Println("All-time records:")
+  hot, cold := byMonth[e.HottestDate].Temp_high.Max, byMonth[e.ColdestDate].Temp_low.Min
+  fmt.Printf("   Hottest: %s (%s)\n", units.Temp(hot.F, hot.C), e.HottestDate)
+  fmt.Printf("   Coldest: %s (%s)\n", units.Temp(cold.F, cold.C), e.ColdestDate)
+  if e.WettestDate != "" {
+    wet := byMonth[e.WettestDate].Precip.Max
+    fmt.Printf("   Wettest: %s (%s)\n", units.Precip(wet.In, wet.Mm()), e.WettestDate)
+  }
+}
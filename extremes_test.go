@@ -0,0 +1,126 @@
+/*
+* extremes_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the all-time records (extremes.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:16:47 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+)
+
+// monthlyPlanners returns twelve planner responses with the given
+// record highs, lows, and wettest days (in F and inches); "" leaves a
+// month without data
+func monthlyPlanners(t *testing.T, highs, lows, precip []string) []*Conditions {
+  almanacs := make([]*Conditions, 12)
+  for i := range almanacs {
+    doc := `{"trip": {}}`
+    if highs[i] != "" {
+      doc = fmt.Sprintf(`{"trip": {"temp_high": {"max": {"f": %q}}, "temp_low": {"min": {"f": %q}}, "precip": {"max": {"in": %q}}}}`,
+        highs[i], lows[i], precip[i])
+    }
+    almanacs[i] = new(Conditions)
+    if err := json.Unmarshal([]byte(doc), almanacs[i]); err != nil {
+      t.Fatal(err)
+    }
+  }
+  return almanacs
+}
+
+func TestFindExtremes(t *testing.T) {
+  tests := []struct {
+    name              string
+    highs, lows, rain []string
+    want              Extremes
+  }{
+    {
+      "Lincoln",
+      []string{"73", "84", "91", "97", "102", "108", "115", "112", "106", "96", "85", "76"},
+      []string{"-33", "-28", "-19", "5", "23", "39", "45", "41", "24", "5", "-13", "-25"},
+      []string{"1.21", "1.40", "2.80", "4.02", "5.56", "6.23", "5.10", "6.87", "4.51", "3.10", "2.02", "1.45"},
+      Extremes{115, "July", -33, "January", 6.87, "August"},
+    },
+    {
+      "missing months",
+      []string{"", "60", "", "", "", "", "", "", "", "", "", "58"},
+      []string{"", "-2", "", "", "", "", "", "", "", "", "", "-2"},
+      []string{"", "T", "", "", "", "", "", "", "", "", "", "0.50"},
+      Extremes{60, "February", -2, "February", 0.5, "December"},
+    },
+    {
+      "no data",
+      make([]string, 12), make([]string, 12), make([]string, 12),
+      Extremes{},
+    },
+  }
+  for _, tt := range tests {
+    if got := FindExtremes(monthlyPlanners(t, tt.highs, tt.lows, tt.rain)); got != tt.want {
+      t.Errorf("%s: FindExtremes = %+v, want %+v", tt.name, got, tt.want)
+    }
+  }
+}
+
+func TestFetchMonthlySummaries(t *testing.T) {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    if strings.Contains(r.URL.Path, "/planner_0701") {
+      w.Write([]byte(`{"trip": {"temp_high": {"max": {"f": "115"}}}}`))
+      return
+    }
+    w.Write([]byte(`{"trip": {}}`))
+  }))
+  defer srv.Close()
+  url, rate := apiURL, historyrate
+  t.Cleanup(func() { apiURL, historyrate = url, rate })
+  apiURL, historyrate = srv.URL+"/api/", 60000
+
+  almanacs, err := FetchMonthlySummaries("KLNK")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(almanacs) != 12 || almanacs[6].Trip.Temp_high.Max.F != "115" {
+    t.Errorf("FetchMonthlySummaries didn't put July's planner in July: %+v", almanacs)
+  }
+}
+
+func TestFetchMonthlySummariesError(t *testing.T) {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    w.WriteHeader(http.StatusTooManyRequests)
+  }))
+  defer srv.Close()
+  url, rate := apiURL, historyrate
+  t.Cleanup(func() { apiURL, historyrate = url, rate })
+  apiURL, historyrate = srv.URL+"/api/", 60000
+
+  if _, err := FetchMonthlySummaries("KLNK"); codeOf(err) != QuotaExceeded {
+    t.Errorf("FetchMonthlySummaries on a 429 returned %v (code %d), want QuotaExceeded", err, codeOf(err))
+  }
+}
@@ -0,0 +1,132 @@
+/*
+* feels.go
+*
+* This file is part of wu.  It contains functions related to
+* the --humidex and --humidity-comfort switches ("feels like"
+* indexes).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 20:09:17 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "strconv"
+  "strings"
+)
+
+// Humidex returns the Canadian humidex for a temperature and dew point
+// given in Celsius
+func Humidex(tempC, dewpointC float64) float64 {
+  e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.16+dewpointC)))
+  return tempC + 0.5555*(e-10)
+}
+
+// humidexComfort describes how a humidex value feels
+func humidexComfort(h float64) string {
+  switch {
+  case h >= 40:
+    return "dangerous"
+  case h >= 30:
+    return "some discomfort"
+  }
+  return "comfortable"
+}
+
+// PrintHumidex prints the humidex for the current conditions, if the
+// station reports a temperature and dew point
+func PrintHumidex(current *Current, w io.Writer) {
+  t, ok1 := current.Temp_c.Float()
+  dp, ok2 := current.Dewpoint_c.Float()
+  if !ok1 || !ok2 {
+    return
+  }
+  h := Humidex(t, dp)
+  if h < 20 {
+    fmt.Fprintln(w, "   Humidex: No humidex effect.")
+    return
+  }
+  fmt.Fprintf(w, "   Humidex: %.0f (%s)\n", h, humidexComfort(h))
+}
+
+// NWSHeatIndex returns the National Weather Service heat index (F),
+// from the Rothfusz regression with the NWS's adjustments for very dry
+// and very humid air, and whether it applies: it is only defined above
+// 80 F and 40% relative humidity
+func NWSHeatIndex(tempF, relHumidity float64) (float64, bool) {
+  if tempF <= 80 || relHumidity <= 40 {
+    return tempF, false
+  }
+  t, rh := tempF, relHumidity
+  hi := -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh -
+    0.00683783*t*t - 0.05481717*rh*rh + 0.00122874*t*t*rh +
+    0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+  switch {
+  case rh < 13 && t < 112:
+    hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+  case rh > 85 && t < 87:
+    hi += (rh - 85) / 10 * (87 - t) / 5
+  }
+  return hi, true
+}
+
+// HeatIndexCategory returns the NWS category for a heat index (F), or
+// "" below 80 F
+func HeatIndexCategory(hi float64) string {
+  switch {
+  case hi >= 125:
+    return "Extreme Danger"
+  case hi >= 103:
+    return "Danger"
+  case hi >= 90:
+    return "Extreme Caution"
+  case hi >= 80:
+    return "Caution"
+  }
+  return ""
+}
+
+// PrintHeatIndex prints the NWS heat index and its category for the
+// current conditions
+func PrintHeatIndex(current *Current, metric bool, w io.Writer) {
+  t, ok := current.Temp_f.Float()
+  rh, err := strconv.ParseFloat(strings.TrimSuffix(current.Relative_humidity, "%"), 64)
+  if !ok || err != nil {
+    return
+  }
+  hi, ok := NWSHeatIndex(t, rh)
+  if !ok {
+    fmt.Fprintln(w, "   NWS Heat Index: Not applicable (below 80 F or 40% humidity).")
+    return
+  }
+  value := fmt.Sprintf("%.0f F", hi)
+  if metric {
+    value = fmt.Sprintf("%.0f C", (hi-32)*5/9)
+  }
+  if category := HeatIndexCategory(hi); category != "" {
+    value += " (" + category + ")"
+  }
+  fmt.Fprintln(w, "   NWS Heat Index:", value)
+}
@@ -0,0 +1,142 @@
+/*
+* feels_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the humidex and heat index (feels.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:50 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "math"
+  "testing"
+)
+
+func TestHumidex(t *testing.T) {
+  // Environment Canada's humidex table gives 37 for a Toronto summer
+  // afternoon of 30°C with a 20°C dew point, and 34 with a 15°C one
+  if h := Humidex(30, 20); math.Abs(h-37) > 1 {
+    t.Errorf("Humidex(30, 20) = %.2f, want 37 ± 1", h)
+  }
+  if h := Humidex(30, 15); math.Round(h) != 34 {
+    t.Errorf("Humidex(30, 15) = %.2f, want 34", h)
+  }
+}
+
+func TestPrintHumidex(t *testing.T) {
+  tests := []struct {
+    temp, dewpoint Value
+    want           string
+  }{
+    {"30", "20", "   Humidex: 38 (some discomfort)\n"},
+    {"35", "25", "   Humidex: 47 (dangerous)\n"},
+    {"24", "10", "   Humidex: 25 (comfortable)\n"},
+    {"12", "5", "   Humidex: No humidex effect.\n"},
+    {"30", "", ""},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintHumidex(&Current{Temp_c: tt.temp, Dewpoint_c: tt.dewpoint}, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintHumidex(%s°C, %s°C) = %q, want %q", tt.temp, tt.dewpoint, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestNWSHeatIndex(t *testing.T) {
+  // The NWS heat index chart gives 122 F for 90 F at 90% relative
+  // humidity, and 118 F for 100 F at 50%
+  tests := []struct {
+    temp, rh float64
+    want     float64
+    category string
+  }{
+    {90, 90, 122, "Danger"},
+    {100, 50, 118, "Danger"},
+    {86, 50, 88, "Caution"},
+    {94, 60, 110, "Danger"},
+    {104, 55, 137, "Extreme Danger"},
+  }
+  for _, tt := range tests {
+    hi, ok := NWSHeatIndex(tt.temp, tt.rh)
+    if !ok {
+      t.Errorf("NWSHeatIndex(%v, %v) didn't apply", tt.temp, tt.rh)
+      continue
+    }
+    if math.Abs(hi-tt.want) > 1 {
+      t.Errorf("NWSHeatIndex(%v, %v) = %.1f, want %v ± 1", tt.temp, tt.rh, hi, tt.want)
+    }
+    if got := HeatIndexCategory(hi); got != tt.category {
+      t.Errorf("HeatIndexCategory(%.1f) = %q, want %q", hi, got, tt.category)
+    }
+  }
+  for _, c := range []struct{ temp, rh float64 }{{80, 90}, {95, 40}, {70, 60}} {
+    if hi, ok := NWSHeatIndex(c.temp, c.rh); ok || hi != c.temp {
+      t.Errorf("NWSHeatIndex(%v, %v) = %v, %v, want %v, false", c.temp, c.rh, hi, ok, c.temp)
+    }
+  }
+}
+
+func TestHeatIndexCategory(t *testing.T) {
+  tests := []struct {
+    hi   float64
+    want string
+  }{
+    {79, ""},
+    {80, "Caution"},
+    {89.9, "Caution"},
+    {90, "Extreme Caution"},
+    {102.9, "Extreme Caution"},
+    {103, "Danger"},
+    {124.9, "Danger"},
+    {125, "Extreme Danger"},
+  }
+  for _, tt := range tests {
+    if got := HeatIndexCategory(tt.hi); got != tt.want {
+      t.Errorf("HeatIndexCategory(%v) = %q, want %q", tt.hi, got, tt.want)
+    }
+  }
+}
+
+func TestPrintHeatIndex(t *testing.T) {
+  tests := []struct {
+    temp   Value
+    rh     string
+    metric bool
+    want   string
+  }{
+    {"100", "50%", false, "   NWS Heat Index: 118 F (Danger)\n"},
+    {"100", "50%", true, "   NWS Heat Index: 48 C (Danger)\n"},
+    {"75", "50%", false, "   NWS Heat Index: Not applicable (below 80 F or 40% humidity).\n"},
+    {"100", "NA", false, ""},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintHeatIndex(&Current{Temp_f: tt.temp, Relative_humidity: tt.rh}, tt.metric, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintHeatIndex(%s F, %s) = %q, want %q", tt.temp, tt.rh, buf.String(), tt.want)
+    }
+  }
+}
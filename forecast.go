@@ -29,29 +29,573 @@
 package main
 
 import (
+  "errors"
   "fmt"
+  "io"
+  "math"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+  "unicode/utf8"
 )
 
 type Forecast struct {
-  Txt_forecast Txt_forecast
+  Txt_forecast   Txt_forecast   `json:"txt_forecast"`
+  Simpleforecast Simpleforecast `json:"simpleforecast"`
 }
 
 type Txt_forecast struct {
-  Date        string
-  Forecastday []Forecastday
+  Date        string        `json:"date"`
+  Forecastday []Forecastday `json:"forecastday"`
 }
 
 type Forecastday struct {
-  Title   string
-  Fcttext string
+  Title          string `json:"title"`
+  Icon           string `json:"icon"`
+  Fcttext        string `json:"fcttext"`
+  Fcttext_metric string `json:"fcttext_metric"`
+  Pop            Value  `json:"pop"`
+
+  freezing bool // whether the period's low is below freezing (see MarkFreezing)
+}
+
+type Simpleforecast struct {
+  Forecastday []Simpleforecastday `json:"forecastday"`
+}
+
+// Simpleforecastday is one day of the forecast as numbers rather than
+// prose
+type Simpleforecastday struct {
+  Date        Simple_date   `json:"date"`
+  Period      Value         `json:"period"`
+  High        Simple_temp   `json:"high"`
+  Low         Simple_temp   `json:"low"`
+  Conditions  string        `json:"conditions"`
+  Icon        string        `json:"icon"`
+  Pop         Value         `json:"pop"`
+  Qpf_allday  Simple_amount `json:"qpf_allday"`
+  Avewind     Simple_wind   `json:"avewind"`
+  Maxwind     Simple_wind   `json:"maxwind"`
+  Avehumidity Value         `json:"avehumidity"`
+}
+
+type Simple_date struct {
+  Epoch         string `json:"epoch"`
+  Day           Value  `json:"day"`
+  Month         Value  `json:"month"`
+  Year          Value  `json:"year"`
+  Weekday       string `json:"weekday"`
+  Weekday_short string `json:"weekday_short"`
+  Pretty        string `json:"pretty"`
+}
+
+type Simple_temp struct {
+  Fahrenheit Value `json:"fahrenheit"`
+  Celsius    Value `json:"celsius"`
+}
+
+type Simple_amount struct {
+  In Value `json:"in"`
+  Mm Value `json:"mm"`
+}
+
+type Simple_wind struct {
+  Mph     Value  `json:"mph"`
+  Kph     Value  `json:"kph"`
+  Dir     string `json:"dir"`
+  Degrees Value  `json:"degrees"`
 }
 
 // printForecast prints the forecast for a given station to standard out
-func PrintForecast(obs *Conditions, stationId string) {
+func PrintForecast(obs *Conditions, stationId string, units *Units) {
   t := obs.Forecast.Txt_forecast
   fmt.Printf("Forecast for %s\n", stationId)
   fmt.Printf("Issued at %s\n", t.Date)
   for _, f := range t.Forecastday {
-    fmt.Printf("%s: %s\n", f.Title, f.Fcttext)
+    fmt.Printf("%s: %s\n", f.Title, f.Detail(units))
+    printPop(&f)
+  }
+}
+
+// popDisclaimer explains what a probability of precipitation means
+const popDisclaimer = "%s%% means there's a %s%% chance that at least 0.01 inch of rain will fall somewhere in the forecast area."
+
+// shownPopDisclaimer is set once popDisclaimer has been printed, so
+// that it appears only once however many periods are shown
+var shownPopDisclaimer bool
+
+// printPop prints the probability of precipitation for a period for
+// -forecast-confidence, explaining it the first time
+func printPop(f *Forecastday) {
+  if !doconfidencepop || f.Pop == "" {
+    return
+  }
+  fmt.Printf("   Chance of precipitation: %s%%\n", f.Pop)
+  if !shownPopDisclaimer {
+    fmt.Printf("   ("+popDisclaimer+")\n", f.Pop, f.Pop)
+    shownPopDisclaimer = true
+  }
+}
+
+// conditionFilter compiles pattern (case-insensitively) into the
+// predicate behind -filter-condition: it reports whether any of the
+// texts matches, or whether none does if invert is set
+func conditionFilter(pattern string, invert bool) (func(texts ...string) bool, error) {
+  re, err := regexp.Compile("(?i)" + pattern)
+  if err != nil {
+    return nil, err
+  }
+  return func(texts ...string) bool {
+    for _, t := range texts {
+      if re.MatchString(t) {
+        return !invert
+      }
+    }
+    return invert
+  }, nil
+}
+
+// FilterForecast returns the periods whose text or icon matches
+// pattern (case-insensitively), or those that don't if invert is set
+func FilterForecast(days []Forecastday, pattern string, invert bool) ([]Forecastday, error) {
+  keep, err := conditionFilter(pattern, invert)
+  if err != nil {
+    return nil, err
+  }
+  filtered := make([]Forecastday, 0)
+  for _, d := range days {
+    if keep(d.Fcttext, d.Icon) {
+      filtered = append(filtered, d)
+    }
+  }
+  return filtered, nil
+}
+
+// FilterSimpleForecast is FilterForecast for the days of the simple
+// forecast, matching their conditions or icon
+func FilterSimpleForecast(days []Simpleforecastday, pattern string, invert bool) ([]Simpleforecastday, error) {
+  keep, err := conditionFilter(pattern, invert)
+  if err != nil {
+    return nil, err
+  }
+  filtered := make([]Simpleforecastday, 0)
+  for _, d := range days {
+    if keep(d.Conditions, d.Icon) {
+      filtered = append(filtered, d)
+    }
+  }
+  return filtered, nil
+}
+
+// FilterByRainRisk returns the periods whose chance of precipitation
+// is at least minPop percent
+func FilterByRainRisk(days []Forecastday, minPop int) []Forecastday {
+  filtered := make([]Forecastday, 0)
+  for _, d := range days {
+    if pop, ok := d.Pop.Float(); ok && pop >= float64(minPop) {
+      filtered = append(filtered, d)
+    }
+  }
+  return filtered
+}
+
+// FilterSimpleByRainRisk is FilterByRainRisk for the days of the simple
+// forecast
+func FilterSimpleByRainRisk(days []Simpleforecastday, minPop int) []Simpleforecastday {
+  filtered := make([]Simpleforecastday, 0)
+  for _, d := range days {
+    if pop, ok := d.Pop.Float(); ok && pop >= float64(minPop) {
+      filtered = append(filtered, d)
+    }
+  }
+  return filtered
+}
+
+// LimitForecast trims both the text and the simple forecast to at most
+// n periods (days for the simple forecast)
+func LimitForecast(f *Forecast, n int) {
+  if len(f.Txt_forecast.Forecastday) > n {
+    f.Txt_forecast.Forecastday = f.Txt_forecast.Forecastday[:n]
+  }
+  if len(f.Simpleforecast.Forecastday) > n {
+    f.Simpleforecast.Forecastday = f.Simpleforecast.Forecastday[:n]
+  }
+}
+
+// isFreezing reports whether a Fahrenheit temperature is below 32
+func isFreezing(lowF string) bool {
+  t, err := strconv.ParseFloat(strings.TrimSpace(lowF), 64)
+  return err == nil && t < 32.0
+}
+
+// periodDays returns the index of the simple forecast day each text
+// forecast period belongs to.  The text forecast has a day and a night
+// period for each day, but may begin with "Tonight".
+func periodDays(f *Forecast) []int {
+  indexes := make([]int, len(f.Txt_forecast.Forecastday))
+  day := 0
+  for i, p := range f.Txt_forecast.Forecastday {
+    indexes[i] = day
+    if isNight(&p) {
+      day++
+    }
+  }
+  return indexes
+}
+
+var nightTitlePattern = regexp.MustCompile(`(?i)\b(tonight|overnight|night)\b`)
+
+// isNightPeriod reports whether a text forecast period title (e.g.
+// "Tonight" or "Tuesday Night") names a night period
+func isNightPeriod(title string) bool {
+  return nightTitlePattern.MatchString(strings.TrimSpace(title))
+}
+
+// isDayPeriod reports whether a text forecast period title names a
+// daytime period (anything that isn't a night period)
+func isDayPeriod(title string) bool {
+  return !isNightPeriod(title)
+}
+
+// isNight reports whether a text forecast period is a night period
+func isNight(p *Forecastday) bool {
+  return isNightPeriod(p.Title)
+}
+
+// MarkFreezing flags the night periods of the text forecast whose low
+// (from the simple forecast) is below freezing, and reports whether
+// there were any.
+func MarkFreezing(f *Forecast) bool {
+  days := f.Simpleforecast.Forecastday
+  any := false
+  for i, day := range periodDays(f) {
+    p := &f.Txt_forecast.Forecastday[i]
+    if isNight(p) && day < len(days) && isFreezing(string(days[day].Low.Fahrenheit)) {
+      p.freezing = true
+      any = true
+    }
+  }
+  return any
+}
+
+// FindForecastDay returns the text forecast periods for the date
+// target, or an error if it falls outside the forecast
+func FindForecastDay(forecast *Forecast, target time.Time) ([]Forecastday, error) {
+  days := forecast.Simpleforecast.Forecastday
+  if len(days) == 0 {
+    return nil, errors.New("No forecast available.")
+  }
+  match := -1
+  for i, d := range days {
+    if d.Date.Time().Equal(target) {
+      match = i
+    }
+  }
+  if match < 0 {
+    if target.Before(days[0].Date.Time()) {
+      return nil, errors.New("Date is in the past.")
+    }
+    return nil, errors.New("Date is beyond the 10-day forecast window.")
+  }
+  periods := make([]Forecastday, 0)
+  for i, day := range periodDays(forecast) {
+    if day == match {
+      periods = append(periods, forecast.Txt_forecast.Forecastday[i])
+    }
+  }
+  return periods, nil
+}
+
+// Time returns the date (at midnight UTC, so that dates compare
+// equal regardless of the station's time zone)
+func (d *Simple_date) Time() time.Time {
+  year, _ := strconv.Atoi(string(d.Year))
+  month, _ := strconv.Atoi(string(d.Month))
+  day, _ := strconv.Atoi(string(d.Day))
+  return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// SumQPF totals the forecast precipitation (in inches) over days, and
+// returns the number of days that reported it
+func SumQPF(days []Simpleforecastday) (float64, int) {
+  total, n := 0.0, 0
+  for _, d := range days {
+    if in, ok := d.Qpf_allday.In.Float(); ok {
+      total += in
+      n++
+    }
+  }
+  return total, n
+}
+
+// PrintForecastRainTotal prints the precipitation expected over the
+// forecast, or (when the forecast has no amounts) the number of rainy
+// days to expect from the chance of precipitation
+func PrintForecastRainTotal(obs *Conditions, metric bool, w io.Writer) {
+  days := obs.Forecast.Simpleforecast.Forecastday
+  total, n := SumQPF(days)
+  if n > 0 {
+    if metric {
+      fmt.Fprintf(w, "Expected total rainfall: %.0f mm over %d days.\n", total*25.4, n)
+    } else {
+      fmt.Fprintf(w, "Expected total rainfall: %.2f inches over %d days.\n", total, n)
+    }
+    return
+  }
+  rainy, n := 0.0, 0
+  for _, d := range days {
+    if pop, ok := d.Pop.Float(); ok {
+      rainy += pop / 100
+      n++
+    }
+  }
+  if n == 0 {
+    fmt.Fprintln(w, "Precipitation forecast unavailable.")
+    return
+  }
+  fmt.Fprintf(w, "Expect rain on about %.1f of the next %d days.\n", rainy, n)
+  fmt.Fprintln(w, "(The forecast has no amounts; this estimate is from the chance of precipitation.)")
+}
+
+// Sky (and precipitation) categories for SummarizeForecast
+const (
+  skySunny  = "sunny"
+  skyCloudy = "cloudy"
+  skyRain   = "rain"
+  skySnow   = "snow"
+)
+
+// iconCategory classifies a forecast icon (e.g. "chancetstorms" or
+// "nt_mostlysunny") for SummarizeForecast
+func iconCategory(icon string) string {
+  icon = strings.TrimPrefix(strings.TrimPrefix(icon, "nt_"), "chance")
+  switch icon {
+  case "rain", "tstorms":
+    return skyRain
+  case "snow", "flurries", "sleet":
+    return skySnow
+  case "clear", "sunny", "mostlysunny":
+    return skySunny
+  }
+  return skyCloudy
+}
+
+// joinWords joins words as English does ("a", "a and b", "a, b, and c")
+func joinWords(words []string) string {
+  switch len(words) {
+  case 0:
+    return ""
+  case 1:
+    return words[0]
+  case 2:
+    return words[0] + " and " + words[1]
+  }
+  return strings.Join(words[:len(words)-1], ", ") + ", and " + words[len(words)-1]
+}
+
+// describeHighs describes the range of days' highs (in Fahrenheit):
+// "temperatures in the low 70s" when they're close together, or
+// "highs from 55 to 75°F" otherwise
+func describeHighs(days []Simpleforecastday) string {
+  lo, hi, sum, n := math.Inf(1), math.Inf(-1), 0.0, 0
+  for _, d := range days {
+    if t, ok := d.High.Fahrenheit.Float(); ok {
+      lo, hi, sum, n = math.Min(lo, t), math.Max(hi, t), sum+t, n+1
+    }
+  }
+  if n == 0 {
+    return ""
+  }
+  if hi-lo >= 10 {
+    return fmt.Sprintf("highs from %.0f to %.0f°F", lo, hi)
+  }
+  avg := int(math.Round(sum / float64(n)))
+  decade, part := avg/10*10, "mid"
+  switch avg % 10 {
+  case 0, 1, 2, 3:
+    part = "low"
+  case 7, 8, 9:
+    part = "upper"
+  }
+  return fmt.Sprintf("temperatures in the %s %ds", part, decade)
+}
+
+// SummarizeForecast describes the week ahead (the first seven days of
+// the forecast) in one sentence, from each day's icon and high
+func SummarizeForecast(days []Simpleforecastday) string {
+  if len(days) > 7 {
+    days = days[:7]
+  }
+  if len(days) == 0 {
+    return "No forecast available."
+  }
+  wet := map[string][]string{}
+  skies := map[string]int{}
+  for _, d := range days {
+    switch c := iconCategory(d.Icon); c {
+    case skyRain, skySnow:
+      wet[c] = append(wet[c], d.Date.Weekday)
+    default:
+      skies[c]++
+    }
+  }
+  sky := "a mix of sun and clouds"
+  if skies[skySunny] > skies[skyCloudy] {
+    sky = "sunny skies"
+  } else if skies[skyCloudy] > skies[skySunny] {
+    sky = "cloudy skies"
+  }
+  temps := describeHighs(days)
+  if len(wet) == 0 {
+    if skies[skySunny] == len(days) || skies[skyCloudy] == len(days) {
+      return fmt.Sprintf("This week expect %s every day, with %s.", sky, temps)
+    }
+    return fmt.Sprintf("This week expect %s, with %s.", sky, temps)
+  }
+  if len(wet[skyRain])+len(wet[skySnow]) == len(days) {
+    kind := "rain"
+    if len(wet[skySnow]) > 0 {
+      kind = "rain or snow"
+      if len(wet[skyRain]) == 0 {
+        kind = "snow"
+      }
+    }
+    return fmt.Sprintf("This week expect %s every day, with %s.", kind, temps)
+  }
+  precip := make([]string, 0)
+  for _, c := range []string{skyRain, skySnow} {
+    if len(wet[c]) > 0 {
+      precip = append(precip, c+" on "+joinWords(wet[c]))
+    }
+  }
+  return fmt.Sprintf("This week expect %s, with otherwise %s and %s.", strings.Join(precip, " and "), sky, temps)
+}
+
+// PrintForecastSummary prints SummarizeForecast for the forecast
+func PrintForecastSummary(obs *Conditions, w io.Writer) {
+  fmt.Fprintln(w, SummarizeForecast(obs.Forecast.Simpleforecast.Forecastday))
+}
+
+// PackForecast lays out days as "Mon ⛅71↑ 52↓" entries, two spaces
+// apart, on as few lines of no more than maxWidth columns as it can
+func PackForecast(days []Simpleforecastday, metric bool, maxWidth int) []string {
+  lang, supportUTF8 := utf8Locale()
+  lines := make([]string, 0)
+  line := ""
+  for _, day := range days {
+    high, low := day.High.Fahrenheit, day.Low.Fahrenheit
+    if metric {
+      high, low = day.High.Celsius, day.Low.Celsius
+    }
+    icon := BestIcon(day.Icon, lang, supportUTF8)
+    if icon[0] < utf8.RuneSelf {
+      icon += " " // keep ASCII codes off the digits
+    }
+    entry := fmt.Sprintf("%s %s%s↑ %s↓", day.Date.Weekday_short, icon, high, low)
+    if line != "" && displayWidth(line+"  "+entry) > maxWidth {
+      lines = append(lines, line)
+      line = ""
+    }
+    if line != "" {
+      line += "  "
+    }
+    line += entry
+  }
+  if line != "" {
+    lines = append(lines, line)
+  }
+  return lines
+}
+
+// PrintForecastPack prints PackForecast for the forecast, 80 columns wide
+func PrintForecastPack(obs *Conditions, metric bool, w io.Writer) {
+  for _, line := range PackForecast(obs.Forecast.Simpleforecast.Forecastday, metric, 80) {
+    fmt.Fprintln(w, line)
+  }
+}
+
+var weekendNames = map[string]bool{"Saturday": true, "Sunday": true}
+
+// FilterWeekend returns the periods (day and night) that fall on a
+// Saturday or Sunday, going by the day named in each title
+func FilterWeekend(days []Forecastday) []Forecastday {
+  filtered := make([]Forecastday, 0)
+  for _, d := range days {
+    if fields := strings.Fields(d.Title); len(fields) > 0 && weekendNames[fields[0]] {
+      filtered = append(filtered, d)
+    }
+  }
+  return filtered
+}
+
+// FilterPeriods returns the periods of a text forecast whose titles
+// satisfy keep (isDayPeriod or isNightPeriod)
+func FilterPeriods(days []Forecastday, keep func(title string) bool) []Forecastday {
+  filtered := make([]Forecastday, 0)
+  for _, d := range days {
+    if keep(d.Title) {
+      filtered = append(filtered, d)
+    }
+  }
+  return filtered
+}
+
+// Brief returns the first sentence of the forecast text, for
+// -forecast-detail=brief
+func (f *Forecastday) Brief(units *Units) string {
+  text := f.Text(units)
+  if i := strings.Index(text, ". "); i >= 0 {
+    return text[:i+1]
+  }
+  return text
+}
+
+// Detail returns the forecast text at the level of detail requested
+// with -forecast-detail
+func (f *Forecastday) Detail(units *Units) string {
+  if forecastdetail == "brief" {
+    return f.Brief(units)
+  }
+  return f.Text(units)
+}
+
+// Text returns the forecast text in the temperature units requested
+func (f *Forecastday) Text(units *Units) string {
+  if units.Metric() && f.Fcttext_metric != "" {
+    return f.Fcttext_metric
+  }
+  return f.Fcttext
+}
+
+// PrintForecastHighLowOnly prints the high and low for up to days days
+// of the forecast as "Mon 65/45, Tue 72/52, ...", wrapped at 80
+// columns
+func PrintForecastHighLowOnly(obs *Conditions, days int, metric bool, w io.Writer) {
+  forecast := obs.Forecast.Simpleforecast.Forecastday
+  if days < len(forecast) {
+    forecast = forecast[:days]
+  }
+  line := ""
+  for i, d := range forecast {
+    high, low := d.High.Fahrenheit, d.Low.Fahrenheit
+    if metric {
+      high, low = d.High.Celsius, d.Low.Celsius
+    }
+    item := fmt.Sprintf("%s %s/%s", d.Date.Weekday_short, high, low)
+    if i < len(forecast)-1 {
+      item += ","
+    }
+    if line != "" && len(line)+1+len(item) > 80 {
+      fmt.Fprintln(w, line)
+      line = ""
+    }
+    if line != "" {
+      line += " "
+    }
+    line += item
+  }
+  if line != "" {
+    fmt.Fprintln(w, line)
   }
 }
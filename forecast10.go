@@ -34,11 +34,15 @@ import (
 
 // printForecast prints the forecast for a given station to standard out
 // The dat structure on which it depends is in forecast.go.
-func PrintForecast10(obs *Conditions, stationId string) {
+func PrintForecast10(obs *Conditions, stationId string, units *Units) {
   t := obs.Forecast.Txt_forecast
   fmt.Printf("Forecast for %s\n", stationId)
   fmt.Printf("Issued at %s\n", t.Date)
   for _, f := range t.Forecastday {
-    fmt.Printf("%s: %s\n", f.Title, f.Fcttext)
+    if f.freezing {
+      fmt.Print("❄ ")
+    }
+    fmt.Printf("%s: %s\n", f.Title, f.Detail(units))
+    printPop(&f)
   }
 }
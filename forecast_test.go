@@ -0,0 +1,649 @@
+/*
+* forecast_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for forecast filtering (forecast.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:16:45 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "math"
+  "regexp"
+  "strconv"
+  "strings"
+  "testing"
+  "time"
+)
+
+// tenDayFixture is a 10-day forecast with rain on days 2, 5 and 9
+func tenDayFixture() []Forecastday {
+  texts := []struct{ icon, text string }{
+    {"clear", "Sunny. High 75F."},
+    {"chancerain", "Chance of rain in the afternoon. High 68F."},
+    {"partlycloudy", "Partly cloudy. High 70F."},
+    {"cloudy", "Overcast. High 66F."},
+    {"rain", "Periods of rain. High 60F."},
+    {"clear", "Clear. High 72F."},
+    {"mostlysunny", "Mostly sunny. High 74F."},
+    {"partlycloudy", "Partly cloudy. High 71F."},
+    {"tstorms", "Thunderstorms with heavy rain. High 80F."},
+    {"clear", "Sunny. High 77F."},
+  }
+  days := make([]Forecastday, len(texts))
+  for i, t := range texts {
+    days[i] = Forecastday{Title: dayNames[i%len(dayNames)], Icon: t.icon, Fcttext: t.text}
+  }
+  return days
+}
+
+var dayNames = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+func TestFilterForecast(t *testing.T) {
+  tests := []struct {
+    pattern string
+    invert  bool
+    want    []int
+  }{
+    {"rain", false, []int{1, 4, 8}},
+    {"RAIN", false, []int{1, 4, 8}},
+    {"rain", true, []int{0, 2, 3, 5, 6, 7, 9}},
+    {"rain|overcast", false, []int{1, 3, 4, 8}},
+    {"snow", false, []int{}},
+  }
+  days := tenDayFixture()
+  for _, tt := range tests {
+    got, err := FilterForecast(days, tt.pattern, tt.invert)
+    if err != nil {
+      t.Fatalf("FilterForecast(%q): %v", tt.pattern, err)
+    }
+    if len(got) != len(tt.want) {
+      t.Errorf("FilterForecast(%q, %v) kept %d periods, want %d", tt.pattern, tt.invert, len(got), len(tt.want))
+      continue
+    }
+    for i, idx := range tt.want {
+      if got[i].Fcttext != days[idx].Fcttext {
+        t.Errorf("FilterForecast(%q, %v)[%d] = %q, want %q", tt.pattern, tt.invert, i, got[i].Fcttext, days[idx].Fcttext)
+      }
+    }
+  }
+}
+
+func TestFilterForecastInvalidPattern(t *testing.T) {
+  if _, err := FilterForecast(tenDayFixture(), "rain(", false); err == nil {
+    t.Error("FilterForecast accepted an invalid pattern")
+  }
+}
+
+// forecastMonday is the Monday, August 14, 2023 that most of the test
+// forecasts start on
+var forecastMonday = time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC)
+
+// dayField is one field of the forecast days made by forecastDays,
+// with its value for each day in turn
+type dayField struct {
+  set    func(d *Simpleforecastday, v string)
+  values []string
+}
+
+func dayIcons(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Icon = v }, v}
+}
+
+func dayConditions(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Conditions = v }, v}
+}
+
+func dayHighs(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.High.Fahrenheit = Value(v) }, v}
+}
+
+func dayHighsC(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.High.Celsius = Value(v) }, v}
+}
+
+func dayLows(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Low.Fahrenheit = Value(v) }, v}
+}
+
+func dayLowsC(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Low.Celsius = Value(v) }, v}
+}
+
+func dayPops(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Pop = Value(v) }, v}
+}
+
+func dayQPFs(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Qpf_allday.In = Value(v) }, v}
+}
+
+func dayWinds(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Avewind.Mph = Value(v) }, v}
+}
+
+func dayGusts(v ...string) dayField {
+  return dayField{func(d *Simpleforecastday, v string) { d.Maxwind.Mph = Value(v) }, v}
+}
+
+// forecastDays returns a simple forecast day for each day from start,
+// with fields filled in, for as many days as the longest field has
+// values
+func forecastDays(start time.Time, fields ...dayField) []Simpleforecastday {
+  n := 0
+  for _, f := range fields {
+    if len(f.values) > n {
+      n = len(f.values)
+    }
+  }
+  days := make([]Simpleforecastday, n)
+  for i := range days {
+    t := start.AddDate(0, 0, i)
+    days[i].Date = Simple_date{
+      Epoch: strconv.FormatInt(t.Unix(), 10), Day: Value(strconv.Itoa(t.Day())),
+      Month: Value(strconv.Itoa(int(t.Month()))), Year: Value(strconv.Itoa(t.Year())),
+      Weekday: t.Format("Monday"), Weekday_short: t.Format("Mon"),
+    }
+    for _, f := range fields {
+      if i < len(f.values) {
+        f.set(&days[i], f.values[i])
+      }
+    }
+  }
+  return days
+}
+
+// simpleWeek is a 7-day simple forecast starting on Monday
+func simpleWeek() []Simpleforecastday {
+  return forecastDays(forecastMonday,
+    dayHighs("65", "72", "60", "58", "61", "70", "102"), dayLows("45", "52", "40", "38", "41", "50", "-5"),
+    dayHighsC("18", "22", "16", "14", "16", "21", "39"), dayLowsC("7", "11", "4", "3", "5", "10", "-21"))
+}
+
+func TestPrintForecastHighLowOnly(t *testing.T) {
+  tests := []struct {
+    days   int
+    metric bool
+    want   string
+  }{
+    {7, false, "Mon 65/45, Tue 72/52, Wed 60/40, Thu 58/38, Fri 61/41, Sat 70/50, Sun 102/-5\n"},
+    {7, true, "Mon 18/7, Tue 22/11, Wed 16/4, Thu 14/3, Fri 16/5, Sat 21/10, Sun 39/-21\n"},
+    {3, false, "Mon 65/45, Tue 72/52, Wed 60/40\n"},
+    {10, false, "Mon 65/45, Tue 72/52, Wed 60/40, Thu 58/38, Fri 61/41, Sat 70/50, Sun 102/-5\n"},
+  }
+  for _, tt := range tests {
+    obs := &Conditions{}
+    obs.Forecast.Simpleforecast.Forecastday = simpleWeek()
+    var buf bytes.Buffer
+    PrintForecastHighLowOnly(obs, tt.days, tt.metric, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintForecastHighLowOnly(%d, metric %v) = %q, want %q", tt.days, tt.metric, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestPrintForecastHighLowOnlyWraps(t *testing.T) {
+  obs := &Conditions{}
+  obs.Forecast.Simpleforecast.Forecastday = append(simpleWeek(), simpleWeek()...)
+  var buf bytes.Buffer
+  PrintForecastHighLowOnly(obs, 14, false, &buf)
+  lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+  if len(lines) != 2 {
+    t.Fatalf("14 days printed on %d lines, want 2:\n%s", len(lines), buf.String())
+  }
+  for _, l := range lines {
+    if len(l) > 80 {
+      t.Errorf("line %q is longer than 80 columns", l)
+    }
+  }
+  if !strings.HasSuffix(lines[0], ",") || strings.Count(buf.String(), "/") != 14 {
+    t.Errorf("wrapped output lost a day:\n%s", buf.String())
+  }
+}
+
+func TestFilterByRainRisk(t *testing.T) {
+  days := tenDayFixture()
+  for _, i := range []int{1, 4, 8} {
+    days[i].Pop = "60"
+  }
+  for _, i := range []int{2, 5} {
+    days[i].Pop = "20"
+  }
+  days[0].Pop = "0"
+  tests := []struct {
+    minPop int
+    want   []int
+  }{
+    {40, []int{1, 4, 8}},
+    {60, []int{1, 4, 8}},
+    {61, nil},
+    {20, []int{1, 2, 4, 5, 8}},
+  }
+  for _, tt := range tests {
+    got := FilterByRainRisk(days, tt.minPop)
+    if len(got) != len(tt.want) {
+      t.Errorf("FilterByRainRisk(%d) kept %d periods, want %d", tt.minPop, len(got), len(tt.want))
+      continue
+    }
+    for i, idx := range tt.want {
+      if got[i].Fcttext != days[idx].Fcttext {
+        t.Errorf("FilterByRainRisk(%d)[%d] = %q, want %q", tt.minPop, i, got[i].Fcttext, days[idx].Fcttext)
+      }
+    }
+  }
+}
+
+func TestLimitForecast(t *testing.T) {
+  f := Forecast{
+    Txt_forecast:   Txt_forecast{Forecastday: tenDayFixture()},
+    Simpleforecast: Simpleforecast{Forecastday: make([]Simpleforecastday, 2)},
+  }
+  LimitForecast(&f, 3)
+  if len(f.Txt_forecast.Forecastday) != 3 || len(f.Simpleforecast.Forecastday) != 2 {
+    t.Errorf("LimitForecast(3) left %d periods and %d days, want 3 and 2", len(f.Txt_forecast.Forecastday), len(f.Simpleforecast.Forecastday))
+  }
+}
+
+func TestForecastdayBrief(t *testing.T) {
+  tests := []struct {
+    text string
+    want string
+  }{
+    {"Chance of rain in the afternoon. High 68F.", "Chance of rain in the afternoon."},
+    {"Sunny.", "Sunny."},
+    {"", ""},
+  }
+  for _, tt := range tests {
+    f := Forecastday{Fcttext: tt.text}
+    if got := f.Brief(&Units{Temperature: "f"}); got != tt.want {
+      t.Errorf("Brief(%q) = %q, want %q", tt.text, got, tt.want)
+    }
+  }
+}
+
+func TestFilterWeekend(t *testing.T) {
+  days := tenDayFixture()
+  got := FilterWeekend(days)
+  if len(got) != 2 || got[0].Title != "Saturday" || got[1].Title != "Sunday" {
+    t.Errorf("FilterWeekend kept %v, want Saturday and Sunday", titles(got))
+  }
+  periods := []Forecastday{{Title: "Friday Night"}, {Title: "Saturday"}, {Title: "Saturday Night"}, {Title: "Sunday"}, {Title: "Monday"}}
+  if got := FilterWeekend(periods); len(got) != 3 || got[1].Title != "Saturday Night" {
+    t.Errorf("FilterWeekend kept %v, want Saturday, Saturday Night and Sunday", titles(got))
+  }
+  if got := FilterWeekend(days[:5]); len(got) != 0 {
+    t.Errorf("FilterWeekend of a week's weekdays kept %v", titles(got))
+  }
+}
+
+// titles returns the titles of the periods
+func titles(days []Forecastday) []string {
+  t := make([]string, len(days))
+  for i, d := range days {
+    t[i] = d.Title
+  }
+  return t
+}
+
+func TestIsFreezing(t *testing.T) {
+  tests := []struct {
+    lowF string
+    want bool
+  }{
+    {"31", true},
+    {"-4", true},
+    {" 31.9 ", true},
+    {"32", false},
+    {"45", false},
+    {"", false},
+    {"NA", false},
+  }
+  for _, tt := range tests {
+    if got := isFreezing(tt.lowF); got != tt.want {
+      t.Errorf("isFreezing(%q) = %v, want %v", tt.lowF, got, tt.want)
+    }
+  }
+}
+
+func TestPrintForecast10Freezing(t *testing.T) {
+  var obs Conditions
+  obs.Forecast.Txt_forecast.Forecastday = []Forecastday{
+    {Title: "Monday", Fcttext: "Sunny. High 40F."},
+    {Title: "Monday Night", Fcttext: "Clear. Low 28F."},
+    {Title: "Tuesday", Fcttext: "Cloudy. High 45F."},
+    {Title: "Tuesday Night", Fcttext: "Cloudy. Low 36F."},
+  }
+  obs.Forecast.Simpleforecast.Forecastday = []Simpleforecastday{
+    {Low: Simple_temp{Fahrenheit: "28"}},
+    {Low: Simple_temp{Fahrenheit: "36"}},
+  }
+  if !MarkFreezing(&obs.Forecast) {
+    t.Fatal("MarkFreezing found no freezing nights")
+  }
+  out := captureStdout(t, func() { PrintForecast10(&obs, "KLNK", &Units{Temperature: "f"}) })
+  if n := strings.Count(out, "❄"); n != 1 {
+    t.Fatalf("%d freezing markers, want 1:\n%s", n, out)
+  }
+  if !strings.Contains(out, "❄ Monday Night: ") {
+    t.Errorf("the freezing night isn't marked:\n%s", out)
+  }
+}
+
+// eventForecast is three days of forecast, from Sunday, September 1,
+// 2013, beginning with that night
+func eventForecast() *Forecast {
+  var f Forecast
+  for _, title := range []string{"Tonight", "Monday", "Monday Night", "Tuesday", "Tuesday Night"} {
+    f.Txt_forecast.Forecastday = append(f.Txt_forecast.Forecastday, Forecastday{Title: title})
+  }
+  for day := 1; day <= 3; day++ {
+    date := Simple_date{Day: Value(strconv.Itoa(day)), Month: "9", Year: "2013"}
+    f.Simpleforecast.Forecastday = append(f.Simpleforecast.Forecastday, Simpleforecastday{Date: date})
+  }
+  return &f
+}
+
+func TestFindForecastDay(t *testing.T) {
+  tests := []struct {
+    date string
+    want []string
+    err  string
+  }{
+    {"2013-09-01", []string{"Tonight"}, ""},
+    {"2013-09-02", []string{"Monday", "Monday Night"}, ""},
+    {"2013-09-03", []string{"Tuesday", "Tuesday Night"}, ""},
+    {"2013-08-31", nil, "Date is in the past."},
+    {"2013-09-04", nil, "Date is beyond the 10-day forecast window."},
+  }
+  for _, tt := range tests {
+    target, _ := time.Parse("2006-01-02", tt.date)
+    got, err := FindForecastDay(eventForecast(), target)
+    if tt.err != "" {
+      if err == nil || err.Error() != tt.err {
+        t.Errorf("FindForecastDay(%s) error = %v, want %q", tt.date, err, tt.err)
+      }
+      continue
+    }
+    if err != nil {
+      t.Errorf("FindForecastDay(%s): %v", tt.date, err)
+      continue
+    }
+    if strings.Join(titles(got), ",") != strings.Join(tt.want, ",") {
+      t.Errorf("FindForecastDay(%s) = %v, want %v", tt.date, titles(got), tt.want)
+    }
+  }
+  if _, err := FindForecastDay(&Forecast{}, time.Now()); err == nil {
+    t.Error("FindForecastDay of an empty forecast succeeded")
+  }
+}
+
+func TestPopDisclaimerOnce(t *testing.T) {
+  savedPop, savedShown := doconfidencepop, shownPopDisclaimer
+  t.Cleanup(func() { doconfidencepop, shownPopDisclaimer = savedPop, savedShown })
+  doconfidencepop, shownPopDisclaimer = true, false
+  var obs Conditions
+  obs.Forecast.Txt_forecast.Forecastday = []Forecastday{
+    {Title: "Monday", Fcttext: "Showers.", Pop: "30"},
+    {Title: "Monday Night", Fcttext: "Clear.", Pop: ""},
+    {Title: "Tuesday", Fcttext: "Rain.", Pop: "60"},
+    {Title: "Wednesday", Fcttext: "Storms.", Pop: "80"},
+  }
+  out := captureStdout(t, func() { PrintForecast(&obs, "KLNK", &Units{Temperature: "f"}) })
+  if n := strings.Count(out, "chance that at least 0.01 inch"); n != 1 {
+    t.Errorf("disclaimer appeared %d times, want 1:\n%s", n, out)
+  }
+  if !strings.Contains(out, "(30% means there's a 30% chance") {
+    t.Errorf("disclaimer doesn't use the first period's chance:\n%s", out)
+  }
+  if n := strings.Count(out, "Chance of precipitation: "); n != 3 {
+    t.Errorf("%d chances of precipitation, want 3:\n%s", n, out)
+  }
+  // Nor again for a second report in the same run
+  out = captureStdout(t, func() { PrintForecast10(&obs, "KLNK", &Units{Temperature: "f"}) })
+  if strings.Contains(out, "0.01 inch") {
+    t.Errorf("disclaimer appeared again:\n%s", out)
+  }
+}
+
+// sentencePattern is a grammatical summary: one capitalized sentence,
+// single-spaced, with no empty list items or dangling conjunctions
+var sentencePattern = regexp.MustCompile(`^This week expect [a-z][a-zA-Z0-9°, ]*[a-zA-Z0-9°]\.$`)
+
+func TestSummarizeForecast(t *testing.T) {
+  seventies := []string{"71", "72", "70", "73", "72", "71", "72"}
+  tests := []struct {
+    name  string
+    icons []string
+    highs []string
+    want  string
+  }{
+    {"all sunny", []string{"clear", "sunny", "mostlysunny", "clear", "nt_clear", "sunny", "clear"}, seventies,
+      "This week expect sunny skies every day, with temperatures in the low 70s."},
+    {"all cloudy", []string{"cloudy", "mostlycloudy", "cloudy", "fog", "cloudy", "partlycloudy", "hazy"}, seventies,
+      "This week expect cloudy skies every day, with temperatures in the low 70s."},
+    {"all rainy", []string{"rain", "chancerain", "tstorms", "rain", "chancetstorms", "rain", "rain"}, seventies,
+      "This week expect rain every day, with temperatures in the low 70s."},
+    {"all snowy", []string{"snow", "flurries", "snow", "chancesnow", "sleet", "snow", "snow"}, []string{"28", "30", "25", "22", "31", "20", "26"},
+      "This week expect snow every day, with highs from 20 to 31°F."},
+    {"mixed", []string{"rain", "clear", "chancerain", "sunny", "clear", "mostlysunny", "cloudy"}, seventies,
+      "This week expect rain on Monday and Wednesday, with otherwise sunny skies and temperatures in the low 70s."},
+    {"rain and snow", []string{"rain", "cloudy", "snow", "cloudy", "rain", "cloudy", "rain"}, []string{"40", "38", "33", "36", "42", "45", "39"},
+      "This week expect rain on Monday, Friday, and Sunday and snow on Wednesday, with otherwise cloudy skies and highs from 33 to 45°F."},
+    {"mixed skies", []string{"clear", "cloudy", "clear", "cloudy", "partlycloudy", "sunny", "mostlycloudy"}, seventies,
+      "This week expect cloudy skies, with temperatures in the low 70s."},
+  }
+  for _, tt := range tests {
+    got := SummarizeForecast(forecastDays(forecastMonday, dayIcons(tt.icons...), dayHighs(tt.highs...)))
+    if got != tt.want {
+      t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+    }
+    if !sentencePattern.MatchString(got) || strings.Contains(got, "  ") || strings.Contains(got, " ,") {
+      t.Errorf("%s: %q isn't a well-formed sentence", tt.name, got)
+    }
+  }
+  if got := SummarizeForecast(nil); got != "No forecast available." {
+    t.Errorf("SummarizeForecast(nil) = %q", got)
+  }
+}
+
+func TestSumQPF(t *testing.T) {
+  tests := []struct {
+    amounts []string
+    total   float64
+    n       int
+  }{
+    {[]string{"0.25", "0.00", "1.10", "0.05", "0.34"}, 1.74, 5},
+    {[]string{"0.25", "", "1.10", "null", "0.34"}, 1.69, 3},
+    {[]string{"", "", ""}, 0, 0},
+    {nil, 0, 0},
+  }
+  for _, tt := range tests {
+    total, n := SumQPF(forecastDays(forecastMonday, dayQPFs(tt.amounts...)))
+    if math.Abs(total-tt.total) > 1e-9 || n != tt.n {
+      t.Errorf("SumQPF(%q) = %v, %d; want %v, %d", tt.amounts, total, n, tt.total, tt.n)
+    }
+  }
+}
+
+func TestPrintForecastRainTotal(t *testing.T) {
+  tests := []struct {
+    amounts, pops []string
+    metric        bool
+    want          string
+  }{
+    {[]string{"0.25", "0.00", "1.10"}, []string{"60", "10", "90"}, false, "Expected total rainfall: 1.35 inches over 3 days.\n"},
+    {[]string{"0.25", "0.00", "1.10"}, []string{"60", "10", "90"}, true, "Expected total rainfall: 34 mm over 3 days.\n"},
+    {[]string{"", "", ""}, []string{"60", "10", "90"}, false,
+      "Expect rain on about 1.6 of the next 3 days.\n(The forecast has no amounts; this estimate is from the chance of precipitation.)\n"},
+    {[]string{"", ""}, []string{"", ""}, false, "Precipitation forecast unavailable.\n"},
+  }
+  for _, tt := range tests {
+    var obs Conditions
+    obs.Forecast.Simpleforecast.Forecastday = forecastDays(forecastMonday, dayQPFs(tt.amounts...), dayPops(tt.pops...))
+    var buf bytes.Buffer
+    PrintForecastRainTotal(&obs, tt.metric, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintForecastRainTotal(%q, %q, metric %v) = %q, want %q", tt.amounts, tt.pops, tt.metric, buf.String(), tt.want)
+    }
+  }
+}
+
+// packFixture is a week of forecast days for PackForecast
+func packFixture() []Simpleforecastday {
+  return forecastDays(forecastMonday,
+    dayIcons("partlycloudy", "rain", "clear", "mostlycloudy", "tstorms", "snow", "nt_clear"),
+    dayHighs("71", "68", "82", "75", "101", "28", "64"), dayLows("52", "48", "60", "55", "78", "-4", "45"),
+    dayHighsC("22", "22", "22", "22", "22", "22", "22"), dayLowsC("11", "11", "11", "11", "11", "11", "11"))
+}
+
+func TestPackForecast(t *testing.T) {
+  for _, lang := range []string{"en_US.UTF-8", "C"} {
+    t.Setenv("LC_ALL", lang)
+    lines := PackForecast(packFixture(), false, 80)
+    days := 0
+    for _, line := range lines {
+      if w := displayWidth(line); w > 80 {
+        t.Errorf("%s: line is %d columns: %q", lang, w, line)
+      }
+      days += strings.Count(line, "↑")
+    }
+    if days != 7 || len(lines) < 2 {
+      t.Errorf("%s: packed %d days on %d lines, want 7 on at least 2:\n%s", lang, days, len(lines), strings.Join(lines, "\n"))
+    }
+  }
+  t.Setenv("LC_ALL", "C")
+  if lines := PackForecast(packFixture()[:2], true, 80); len(lines) != 1 || lines[0] != "Mon PC 22↑ 11↓  Tue RA 22↑ 11↓" {
+    t.Errorf("metric PackForecast = %q", lines)
+  }
+}
+
+func TestDisplayWidth(t *testing.T) {
+  tests := []struct {
+    s    string
+    want int
+  }{
+    {"Mon PC 71↑ 52↓", 14},
+    {"⛅", 2},
+    {"☀️", 2},
+    {"🌧", 2},
+    {"Mon ⛅71↑ 52↓", 13},
+    {"72°F", 4},
+    {"東京", 4},
+    {"", 0},
+  }
+  for _, tt := range tests {
+    if got := displayWidth(tt.s); got != tt.want {
+      t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+    }
+  }
+}
+
+func TestIsNightPeriod(t *testing.T) {
+  tests := []struct {
+    title string
+    want  bool
+  }{
+    {"Tonight", true},
+    {"Tuesday Night", true},
+    {"Wednesday Overnight", true},
+    {"Overnight", true},
+    {"Thursday night", true},
+    {" Friday Night ", true},
+    {"Today", false},
+    {"Tuesday", false},
+    {"This Afternoon", false},
+    {"Nightingale Park", false},
+    {"Knightsbridge", false},
+    {"", false},
+  }
+  for _, tt := range tests {
+    if got := isNightPeriod(tt.title); got != tt.want {
+      t.Errorf("isNightPeriod(%q) = %v, want %v", tt.title, got, tt.want)
+    }
+  }
+}
+
+func TestFilterNightPeriods(t *testing.T) {
+  days := []Forecastday{{Title: "Monday"}, {Title: "Monday Night"}, {Title: "Tuesday"}, {Title: "Tuesday Night"}, {Title: "Wednesday"}, {Title: "Wednesday Night"}}
+  if got := titles(FilterPeriods(days, isNightPeriod)); strings.Join(got, ",") != "Monday Night,Tuesday Night,Wednesday Night" {
+    t.Errorf("night periods = %q", got)
+  }
+}
+
+func TestIsDayPeriod(t *testing.T) {
+  for _, title := range []string{"Today", "Tonight", "Tuesday", "Tuesday Night", "Wednesday Overnight",
+    "This Afternoon", "Rest of Tonight", "Nightingale Park", "Saturday", "", "Friday night"} {
+    if isDayPeriod(title) == isNightPeriod(title) {
+      t.Errorf("%q: isDayPeriod = isNightPeriod = %v", title, isDayPeriod(title))
+    }
+  }
+}
+
+func TestForecastDayAndNight(t *testing.T) {
+  status, _, stderr := runOptions(t, "-script-mode", "-forecast", "-forecast-day", "-forecast-night")
+  if status != int(InvalidInput) || !strings.Contains(stderr, "-forecast-day or -forecast-night, not both") {
+    t.Errorf("-forecast-day -forecast-night: status %d, %q", status, stderr)
+  }
+  if status, stdout, _ := runOptions(t, "-forecast", "-forecast-day", "-s", "KLNK"); status != 0 || stdout != "KLNK\n" {
+    t.Errorf("-forecast-day alone: status %d, %q", status, stdout)
+  }
+}
+
+func TestFilterSimpleForecast(t *testing.T) {
+  days := []Simpleforecastday{
+    {Conditions: "Clear", Icon: "clear"},
+    {Conditions: "Chance of Rain", Icon: "chancerain"},
+    {Conditions: "Thunderstorm", Icon: "tstorms"},
+    {Conditions: "Overcast", Icon: "cloudy"},
+  }
+  tests := []struct {
+    pattern string
+    invert  bool
+    want    []int
+  }{
+    {"rain", false, []int{1}},
+    {"RAIN|tstorms", false, []int{1, 2}},
+    {"rain", true, []int{0, 2, 3}},
+    {"snow", false, []int{}},
+  }
+  for _, tt := range tests {
+    got, err := FilterSimpleForecast(days, tt.pattern, tt.invert)
+    if err != nil {
+      t.Fatalf("FilterSimpleForecast(%q): %v", tt.pattern, err)
+    }
+    if len(got) != len(tt.want) {
+      t.Errorf("FilterSimpleForecast(%q, %v) kept %d days, want %d", tt.pattern, tt.invert, len(got), len(tt.want))
+      continue
+    }
+    for i, idx := range tt.want {
+      if got[i].Conditions != days[idx].Conditions {
+        t.Errorf("FilterSimpleForecast(%q, %v)[%d] = %q, want %q", tt.pattern, tt.invert, i, got[i].Conditions, days[idx].Conditions)
+      }
+    }
+  }
+  if _, err := FilterSimpleForecast(days, "rain(", false); err == nil {
+    t.Error("FilterSimpleForecast accepted an invalid pattern")
+  }
+}
@@ -0,0 +1,190 @@
+/*
+* geo.go
+*
+* This file is part of wu.  It contains functions related to
+* geographic calculations and the --station-distance switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:04:37 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "os"
+  "strconv"
+)
+
+const (
+  earthRadiusKm = 6371.0 // mean radius of the Earth
+  kmPerMile     = 1.609344
+  farStationMi  = 50 // beyond this a station may not be representative
+  pwsDriftF     = 10 // a PWS this far from an official station is suspect
+)
+
+// HaversineDistance returns the great-circle distance in kilometers
+// between two points given in decimal degrees
+func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+  rad := math.Pi / 180
+  dlat := (lat2 - lat1) * rad
+  dlon := (lon2 - lon1) * rad
+  a := math.Sin(dlat/2)*math.Sin(dlat/2) +
+    math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dlon/2)*math.Sin(dlon/2)
+  return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// StationDistance returns the distance in kilometers between the
+// location asked about and the station reporting its conditions.
+// Both conditions and geolookup must have been requested.
+func StationDistance(obs *Conditions) (float64, bool) {
+  station := obs.Current_observation.Observation_location
+  slat, ok1 := station.Latitude.Float()
+  slon, ok2 := station.Longitude.Float()
+  qlat, err1 := strconv.ParseFloat(obs.Location.Lat, 64)
+  qlon, err2 := strconv.ParseFloat(obs.Location.Lon, 64)
+  if !ok1 || !ok2 || err1 != nil || err2 != nil {
+    return 0, false
+  }
+  return HaversineDistance(qlat, qlon, slat, slon), true
+}
+
+// PrintStationDistance prints how far the reporting station is from
+// the location asked about, with a warning if it's far away
+func PrintStationDistance(obs *Conditions, w io.Writer) {
+  km, ok := StationDistance(obs)
+  if !ok {
+    return
+  }
+  miles := km / kmPerMile
+  fmt.Fprintf(w, "Reporting station %s is %.1f miles from %s, %s\n", obs.Current_observation.Station_id,
+    miles, obs.Location.City, obs.Location.State)
+  if miles > farStationMi {
+    fmt.Fprintln(w, "⚠ Station is far from your location; data may not be representative.")
+  }
+}
+
+// validateCoordinates checks that lat and lon are in range
+func validateCoordinates(lat, lon float64) error {
+  if lat < -90 || lat > 90 {
+    return fmt.Errorf("latitude %g is not between -90 and 90", lat)
+  }
+  if lon < -180 || lon > 180 {
+    return fmt.Errorf("longitude %g is not between -180 and 180", lon)
+  }
+  return nil
+}
+
+// CoordinatesStation returns the LAT,LON station for -lat and -lon,
+// both of which must be given
+func CoordinatesStation(lat, lon string) (string, error) {
+  if lat == "" || lon == "" {
+    return "", fmt.Errorf("-lat and -lon must be given together")
+  }
+  la, err1 := strconv.ParseFloat(lat, 64)
+  lo, err2 := strconv.ParseFloat(lon, 64)
+  if err1 != nil || err2 != nil {
+    return "", fmt.Errorf("-lat and -lon must be decimal degrees (e.g. -lat 40.7128 -lon -74.0060)")
+  }
+  if err := validateCoordinates(la, lo); err != nil {
+    return "", err
+  }
+  return lat + "," + lon, nil
+}
+
+// NearestOfficialStation returns the ICAO code of the airport (ASOS/AWOS)
+// station in geolookup nearest the station reporting the conditions,
+// other than that station itself
+func NearestOfficialStation(obs *Conditions) (string, bool) {
+  station := obs.Current_observation.Observation_location
+  slat, ok1 := station.Latitude.Float()
+  slon, ok2 := station.Longitude.Float()
+  nearest, best := "", math.Inf(1)
+  for _, s := range obs.Location.Nearby_weather_stations.Airport.Station {
+    if s.Icao == "" || s.Icao == obs.Current_observation.Station_id {
+      continue
+    }
+    lat, ok3 := s.Lat.Float()
+    lon, ok4 := s.Lon.Float()
+    if !ok1 || !ok2 || !ok3 || !ok4 {
+      // Without coordinates, trust the API's ordering by distance
+      if nearest == "" {
+        nearest = s.Icao
+      }
+      continue
+    }
+    if d := HaversineDistance(slat, slon, lat, lon); d < best {
+      nearest, best = s.Icao, d
+    }
+  }
+  return nearest, nearest != ""
+}
+
+// CompareStationReadings returns how many degrees Fahrenheit warmer the
+// pws reading is than the official one (negative if it is cooler)
+func CompareStationReadings(pws, official *Current) (float64, error) {
+  p, ok := pws.Temp_f.Float()
+  if !ok {
+    return 0, fmt.Errorf("%s reported no temperature", pws.Station_id)
+  }
+  o, ok := official.Temp_f.Float()
+  if !ok {
+    return 0, fmt.Errorf("%s reported no temperature", official.Station_id)
+  }
+  return p - o, nil
+}
+
+// FetchOfficialConditions retrieves the current conditions at the
+// official station nearest the one reporting obs's conditions
+func FetchOfficialConditions(obs *Conditions) (*Current, error) {
+  icao, ok := NearestOfficialStation(obs)
+  if !ok {
+    return nil, fmt.Errorf("no official station nearby")
+  }
+  b, err := Fetch(BuildURL([]string{"conditions"}, icao))
+  if err != nil {
+    return nil, err
+  }
+  var official Conditions
+  if err := parseJSON(b, &official); err != nil {
+    return nil, err
+  }
+  if err := official.Response.Err(); err != nil {
+    return nil, err
+  }
+  return &official.Current_observation, nil
+}
+
+// PrintCalibrationWarning warns if the personal weather station's
+// temperature is far from the official station's
+func PrintCalibrationWarning(pws, official *Current, w io.Writer) {
+  diff, err := CompareStationReadings(pws, official)
+  if err != nil {
+    fmt.Fprintln(os.Stderr, "PWS calibration check skipped: "+err.Error())
+    return
+  }
+  if math.Abs(diff) > pwsDriftF {
+    fmt.Fprintf(w, "⚠ PWS temperature (%s°F) differs by %.0f°F from nearest official station %s (%s°F). Data may be unreliable.\n",
+      pws.Temp_f, math.Abs(diff), official.Station_id, official.Temp_f)
+  }
+}
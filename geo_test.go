@@ -0,0 +1,166 @@
+/*
+* geo_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for distances between stations (geo.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:11:48 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "math"
+  "strings"
+  "testing"
+)
+
+func TestHaversineDistance(t *testing.T) {
+  tests := []struct {
+    name                   string
+    lat1, lon1, lat2, lon2 float64
+    miles                  float64
+  }{
+    {"New York to Los Angeles", 40.7128, -74.0060, 34.0522, -118.2437, 2445},
+    {"London to Paris", 51.5074, -0.1278, 48.8566, 2.3522, 213},
+    {"Chicago to Detroit", 41.8781, -87.6298, 42.3314, -83.0458, 237},
+    {"Lincoln to Omaha", 40.8136, -96.7026, 41.2565, -95.9345, 50},
+    {"same place", 40.8136, -96.7026, 40.8136, -96.7026, 0},
+  }
+  for _, tt := range tests {
+    got := HaversineDistance(tt.lat1, tt.lon1, tt.lat2, tt.lon2) / kmPerMile
+    if math.Abs(got-tt.miles) > 1 {
+      t.Errorf("%s: %.1f miles, want %.0f", tt.name, got, tt.miles)
+    }
+    back := HaversineDistance(tt.lat2, tt.lon2, tt.lat1, tt.lon1) / kmPerMile
+    if math.Abs(back-got) > 1e-9 {
+      t.Errorf("%s: %.1f miles one way but %.1f the other", tt.name, got, back)
+    }
+  }
+}
+
+func TestPrintStationDistance(t *testing.T) {
+  tests := []struct {
+    lat, lon string
+    want     string
+    far      bool
+  }{
+    {"40.8136", "-96.7026", "Reporting station KOMA is 50.4 miles from Lincoln, NE\n", true},
+    {"41.2000", "-95.9000", "Reporting station KOMA is 4.3 miles from Lincoln, NE\n", false},
+    {"", "", "", false},
+  }
+  for _, tt := range tests {
+    var obs Conditions
+    obs.Location.City, obs.Location.State = "Lincoln", "NE"
+    obs.Location.Lat, obs.Location.Lon = tt.lat, tt.lon
+    obs.Current_observation.Station_id = "KOMA"
+    obs.Current_observation.Observation_location.Latitude = "41.2565"
+    obs.Current_observation.Observation_location.Longitude = "-95.9345"
+    var buf bytes.Buffer
+    PrintStationDistance(&obs, &buf)
+    if !strings.HasPrefix(buf.String(), tt.want) {
+      t.Errorf("PrintStationDistance(%s,%s) = %q, want %q", tt.lat, tt.lon, buf.String(), tt.want)
+    }
+    if far := strings.Contains(buf.String(), "⚠ Station is far"); far != tt.far {
+      t.Errorf("PrintStationDistance(%s,%s) warned %v, want %v", tt.lat, tt.lon, far, tt.far)
+    }
+  }
+}
+
+func TestNearestOfficialStation(t *testing.T) {
+  var obs Conditions
+  err := json.Unmarshal([]byte(`{
+    "current_observation": {"station_id": "KILCHICA52",
+      "observation_location": {"latitude": "41.90", "longitude": "-87.65"}},
+    "location": {"nearby_weather_stations": {"airport": {"station": [
+      {"icao": "KPWK", "lat": "42.12", "lon": "-87.90"},
+      {"icao": "KMDW", "lat": "41.79", "lon": "-87.75"},
+      {"icao": "KORD", "lat": "41.98", "lon": "-87.90"}]}}}}`), &obs)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if icao, ok := NearestOfficialStation(&obs); !ok || icao != "KMDW" {
+    t.Errorf("NearestOfficialStation = %q, %v, want KMDW", icao, ok)
+  }
+}
+
+func TestPrintCalibrationWarning(t *testing.T) {
+  official := Current{Station_id: "KORD", Temp_f: "73"}
+  tests := []struct {
+    pws  string
+    want string
+  }{
+    {"88", "⚠ PWS temperature (88°F) differs by 15°F from nearest official station KORD (73°F). Data may be unreliable.\n"},
+    {"60", "⚠ PWS temperature (60°F) differs by 13°F from nearest official station KORD (73°F). Data may be unreliable.\n"},
+    {"80", ""},
+    {"63", ""},
+  }
+  for _, tt := range tests {
+    pws := Current{Station_id: "KILCHICA52", Temp_f: Value(tt.pws)}
+    var buf bytes.Buffer
+    PrintCalibrationWarning(&pws, &official, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintCalibrationWarning(%s°F) = %q, want %q", tt.pws, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestCoordinatesStation(t *testing.T) {
+  tests := []struct {
+    lat, lon string
+    want     string
+    err      string
+  }{
+    {"40.7128", "-74.0060", "40.7128,-74.0060", ""},
+    {"-90", "180", "-90,180", ""},
+    {"90.5", "-74.0060", "", "latitude 90.5 is not between -90 and 90"},
+    {"-91", "0", "", "latitude -91 is not between -90 and 90"},
+    {"40.7128", "-180.1", "", "longitude -180.1 is not between -180 and 180"},
+    {"40.7128", "", "", "-lat and -lon must be given together"},
+    {"", "-74.0060", "", "-lat and -lon must be given together"},
+    {"north", "-74.0060", "", "-lat and -lon must be decimal degrees (e.g. -lat 40.7128 -lon -74.0060)"},
+  }
+  for _, tt := range tests {
+    got, err := CoordinatesStation(tt.lat, tt.lon)
+    if tt.err != "" {
+      if err == nil || err.Error() != tt.err {
+        t.Errorf("CoordinatesStation(%q, %q) error = %v, want %q", tt.lat, tt.lon, err, tt.err)
+      }
+      continue
+    }
+    if err != nil || got != tt.want {
+      t.Errorf("CoordinatesStation(%q, %q) = %q, %v, want %q", tt.lat, tt.lon, got, err, tt.want)
+    }
+  }
+}
+
+func TestCoordinatesStationURL(t *testing.T) {
+  coords, err := CoordinatesStation("40.7128", "-74.0060")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if got, want := BuildURL([]string{"conditions"}, coords), BuildURL([]string{"conditions"}, "40.7128,-74.0060"); got != want {
+    t.Errorf("-lat -lon URL = %q, want the -s URL %q", got, want)
+  }
+}
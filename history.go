@@ -30,100 +30,164 @@ package main
 
 import (
   "fmt"
+  "io"
   "math"
-  "os"
+  "sort"
   "strconv"
+  "strings"
+  "sync"
+  "text/tabwriter"
+  "time"
 )
 
 type History struct {
-  Date         Date // Defined in wu.go
-  Observations []Observations
-  Dailysummary []Dailysummary
+  Date         Date           `json:"date"` // Defined in wu.go
+  Observations []Observations `json:"observations"`
+  Dailysummary []Dailysummary `json:"dailysummary"`
 }
 
 type Observations struct {
+  Date      Date   `json:"date"`
+  Tempi     string `json:"tempi"`
+  Tempm     string `json:"tempm"`
+  Hum       string `json:"hum"`
+  Pressurei string `json:"pressurei"`
+  Pressurem string `json:"pressurem"`
+  Wspdi     string `json:"wspdi"`
+  Wspdm     string `json:"wspdm"`
+  Precipi   string `json:"precipi"`
+  Precipm   string `json:"precipm"`
+  Conds     string `json:"conds"`
+  Icon      string `json:"icon"`
 }
 
 type Dailysummary struct {
-  Fog                                string
-  Rain                               string
-  Snow                               string
-  Snowfallm                          string
-  Snowfalli                          string
-  Monthtodatesnowfallm               string
-  Monthtodatesnowfalli               string
-  Since1julsnowfallm                 string
-  Since1julsnowfalli                 string
-  Snowdepthm                         string
-  Snowdepthi                         string
-  Hail                               string
-  Thunder                            string
-  Tornado                            string
-  Meantempm                          string
-  Meantempi                          string
-  Meandewptm                         string
-  Meandewpti                         string
-  Meanpressurem                      string
-  Meanpressurei                      string
-  Meanwindspdm                       string
-  Meanwindspdi                       string
-  Meanwdire                          string
-  Meanwdird                          string
-  Meanvism                           string
-  Meanvisi                           string
-  Humidity                           string
-  Maxtempm                           string
-  Maxtempi                           string
-  Mintempm                           string
-  Mintempi                           string
-  Maxhumidity                        string
-  Minhumidity                        string
-  Maxdewptm                          string
-  Maxdewpti                          string
-  Mindewptm                          string
-  Mindewpti                          string
-  Maxpressurem                       string
-  Maxpressurei                       string
-  Minpressurem                       string
-  Minpressurei                       string
-  Maxwspdm                           string
-  Maxwspdi                           string
-  Minwspdm                           string
-  Minwspdi                           string
-  Maxvism                            string
-  Maxvisi                            string
-  Minvism                            string
-  Minvisi                            string
-  Gdegreedays                        string
-  Heatingdegreedays                  string
-  Coolingdegreedays                  string
-  Precipm                            string
-  Precipi                            string
-  Heatingdegreedaysnormal            string
-  Monthtodateheatingdegreedays       string
-  Monthtodateheatingdegreedaysnormal string
-  Since1sepheatingdegreedays         string
-  Since1sepheatingdegreedaysnormal   string
-  Since1julheatingdegreedays         string
-  Since1julheatingdegreedaysnormal   string
-  Coolingdegreedaysnormal            string
-  Monthtodatecoolingdegreedays       string
-  Monthtodatecoolingdegreedaysnormal string
-  Since1sepcoolingdegreedays         string
-  Since1sepcoolingdegreedaysnormal   string
-  Since1jancoolingdegreedays         string
-  Since1jancoolingdegreedaysnormal   string
-}
-
-func PrintHistory(obs *Conditions, stationId string) {
+  Fog                                string `json:"fog"`
+  Rain                               string `json:"rain"`
+  Snow                               string `json:"snow"`
+  Snowfallm                          string `json:"snowfallm"`
+  Snowfalli                          string `json:"snowfalli"`
+  Monthtodatesnowfallm               string `json:"monthtodatesnowfallm"`
+  Monthtodatesnowfalli               string `json:"monthtodatesnowfalli"`
+  Since1julsnowfallm                 string `json:"since1julsnowfallm"`
+  Since1julsnowfalli                 string `json:"since1julsnowfalli"`
+  Snowdepthm                         string `json:"snowdepthm"`
+  Snowdepthi                         string `json:"snowdepthi"`
+  Hail                               string `json:"hail"`
+  Thunder                            string `json:"thunder"`
+  Tornado                            string `json:"tornado"`
+  Meantempm                          string `json:"meantempm"`
+  Meantempi                          string `json:"meantempi"`
+  Meandewptm                         string `json:"meandewptm"`
+  Meandewpti                         string `json:"meandewpti"`
+  Meanpressurem                      string `json:"meanpressurem"`
+  Meanpressurei                      string `json:"meanpressurei"`
+  Meanwindspdm                       string `json:"meanwindspdm"`
+  Meanwindspdi                       string `json:"meanwindspdi"`
+  Meanwdire                          string `json:"meanwdire"`
+  Meanwdird                          string `json:"meanwdird"`
+  Meanvism                           string `json:"meanvism"`
+  Meanvisi                           string `json:"meanvisi"`
+  Humidity                           string `json:"humidity"`
+  Maxtempm                           string `json:"maxtempm"`
+  Maxtempi                           string `json:"maxtempi"`
+  Mintempm                           string `json:"mintempm"`
+  Mintempi                           string `json:"mintempi"`
+  Maxhumidity                        string `json:"maxhumidity"`
+  Minhumidity                        string `json:"minhumidity"`
+  Maxdewptm                          string `json:"maxdewptm"`
+  Maxdewpti                          string `json:"maxdewpti"`
+  Mindewptm                          string `json:"mindewptm"`
+  Mindewpti                          string `json:"mindewpti"`
+  Maxpressurem                       string `json:"maxpressurem"`
+  Maxpressurei                       string `json:"maxpressurei"`
+  Minpressurem                       string `json:"minpressurem"`
+  Minpressurei                       string `json:"minpressurei"`
+  Maxwspdm                           string `json:"maxwspdm"`
+  Maxwspdi                           string `json:"maxwspdi"`
+  Minwspdm                           string `json:"minwspdm"`
+  Minwspdi                           string `json:"minwspdi"`
+  Maxvism                            string `json:"maxvism"`
+  Maxvisi                            string `json:"maxvisi"`
+  Minvism                            string `json:"minvism"`
+  Minvisi                            string `json:"minvisi"`
+  Gdegreedays                        string `json:"gdegreedays"`
+  Heatingdegreedays                  string `json:"heatingdegreedays"`
+  Coolingdegreedays                  string `json:"coolingdegreedays"`
+  Precipm                            string `json:"precipm"`
+  Precipi                            string `json:"precipi"`
+  Heatingdegreedaysnormal            string `json:"heatingdegreedaysnormal"`
+  Monthtodateheatingdegreedays       string `json:"monthtodateheatingdegreedays"`
+  Monthtodateheatingdegreedaysnormal string `json:"monthtodateheatingdegreedaysnormal"`
+  Since1sepheatingdegreedays         string `json:"since1sepheatingdegreedays"`
+  Since1sepheatingdegreedaysnormal   string `json:"since1sepheatingdegreedaysnormal"`
+  Since1julheatingdegreedays         string `json:"since1julheatingdegreedays"`
+  Since1julheatingdegreedaysnormal   string `json:"since1julheatingdegreedaysnormal"`
+  Coolingdegreedaysnormal            string `json:"coolingdegreedaysnormal"`
+  Monthtodatecoolingdegreedays       string `json:"monthtodatecoolingdegreedays"`
+  Monthtodatecoolingdegreedaysnormal string `json:"monthtodatecoolingdegreedaysnormal"`
+  Since1sepcoolingdegreedays         string `json:"since1sepcoolingdegreedays"`
+  Since1sepcoolingdegreedaysnormal   string `json:"since1sepcoolingdegreedaysnormal"`
+  Since1jancoolingdegreedays         string `json:"since1jancoolingdegreedays"`
+  Since1jancoolingdegreedaysnormal   string `json:"since1jancoolingdegreedaysnormal"`
+}
+
+// EstimatePercentile estimates the percentile of value among all the
+// observations for a date, assuming they are normally distributed about
+// mean with the records lying three standard deviations either side
+func EstimatePercentile(value, mean, recordHigh, recordLow float64) float64 {
+  sigma := (recordHigh - recordLow) / 6
+  if sigma <= 0 {
+    return math.NaN()
+  }
+  return 50 * (1 + math.Erf((value-mean)/(sigma*math.Sqrt2)))
+}
+
+// ordinal returns n with its English ordinal suffix (1st, 2nd, ...)
+func ordinal(n int) string {
+  suffix := "th"
+  switch {
+  case n%100 >= 11 && n%100 <= 13:
+  case n%10 == 1:
+    suffix = "st"
+  case n%10 == 2:
+    suffix = "nd"
+  case n%10 == 3:
+    suffix = "rd"
+  }
+  return strconv.Itoa(n) + suffix
+}
+
+// percentileNote returns " (Nth percentile for this date)" if
+// -history-percentile was given and the temperature and almanac are
+// available.  The almanac normals and records are today's, which is
+// the closest the API offers for a past date.
+func percentileNote(tempF, normalF string, almanac *Almanac) string {
+  if !dopercentile {
+    return ""
+  }
+  value, err1 := strconv.ParseFloat(tempF, 64)
+  mean, err2 := strconv.ParseFloat(normalF, 64)
+  high, err3 := strconv.ParseFloat(almanac.Temp_high.Record.F, 64)
+  low, err4 := strconv.ParseFloat(almanac.Temp_low.Record.F, 64)
+  if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+    return ""
+  }
+  p := EstimatePercentile(value, mean, high, low)
+  if math.IsNaN(p) {
+    return ""
+  }
+  return fmt.Sprintf(" (%s percentile for this date)", ordinal(int(math.Min(99, math.Max(1, math.Round(p))))))
+}
+
+func PrintHistory(obs *Conditions, stationId string, units *Units) {
 
   if len(obs.History.Observations) == 0 {
-    fmt.Println("No data available for specified date")
-    os.Exit(0)
+    Fail(APIError, "No data available for specified date")
   }
 
   history := obs.History.Dailysummary[0]
-  fmt.Printf("Weather summary for %s: ", obs.History.Date.Pretty)
+  fmt.Printf("Weather summary for %s: ", units.Locale.FormatDate(obs.History.Date.Pretty))
   if history.Fog == "1" {
     fmt.Print("fog ")
   }
@@ -153,11 +217,11 @@ func PrintHistory(obs *Conditions, stationId string) {
     if history.Snowfalli == "T" {
       fmt.Println("     trace")
     } else if history.Snowfalli >= "0.00" {
-      fmt.Printf("     %s in (%s mm)\n", history.Snowfalli, history.Snowfallm)
+      fmt.Printf("     %s\n", units.Precip(history.Snowfalli, history.Snowfallm))
 
-      fmt.Printf("     Snow depth: %s in (%s mm)\n", history.Snowdepthi, history.Snowdepthm)
-      fmt.Printf("     Month to date: %s in (%s mm)\n", history.Monthtodatesnowfalli, history.Monthtodatesnowfallm)
-      fmt.Printf("     Since July 1st: %s in (%s mm)\n", history.Since1julsnowfalli, history.Since1julsnowfallm)
+      fmt.Printf("     Snow depth: %s\n", units.Precip(history.Snowdepthi, history.Snowdepthm))
+      fmt.Printf("     Month to date: %s\n", units.Precip(history.Monthtodatesnowfalli, history.Monthtodatesnowfallm))
+      fmt.Printf("     Since July 1st: %s\n", units.Precip(history.Since1julsnowfalli, history.Since1julsnowfallm))
     }
   }
 
@@ -167,16 +231,18 @@ func PrintHistory(obs *Conditions, stationId string) {
     if history.Precipi == "T" {
       fmt.Printf("   Precipitation: trace\n")
     } else {
-      fmt.Printf("   Precipitation: %s in (%s mm)\n", history.Precipi, history.Precipm)
+      fmt.Printf("   Precipitation: %s\n", units.Precip(history.Precipi, history.Precipm))
     }
   }
 
   // Temperature
 
   fmt.Println("   Temperature:")
-  fmt.Printf("      Mean Temperature: %s F (%s C)\n", history.Meantempi, history.Meantempm)
-  fmt.Printf("      Max Temperature: %s F (%s C)\n", history.Maxtempi, history.Maxtempm)
-  fmt.Printf("      Min Temperature: %s F (%s C)\n", history.Mintempi, history.Mintempm)
+  fmt.Printf("      Mean Temperature: %s\n", units.Temp(history.Meantempi, history.Meantempm))
+  fmt.Printf("      Max Temperature: %s%s\n", units.Temp(history.Maxtempi, history.Maxtempm),
+    percentileNote(history.Maxtempi, obs.Almanac.Temp_high.Normal.F, &obs.Almanac))
+  fmt.Printf("      Min Temperature: %s%s\n", units.Temp(history.Mintempi, history.Mintempm),
+    percentileNote(history.Mintempi, obs.Almanac.Temp_low.Normal.F, &obs.Almanac))
 
   // Degree Days
 
@@ -220,9 +286,9 @@ func PrintHistory(obs *Conditions, stationId string) {
   // Moisture
 
   fmt.Println("   Moisture:")
-  fmt.Printf("      Mean Dew Point: %s (%s C)\n", history.Meandewpti, history.Meandewptm)
-  fmt.Printf("      Max Dew Point: %s (%s C)\n", history.Maxdewpti, history.Maxdewptm)
-  fmt.Printf("      Min Dew Point: %s (%s C)\n", history.Mindewpti, history.Mindewptm)
+  fmt.Printf("      Mean Dew Point: %s\n", units.Temp(history.Meandewpti, history.Meandewptm))
+  fmt.Printf("      Max Dew Point: %s\n", units.Temp(history.Maxdewpti, history.Maxdewptm))
+  fmt.Printf("      Min Dew Point: %s\n", units.Temp(history.Mindewpti, history.Mindewptm))
   if history.Humidity != "" {
     fmt.Printf("      Humidity: %s%%\n", history.Humidity)
   }
@@ -232,9 +298,9 @@ func PrintHistory(obs *Conditions, stationId string) {
   // Pressure
 
   fmt.Println("   Pressure:")
-  fmt.Printf("      Mean Pressure: %s in (%s mb)\n", history.Meanpressurei, history.Meanpressurem)
-  fmt.Printf("      Max Pressure: %s in (%s mb)\n", history.Maxpressurei, history.Maxpressurem)
-  fmt.Printf("      Min Pressure: %s in (%s mb)\n", history.Minpressurei, history.Minpressurem)
+  fmt.Printf("      Mean Pressure: %s\n", units.Press(history.Meanpressurei, history.Meanpressurem))
+  fmt.Printf("      Max Pressure: %s\n", units.Press(history.Maxpressurei, history.Maxpressurem))
+  fmt.Printf("      Min Pressure: %s\n", units.Press(history.Minpressurei, history.Minpressurem))
 
   // Wind
 
@@ -254,6 +320,425 @@ func PrintHistory(obs *Conditions, stationId string) {
 
 }
 
+// HistoryDay is the daily summary for one day of a --history-range.
+// Summary is empty when the station has no data for that day.
+type HistoryDay struct {
+  Date    time.Time
+  Summary Dailysummary
+}
+
+// ParseHistoryRange parses a --history-range argument (YYYYMMDD-YYYYMMDD)
+func ParseHistoryRange(r string) (time.Time, time.Time, error) {
+  var start, end time.Time
+  dates := strings.Split(r, "-")
+  if len(dates) != 2 {
+    return start, end, fmt.Errorf("history range must be YYYYMMDD-YYYYMMDD")
+  }
+  start, err := time.Parse("20060102", dates[0])
+  if err != nil {
+    return start, end, err
+  }
+  end, err = time.Parse("20060102", dates[1])
+  if err != nil {
+    return start, end, err
+  }
+  if end.Before(start) {
+    return start, end, fmt.Errorf("history range ends before it starts")
+  }
+  if end.Sub(start) > 366*24*time.Hour {
+    return start, end, fmt.Errorf("history range may not exceed one year")
+  }
+  return start, end, nil
+}
+
+// FetchHistoryRange retrieves the daily summary for each day from
+// start to end (inclusive), as FetchHistoryDays does
+func FetchHistoryRange(start, end time.Time, stationId string) ([]HistoryDay, error) {
+  dates := make([]time.Time, 0)
+  for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+    dates = append(dates, d)
+  }
+  return FetchHistoryDays(dates, stationId)
+}
+
+// FetchHistoryDays retrieves the daily summary for each of dates, a
+// few requests at a time and no more than -history-rate a minute.  A
+// day the API has no summary for is left empty (see hasData), but a
+// request that fails stops the rest and its error is returned.
+func FetchHistoryDays(dates []time.Time, stationId string) ([]HistoryDay, error) {
+  const maxRequests = 4
+
+  days := make([]HistoryDay, len(dates))
+  for i, d := range dates {
+    days[i].Date = d
+  }
+
+  var wg sync.WaitGroup
+  var mu sync.Mutex
+  var firstErr error
+  fail := func(date time.Time, err error) {
+    mu.Lock()
+    defer mu.Unlock()
+    if firstErr == nil {
+      firstErr = Classify(codeOf(err), fmt.Errorf("history for %s: %v", date.Format("2006-01-02"), err))
+    }
+  }
+  failed := func() bool {
+    mu.Lock()
+    defer mu.Unlock()
+    return firstErr != nil
+  }
+
+  requests := make(chan bool, maxRequests)
+  throttle := time.NewTicker(time.Minute / time.Duration(historyrate))
+  defer throttle.Stop()
+  for i := range days {
+    if i > 0 {
+      <-throttle.C
+    }
+    if failed() {
+      break
+    }
+    wg.Add(1)
+    go func(day *HistoryDay) {
+      defer wg.Done()
+      requests <- true
+      defer func() { <-requests }()
+      b, err := Fetch(BuildURL([]string{"history_" + day.Date.Format("20060102")}, stationId))
+      if err != nil {
+        fail(day.Date, err)
+        return
+      }
+      var obs Conditions
+      if err := parseJSON(b, &obs); err != nil {
+        fail(day.Date, err)
+        return
+      }
+      if err := obs.Response.Err(); err != nil {
+        fail(day.Date, err)
+        return
+      }
+      if len(obs.History.Dailysummary) > 0 {
+        day.Summary = obs.History.Dailysummary[0]
+      }
+    }(&days[i])
+  }
+  wg.Wait()
+  return days, firstErr
+}
+
+// Time returns the observation time as a wall-clock time (in UTC, since
+// the API gives the date in the station's own time zone)
+func (d *Date) Time() (time.Time, bool) {
+  t, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("%s-%s-%s %s:%s", d.Year, d.Mon, d.Mday, d.Hour, d.Min))
+  return t, err == nil
+}
+
+// FindClosestObservation returns the observation nearest in time to
+// target (a wall-clock time at the station).  It is an error for
+// target to be more than an hour past the last observation, since
+// that's in the future or in a gap in the station's reports.
+func FindClosestObservation(observations []Observations, target time.Time) (*Observations, error) {
+  var closest *Observations
+  var best time.Duration
+  var latest time.Time
+  for i := range observations {
+    t, ok := observations[i].Date.Time()
+    if !ok {
+      continue
+    }
+    if t.After(latest) {
+      latest = t
+    }
+    d := target.Sub(t)
+    if d < 0 {
+      d = -d
+    }
+    if closest == nil || d < best {
+      closest, best = &observations[i], d
+    }
+  }
+  if closest == nil {
+    return nil, fmt.Errorf("no observations are available for %s", target.Format("January 2, 2006"))
+  }
+  if target.Sub(latest) > time.Hour {
+    return nil, fmt.Errorf("no observations are available yet for %s", target.Format("3:04 PM, January 2, 2006"))
+  }
+  return closest, nil
+}
+
+// PrintObservation prints a single observation from the history
+func PrintObservation(o *Observations, stationId string, units *Units) {
+  fmt.Printf("Conditions at %s at %s\n", stationId, o.Date.Pretty)
+  fmt.Println("   Temperature:", units.Temp(o.Tempi, o.Tempm))
+  fmt.Println("   Sky Conditions:", o.Conds)
+  fmt.Printf("   Humidity: %s%%\n", o.Hum)
+  fmt.Println("   Wind Speed:", units.Number(o.Wspdi+" mph"))
+  fmt.Println("   Pressure:", units.Press(o.Pressurei, o.Pressurem))
+  fmt.Println("   Precipitation:", units.Precip(o.Precipi, o.Precipm))
+}
+
+// FetchObservations retrieves the individual observations the station
+// reported on date
+func FetchObservations(date time.Time, stationId string) ([]Observations, error) {
+  b, err := Fetch(BuildURL([]string{"history_" + date.Format("20060102")}, stationId))
+  if err != nil {
+    return nil, err
+  }
+  var obs Conditions
+  if err := parseJSON(b, &obs); err != nil {
+    return nil, err
+  }
+  return obs.History.Observations, obs.Response.Err()
+}
+
+// PrintHistoryRange prints one line per day of a --history-range
+func PrintHistoryRange(days []HistoryDay, stationId string, units *Units) {
+  fmt.Printf("Weather history for %s\n", stationId)
+  for _, day := range days {
+    s := day.Summary
+    if s.Maxtempi == "" {
+      fmt.Printf("   %s: no data available\n", day.Date.Format("Jan 02 2006"))
+      continue
+    }
+    fmt.Printf("   %s: high %s, low %s, precipitation %s\n", day.Date.Format("Jan 02 2006"),
+      units.Temp(s.Maxtempi, s.Maxtempm), units.Temp(s.Mintempi, s.Mintempm),
+      units.Precip(s.Precipi, s.Precipm))
+  }
+}
+
+// WeekdayStats accumulates the daily summaries that fall on one day
+// of the week (in Fahrenheit and inches)
+type WeekdayStats struct {
+  Count      int
+  RainyDays  int
+  MaxTempSum float64
+  PrecipSum  float64
+}
+
+// GroupByWeekday totals days by day of the week (indexed by
+// time.Weekday), skipping days with no data.  A trace of rain counts
+// as a rainy day but adds nothing to the total.
+func GroupByWeekday(days []HistoryDay) [7]WeekdayStats {
+  var stats [7]WeekdayStats
+  for _, day := range days {
+    max, err := strconv.ParseFloat(day.Summary.Maxtempi, 64)
+    if err != nil {
+      continue
+    }
+    w := &stats[day.Date.Weekday()]
+    w.Count++
+    w.MaxTempSum += max
+    if day.Summary.Precipi == "T" {
+      w.RainyDays++
+    } else if precip, err := strconv.ParseFloat(day.Summary.Precipi, 64); err == nil {
+      w.PrecipSum += precip
+      if precip > 0 {
+        w.RainyDays++
+      }
+    }
+  }
+  return stats
+}
+
+// PrintWeekdayAverages prints the average high, precipitation, and
+// frequency of rain for each day of the week
+func PrintWeekdayAverages(stats [7]WeekdayStats, stationId string, units *Units) {
+  fmt.Printf("Averages by day of the week for %s\n", stationId)
+  for d, w := range stats {
+    if w.Count == 0 {
+      fmt.Printf("   %-9s  no data available\n", time.Weekday(d))
+      continue
+    }
+    n := float64(w.Count)
+    high := w.MaxTempSum / n
+    precip := w.PrecipSum / n
+    fmt.Printf("   %-9s  high %s, precipitation %s, rain on %d of %d days (%.0f%%)\n", time.Weekday(d),
+      units.Temp(fmt.Sprintf("%.1f", high), fmt.Sprintf("%.1f", (high-32)*5/9)),
+      units.Precip(fmt.Sprintf("%.2f", precip), fmt.Sprintf("%.1f", precip*25.4)),
+      w.RainyDays, w.Count, 100*float64(w.RainyDays)/n)
+  }
+}
+
+// FindFreezeDates returns the last day in the first half of year (the
+// spring) and the first day in the second half (the fall) with a low
+// at or below freezing.  A zero time means there was no such day; the
+// error is for days holding no data at all.
+func FindFreezeDates(days []HistoryDay, year int) (lastSpring, firstFall time.Time, err error) {
+  midyear := time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC)
+  found := false
+  for _, day := range days {
+    if day.Date.Year() != year {
+      continue
+    }
+    low, perr := strconv.ParseFloat(day.Summary.Mintempi, 64)
+    if perr != nil {
+      continue
+    }
+    found = true
+    if low > 32 {
+      continue
+    }
+    if day.Date.Before(midyear) {
+      lastSpring = day.Date
+    } else if firstFall.IsZero() {
+      firstFall = day.Date
+    }
+  }
+  if !found {
+    err = fmt.Errorf("no temperatures recorded in %d", year)
+  }
+  return lastSpring, firstFall, err
+}
+
+// PrintFreezeDates prints the last spring and first fall freeze dates
+// for year
+func PrintFreezeDates(days []HistoryDay, year int, stationId string) {
+  lastSpring, firstFall, err := FindFreezeDates(days, year)
+  CheckError(Classify(APIError, err))
+  fmt.Printf("Freeze dates for %s in %d\n", stationId, year)
+  if lastSpring.IsZero() {
+    fmt.Printf("   No freeze recorded spring %d.\n", year)
+  } else {
+    fmt.Printf("   Last spring freeze: %s\n", lastSpring.Format("Monday, January 2"))
+  }
+  if firstFall.IsZero() {
+    fmt.Printf("   No freeze recorded fall %d.\n", year)
+  } else {
+    fmt.Printf("   First fall freeze: %s\n", firstFall.Format("Monday, January 2"))
+  }
+}
+
+// hasData reports whether a daily summary holds any data
+func hasData(s *Dailysummary) bool {
+  return !isNullish(s.Maxtempi)
+}
+
+// StationUptime counts the days that came back with data (a failed
+// request is an error from FetchHistoryDays, not a missing day)
+func StationUptime(days []HistoryDay) (reported, total int, pct float64) {
+  for _, day := range days {
+    if hasData(&day.Summary) {
+      reported++
+    }
+  }
+  total = len(days)
+  if total > 0 {
+    pct = 100 * float64(reported) / float64(total)
+  }
+  return reported, total, pct
+}
+
+// uptimeWarning is the uptime (percent) below which PrintStationUptime
+// suggests another station
+const uptimeWarning = 80
+
+// PrintStationUptime prints how many of days the station reported data
+func PrintStationUptime(days []HistoryDay, stationId string, w io.Writer) {
+  reported, total, pct := StationUptime(days)
+  fmt.Fprintf(w, "Station %s has reported data %d out of the last %d days (%.0f%%).\n", stationId, reported, total, pct)
+  if pct < uptimeWarning {
+    fmt.Fprintf(w, "⚠ Station reliability: %.0f%% — consider using an alternate station.\n", pct)
+  }
+}
+
+// MissingDataReport lists the dates (keys of days, YYYY-MM-DD) whose
+// value is false, i.e. that came back without data, or returns "" if
+// there are none
+func MissingDataReport(days map[string]bool) string {
+  missing := make([]string, 0)
+  for date, present := range days {
+    if !present {
+      missing = append(missing, date)
+    }
+  }
+  if len(missing) == 0 {
+    return ""
+  }
+  sort.Strings(missing)
+  return fmt.Sprintf("Missing data for: %s (%d of %d days).\nTry these dates again with wu --history YYYYMMDD.",
+    strings.Join(missing, ", "), len(missing), len(days))
+}
+
+// PrintMissingData prints MissingDataReport for a --history-range
+func PrintMissingData(days []HistoryDay, w io.Writer) {
+  present := make(map[string]bool)
+  for _, day := range days {
+    present[day.Date.Format("2006-01-02")] = hasData(&day.Summary)
+  }
+  if report := MissingDataReport(present); report != "" {
+    fmt.Fprintln(w, report)
+  } else {
+    fmt.Fprintf(w, "No missing data (%d days).\n", len(days))
+  }
+}
+
+// DecadeSummary is the average high and low (F) over the years of a
+// decade that have data
+type DecadeSummary struct {
+  Years   int
+  AvgHigh float64
+  AvgLow  float64
+}
+
+// DecadeDates returns today's date in the first year (e.g. 1990) of
+// each of the last n decades, leaving out this year.  February 29 falls
+// back to February 28 in the years that aren't leap years.
+func DecadeDates(today time.Time, n int) []time.Time {
+  dates := make([]time.Time, 0, n)
+  for year := today.Year()/10*10 - 10*(n-1); year < today.Year(); year += 10 {
+    date := time.Date(year, today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+    if date.Month() != today.Month() {
+      date = date.AddDate(0, 0, -date.Day())
+    }
+    dates = append(dates, date)
+  }
+  return dates
+}
+
+// GroupByDecade averages the highs and lows of days by decade, keyed by
+// the decade's first year (e.g. 1990).  Days with no data are skipped.
+func GroupByDecade(days []HistoryDay) map[int]DecadeSummary {
+  decades := make(map[int]DecadeSummary)
+  for _, day := range days {
+    high, err1 := strconv.ParseFloat(day.Summary.Maxtempi, 64)
+    low, err2 := strconv.ParseFloat(day.Summary.Mintempi, 64)
+    if err1 != nil || err2 != nil {
+      continue
+    }
+    decade := day.Date.Year() / 10 * 10
+    d := decades[decade]
+    n := float64(d.Years)
+    d.AvgHigh = (d.AvgHigh*n + high) / (n + 1)
+    d.AvgLow = (d.AvgLow*n + low) / (n + 1)
+    d.Years++
+    decades[decade] = d
+  }
+  return decades
+}
+
+// PrintDecadeAverages prints one row of GroupByDecade per decade
+func PrintDecadeAverages(decades map[int]DecadeSummary, date time.Time, stationId string, units *Units) {
+  fmt.Printf("Averages for %s by decade at %s\n", date.Format("January 2"), stationId)
+  if len(decades) == 0 {
+    fmt.Println("   No data available")
+    return
+  }
+  starts := make([]int, 0, len(decades))
+  for start := range decades {
+    starts = append(starts, start)
+  }
+  sort.Ints(starts)
+  for _, start := range starts {
+    d := decades[start]
+    years := "1 year"
+    if d.Years != 1 {
+      years = fmt.Sprintf("%d years", d.Years)
+    }
+    fmt.Printf("   %ds  high %s, low %s (%s)\n", start, units.Degrees(d.AvgHigh, 1), units.Degrees(d.AvgLow, 1), years)
+  }
+}
+
 // Convert wind degrees to boxed compass points.
 func boxCompass(degreeString string) string {
 
@@ -300,3 +785,129 @@ func boxCompass(degreeString string) string {
   return direction
 
 }
+
+// traceSnowfall is what a trace of snow ("T") counts as towards
+// AccumulateSnowfall's totals: enough to show that it snowed, though
+// not measurably
+const traceSnowfall = 0.001
+
+// DailySnow is one day's snowfall and the total since the start of
+// AccumulateSnowfall's range (in inches)
+type DailySnow struct {
+  Date     time.Time
+  Snowfall float64
+  Total    float64
+  Missing  bool
+}
+
+// AccumulateSnowfall returns each day's snowfall with the running
+// total, and the total for the whole range.  A day without a snowfall
+// (empty, -9999, or not a number) is Missing and adds nothing.
+func AccumulateSnowfall(days []HistoryDay) ([]DailySnow, float64) {
+  snow := make([]DailySnow, 0, len(days))
+  total := 0.0
+  for _, day := range days {
+    d := DailySnow{Date: day.Date}
+    if s := day.Summary.Snowfalli; s == "T" {
+      d.Snowfall = traceSnowfall
+    } else if f, err := strconv.ParseFloat(s, 64); err == nil && !isNullish(s) {
+      d.Snowfall = f
+    } else {
+      d.Missing = true
+    }
+    total += d.Snowfall
+    d.Total = total
+    snow = append(snow, d)
+  }
+  return snow, total
+}
+
+// formatSnowfall renders an amount of snow as PrintSnowfall shows it:
+// to a tenth of an inch, or "T" for any snow too little to show that way
+func formatSnowfall(in float64) string {
+  if in > 0 && in < 0.05 {
+    return "T"
+  }
+  return strconv.FormatFloat(in, 'f', 1, 64)
+}
+
+// PrintSnowfall prints a table of daily snowfall, with the running
+// total, and the total for the season
+func PrintSnowfall(days []HistoryDay, stationId string, w io.Writer) {
+  snow, total := AccumulateSnowfall(days)
+  fmt.Fprintf(w, "Snowfall for %s\n", stationId)
+  tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+  fmt.Fprintln(tw, "   Date\tSnowfall (in)\tSeason total (in)")
+  for _, d := range snow {
+    if d.Missing {
+      fmt.Fprintf(tw, "   %s\tno data\t%s\n", d.Date.Format("Jan 02 2006"), formatSnowfall(d.Total))
+      continue
+    }
+    fmt.Fprintf(tw, "   %s\t%s\t%s\n", d.Date.Format("Jan 02 2006"), formatSnowfall(d.Snowfall), formatSnowfall(d.Total))
+  }
+  tw.Flush()
+  fmt.Fprintf(w, "Season total: %s in\n", formatSnowfall(total))
+}
+
+// RainRecord is one day's precipitation (in inches) for
+// --history-record-rain
+type RainRecord struct {
+  Date   string  `json:"date"`
+  Precip float64 `json:"precip"`
+  Trace  bool    `json:"trace"`
+}
+
+// FindRecordRain ranks days by precipitation, wettest first.  A trace
+// ranks below any measured amount (but above none at all), and days
+// with no data are left out.
+func FindRecordRain(days []HistoryDay) []RainRecord {
+  records := make([]RainRecord, 0, len(days))
+  for _, day := range days {
+    r := RainRecord{Date: day.Date.Format("2006-01-02")}
+    if p := day.Summary.Precipi; p == "T" {
+      r.Trace = true
+    } else if in, err := strconv.ParseFloat(p, 64); err == nil && !isNullish(p) && in >= 0 {
+      r.Precip = in
+    } else {
+      continue
+    }
+    records = append(records, r)
+  }
+  sort.SliceStable(records, func(i, j int) bool {
+    if records[i].Precip != records[j].Precip {
+      return records[i].Precip > records[j].Precip
+    }
+    return records[i].Trace && !records[j].Trace
+  })
+  return records
+}
+
+// describeRain renders a RainRecord's amount (e.g. "2.34 inches")
+func describeRain(r RainRecord) string {
+  switch {
+  case r.Trace:
+    return "a trace"
+  case r.Precip == 1:
+    return "1.00 inch"
+  }
+  return fmt.Sprintf("%.2f inches", r.Precip)
+}
+
+// PrintRecordRain prints the wettest day of days, and the five wettest
+func PrintRecordRain(days []HistoryDay, stationId string, w io.Writer) {
+  const top = 5
+
+  records := FindRecordRain(days)
+  if len(records) == 0 {
+    fmt.Fprintf(w, "No precipitation data for %s in this range.\n", stationId)
+    return
+  }
+  fmt.Fprintf(w, "Wettest day: %s with %s\n", records[0].Date, describeRain(records[0]))
+  if len(records) > top {
+    records = records[:top]
+  }
+  fmt.Fprintf(w, "The %d wettest days at %s:\n", len(records), stationId)
+  for i, r := range records {
+    fmt.Fprintf(w, "   %d. %s: %s\n", i+1, r.Date, describeRain(r))
+  }
+}
@@ -0,0 +1,556 @@
+/*
+* history_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for history requests and reports (history.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:15:54 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "math"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+// summaryField is one field of the daily summaries made by
+// historyDays, with its value for each day in turn
+type summaryField struct {
+  set    func(s *Dailysummary, v string)
+  values []string
+}
+
+func maxTemps(v ...string) summaryField {
+  return summaryField{func(s *Dailysummary, v string) { s.Maxtempi = v }, v}
+}
+
+func minTemps(v ...string) summaryField {
+  return summaryField{func(s *Dailysummary, v string) { s.Mintempi = v }, v}
+}
+
+func precips(v ...string) summaryField {
+  return summaryField{func(s *Dailysummary, v string) { s.Precipi = v }, v}
+}
+
+func snowfalls(v ...string) summaryField {
+  return summaryField{func(s *Dailysummary, v string) { s.Snowfalli = v }, v}
+}
+
+// historyDays returns a daily summary for each day from start, with
+// fields filled in, for as many days as the longest field has values
+func historyDays(start time.Time, fields ...summaryField) []HistoryDay {
+  n := 0
+  for _, f := range fields {
+    if len(f.values) > n {
+      n = len(f.values)
+    }
+  }
+  days := make([]HistoryDay, n)
+  for i := range days {
+    days[i].Date = start.AddDate(0, 0, i)
+    for _, f := range fields {
+      if i < len(f.values) {
+        f.set(&days[i].Summary, f.values[i])
+      }
+    }
+  }
+  return days
+}
+
+func TestGroupByWeekday(t *testing.T) {
+  // Sunday, September 1 to Saturday, September 14, 2013
+  start := time.Date(2013, 9, 1, 0, 0, 0, 0, time.UTC)
+  days := historyDays(start,
+    maxTemps("80", "70", "72", "74", "76", "78", "60", "90", "72", "76", "70", "80", "82", "62"),
+    precips("0.00", "0.50", "T", "0.00", "0.00", "0.00", "1.00", "0.00", "0.20", "0.00", "0.00", "0.00", "0.00", "0.00"))
+  stats := GroupByWeekday(days)
+  tests := []struct {
+    day       time.Weekday
+    avgHigh   float64
+    avgPrecip float64
+    rainyDays int
+  }{
+    {time.Sunday, 85, 0, 0},
+    {time.Monday, 71, 0.35, 2},
+    {time.Tuesday, 74, 0, 1},
+    {time.Wednesday, 72, 0, 0},
+    {time.Thursday, 78, 0, 0},
+    {time.Friday, 80, 0, 0},
+    {time.Saturday, 61, 0.5, 1},
+  }
+  for _, tt := range tests {
+    s := stats[tt.day]
+    if s.Count != 2 {
+      t.Errorf("%s has %d days, want 2", tt.day, s.Count)
+      continue
+    }
+    if got := s.MaxTempSum / 2; got != tt.avgHigh {
+      t.Errorf("%s average high = %v, want %v", tt.day, got, tt.avgHigh)
+    }
+    if got := s.PrecipSum / 2; got < tt.avgPrecip-0.001 || got > tt.avgPrecip+0.001 {
+      t.Errorf("%s average precipitation = %v, want %v", tt.day, got, tt.avgPrecip)
+    }
+    if s.RainyDays != tt.rainyDays {
+      t.Errorf("%s rainy days = %d, want %d", tt.day, s.RainyDays, tt.rainyDays)
+    }
+  }
+}
+
+func TestEstimatePercentile(t *testing.T) {
+  // A normal 80 with records of 110 and 50 gives a standard deviation
+  // of 10, so these are the standard normal table's
+  tests := []struct {
+    value float64
+    want  float64
+  }{
+    {80, 50},
+    {90, 84.134},   // z = 1
+    {100, 97.725},  // z = 2
+    {70, 15.866},   // z = -1
+    {60, 2.275},    // z = -2
+    {99.6, 97.500}, // z = 1.96
+    {110, 99.865},  // z = 3
+  }
+  for _, tt := range tests {
+    if got := EstimatePercentile(tt.value, 80, 110, 50); math.Abs(got-tt.want) > 0.001 {
+      t.Errorf("EstimatePercentile(%v) = %.3f, want %.3f", tt.value, got, tt.want)
+    }
+  }
+  if got := EstimatePercentile(80, 80, 80, 80); !math.IsNaN(got) {
+    t.Errorf("EstimatePercentile with no spread = %v, want NaN", got)
+  }
+}
+
+func TestOrdinal(t *testing.T) {
+  tests := []struct {
+    n    int
+    want string
+  }{
+    {1, "1st"}, {2, "2nd"}, {3, "3rd"}, {4, "4th"}, {11, "11th"}, {12, "12th"},
+    {13, "13th"}, {21, "21st"}, {22, "22nd"}, {98, "98th"}, {101, "101st"}, {111, "111th"},
+  }
+  for _, tt := range tests {
+    if got := ordinal(tt.n); got != tt.want {
+      t.Errorf("ordinal(%d) = %q, want %q", tt.n, got, tt.want)
+    }
+  }
+}
+
+// freezeYear is 2023 with lows of 40°F, except for freezes in January
+// and February, on April 10, on October 5, and in November
+func freezeYear() []HistoryDay {
+  start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+  days := make([]HistoryDay, 365)
+  for i := range days {
+    d := start.AddDate(0, 0, i)
+    low := "40"
+    switch {
+    case d.Month() <= time.February && d.Day()%3 == 0,
+      d.Month() == time.April && d.Day() == 10,
+      d.Month() == time.October && d.Day() == 5,
+      d.Month() == time.November && d.Day() > 20:
+      low = "28"
+    case d.Month() == time.March && d.Day() == 15:
+      low = "32"
+    case d.Month() == time.April && d.Day() == 11, d.Month() == time.October && d.Day() == 4:
+      low = "33"
+    }
+    days[i] = HistoryDay{Date: d, Summary: Dailysummary{Mintempi: low}}
+  }
+  return days
+}
+
+func TestFindFreezeDates(t *testing.T) {
+  lastSpring, firstFall, err := FindFreezeDates(freezeYear(), 2023)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if want := time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC); !lastSpring.Equal(want) {
+    t.Errorf("last spring freeze = %s, want %s", lastSpring.Format("2006-01-02"), want.Format("2006-01-02"))
+  }
+  if want := time.Date(2023, 10, 5, 0, 0, 0, 0, time.UTC); !firstFall.Equal(want) {
+    t.Errorf("first fall freeze = %s, want %s", firstFall.Format("2006-01-02"), want.Format("2006-01-02"))
+  }
+}
+
+func TestFindFreezeDatesNone(t *testing.T) {
+  days := freezeYear()
+  for i := range days {
+    if days[i].Date.Month() >= time.July {
+      days[i].Summary.Mintempi = "45"
+    }
+  }
+  lastSpring, firstFall, err := FindFreezeDates(days, 2023)
+  if err != nil || lastSpring.IsZero() || !firstFall.IsZero() {
+    t.Errorf("FindFreezeDates = %s, %s, %v; want no fall freeze", lastSpring, firstFall, err)
+  }
+  for i := range days {
+    days[i].Summary.Mintempi = ""
+  }
+  if _, _, err := FindFreezeDates(days, 2023); err == nil {
+    t.Error("FindFreezeDates with no data: no error")
+  }
+}
+
+func TestFetchHistoryDaysThrottle(t *testing.T) {
+  serveAPI(t, `{"history": {"dailysummary": [{"maxtempi": "75", "mintempi": "55"}]}}`)
+  saved := historyrate
+  t.Cleanup(func() { historyrate = saved })
+  historyrate = 1200 // one request every 50ms
+
+  dates := make([]time.Time, 5)
+  for i := range dates {
+    dates[i] = time.Date(2023, 7, 1+i, 0, 0, 0, 0, time.UTC)
+  }
+  start := time.Now()
+  days, err := FetchHistoryDays(dates, "KLNK")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+    t.Errorf("5 requests at 1200 a minute took %s, want at least 200ms", elapsed)
+  }
+  for _, d := range days {
+    if d.Summary.Maxtempi != "75" {
+      t.Errorf("%s: high = %q, want 75", d.Date.Format("2006-01-02"), d.Summary.Maxtempi)
+    }
+  }
+}
+
+func TestFindClosestObservation(t *testing.T) {
+  observation := func(hour, min string) Observations {
+    return Observations{Date: Date{Year: "2023", Mon: "07", Mday: "04", Hour: hour, Min: min}, Tempi: hour + min}
+  }
+  observations := []Observations{
+    observation("11", "54"), observation("12", "54"), observation("13", "20"),
+    observation("13", "54"), observation("14", "54"), observation("15", "54"),
+  }
+  tests := []struct {
+    target string
+    want   string
+  }{
+    {"2023-07-04T14:00", "1354"},
+    {"2023-07-04T14:30", "1454"},
+    {"2023-07-04T13:10", "1320"},
+    {"2023-07-04T08:00", "1154"},
+    {"2023-07-04T16:40", "1554"},
+  }
+  for _, tt := range tests {
+    target, _ := time.Parse("2006-01-02T15:04", tt.target)
+    o, err := FindClosestObservation(observations, target)
+    if err != nil || o.Tempi != tt.want {
+      t.Errorf("FindClosestObservation(%s) = %+v, %v; want the %s observation", tt.target, o, err, tt.want)
+    }
+  }
+
+  future, _ := time.Parse("2006-01-02T15:04", "2023-07-04T17:00")
+  if o, err := FindClosestObservation(observations, future); err == nil {
+    t.Errorf("FindClosestObservation(17:00) = %+v, want an error", o)
+  }
+  if o, err := FindClosestObservation(nil, future); err == nil {
+    t.Errorf("FindClosestObservation with no observations = %+v, want an error", o)
+  }
+}
+
+func TestDecadeDates(t *testing.T) {
+  tests := []struct {
+    today string
+    want  []string
+  }{
+    {"2026-10-14", []string{"1990-10-14", "2000-10-14", "2010-10-14", "2020-10-14"}},
+    {"2024-02-29", []string{"1990-02-28", "2000-02-29", "2010-02-28", "2020-02-29"}},
+    {"2030-06-01", []string{"2000-06-01", "2010-06-01", "2020-06-01"}},
+  }
+  for _, tt := range tests {
+    today, _ := time.Parse("2006-01-02", tt.today)
+    dates := DecadeDates(today, 4)
+    got := make([]string, len(dates))
+    for i, d := range dates {
+      got[i] = d.Format("2006-01-02")
+    }
+    if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+      t.Errorf("DecadeDates(%s) = %v, want %v", tt.today, got, tt.want)
+    }
+  }
+}
+
+func TestGroupByDecade(t *testing.T) {
+  day := func(year int, high, low string) HistoryDay {
+    return HistoryDay{Date: time.Date(year, 7, 4, 0, 0, 0, 0, time.UTC), Summary: Dailysummary{Maxtempi: high, Mintempi: low}}
+  }
+  days := []HistoryDay{
+    day(1991, "84", "62"), day(1995, "86", "64"), day(1999, "85", "63"),
+    day(2000, "87", "65"), day(2004, "89", "67"),
+    day(2010, "90", "68"), day(2015, "", ""), day(2019, "92", "70"),
+  }
+  decades := GroupByDecade(days)
+  if len(decades) != 3 {
+    t.Fatalf("GroupByDecade = %v, want 3 decades", decades)
+  }
+  tests := []struct {
+    decade          int
+    years           int
+    avgHigh, avgLow float64
+  }{
+    {1990, 3, 85, 63},
+    {2000, 2, 88, 66},
+    {2010, 2, 91, 69},
+  }
+  for _, tt := range tests {
+    d := decades[tt.decade]
+    if d.Years != tt.years || math.Abs(d.AvgHigh-tt.avgHigh) > 1e-9 || math.Abs(d.AvgLow-tt.avgLow) > 1e-9 {
+      t.Errorf("%ds = %+v, want %d years averaging %g/%g", tt.decade, d, tt.years, tt.avgHigh, tt.avgLow)
+    }
+  }
+}
+
+// serveHistory answers history requests (history_YYYYMMDD) with the
+// response for the date, or an empty one, until the test ends
+func serveHistory(t *testing.T, responses map[string]string) {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    for date, response := range responses {
+      if strings.Contains(r.URL.Path, "/history_"+date+"/") {
+        w.Write([]byte(response))
+        return
+      }
+    }
+    w.Write([]byte(`{"history": {"dailysummary": []}}`))
+  }))
+  url := apiURL
+  apiURL = srv.URL + "/api/"
+  t.Cleanup(func() {
+    apiURL = url
+    srv.Close()
+  })
+  saved := historyrate
+  t.Cleanup(func() { historyrate = saved })
+  historyrate = 60000
+}
+
+func TestMissingDataReport(t *testing.T) {
+  summary := `{"history": {"dailysummary": [{"maxtempi": "75", "mintempi": "55"}]}}`
+  serveHistory(t, map[string]string{
+    "20230301": summary,
+    "20230302": `{"history": {"dailysummary": [{"maxtempi": "-9999", "mintempi": "-9999"}]}}`,
+    "20230303": summary,
+    "20230305": summary,
+  })
+  start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+  days, err := FetchHistoryRange(start, start.AddDate(0, 0, 4), "KLNK")
+  if err != nil {
+    t.Fatal(err)
+  }
+  var buf bytes.Buffer
+  PrintMissingData(days, &buf)
+  want := "Missing data for: 2023-03-02, 2023-03-04 (2 of 5 days).\nTry these dates again with wu --history YYYYMMDD.\n"
+  if buf.String() != want {
+    t.Errorf("PrintMissingData = %q, want %q", buf.String(), want)
+  }
+  if report := MissingDataReport(map[string]bool{"2023-03-01": true}); report != "" {
+    t.Errorf("MissingDataReport with nothing missing = %q", report)
+  }
+}
+
+func TestFetchHistoryDaysErrors(t *testing.T) {
+  start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+  tests := []struct {
+    response string
+    code     ErrorCode
+  }{
+    {`{"response": {"error": {"type": "invalidkey", "description": "this key has exceeded its daily rate limit"}}}`, QuotaExceeded},
+    {`{"response": {"error": {"type": "querynotfound", "description": "No cities match your search query"}}}`, APIError},
+    {`<html>Service Unavailable</html>`, APIError},
+  }
+  for _, tt := range tests {
+    serveHistory(t, map[string]string{"20230302": tt.response})
+    _, err := FetchHistoryRange(start, start.AddDate(0, 0, 2), "KLNK")
+    if err == nil || codeOf(err) != tt.code || !strings.Contains(err.Error(), "2023-03-02") {
+      t.Errorf("FetchHistoryRange with %s: error %v (code %v), want %v for 2023-03-02", tt.response, err, codeOf(err), tt.code)
+    }
+  }
+
+  url := apiURL
+  t.Cleanup(func() { apiURL = url })
+  apiURL = "http://127.0.0.1:1/api/"
+  if _, err := FetchHistoryRange(start, start, "KLNK"); codeOf(err) != NetworkError {
+    t.Errorf("FetchHistoryRange with the API down: error %v, want a network error", err)
+  }
+}
+
+func TestAccumulateSnowfall(t *testing.T) {
+  start := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+  snow, total := AccumulateSnowfall(historyDays(start, snowfalls("1.5", "T", "2.0")))
+  wantTotals := []float64{1.5, 1.5 + traceSnowfall, 3.5 + traceSnowfall}
+  if len(snow) != len(wantTotals) {
+    t.Fatalf("got %d days, want %d", len(snow), len(wantTotals))
+  }
+  for i, want := range wantTotals {
+    if math.Abs(snow[i].Total-want) > 1e-9 || snow[i].Missing {
+      t.Errorf("day %d: %+v, want a total of %v", i, snow[i], want)
+    }
+  }
+  if snow[1].Total <= snow[0].Total {
+    t.Errorf("a trace left the total at %v, want it above %v", snow[1].Total, snow[0].Total)
+  }
+  if math.Abs(total-wantTotals[2]) > 1e-9 {
+    t.Errorf("season total = %v, want %v", total, wantTotals[2])
+  }
+}
+
+func TestAccumulateSnowfallMissing(t *testing.T) {
+  start := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+  tests := []struct {
+    s       string
+    missing bool
+  }{
+    {"", true},
+    {"-9999", true},
+    {"-9999.00", true},
+    {"M", true},
+    {"0.00", false},
+    {"0.4", false},
+    {"T", false},
+  }
+  for _, tt := range tests {
+    snow, total := AccumulateSnowfall(historyDays(start, snowfalls("1.0", tt.s)))
+    if snow[1].Missing != tt.missing {
+      t.Errorf("%q: Missing = %v, want %v", tt.s, snow[1].Missing, tt.missing)
+    }
+    if tt.missing && total != 1 {
+      t.Errorf("%q: total = %v, want it to add nothing", tt.s, total)
+    }
+  }
+}
+
+func TestPrintSnowfall(t *testing.T) {
+  start := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+  var buf bytes.Buffer
+  PrintSnowfall(historyDays(start, snowfalls("1.5", "T", "-9999.00", "0.00")), "KLNK", &buf)
+  want := `Snowfall for KLNK
+   Date         Snowfall (in)  Season total (in)
+   Dec 01 2023  1.5            1.5
+   Dec 02 2023  T              1.5
+   Dec 03 2023  no data        1.5
+   Dec 04 2023  0.0            1.5
+Season total: 1.5 in
+`
+  if buf.String() != want {
+    t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+  }
+}
+
+func TestFindRecordRain(t *testing.T) {
+  start := time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC)
+  days := historyDays(start, precips("0.00", "0.45", "T", "1.20", "-9999", "0.05", "2.34", "0.00", "0.80", "0.10"))
+  want := []RainRecord{
+    {"2023-07-16", 2.34, false},
+    {"2023-07-13", 1.20, false},
+    {"2023-07-18", 0.80, false},
+    {"2023-07-11", 0.45, false},
+    {"2023-07-19", 0.10, false},
+    {"2023-07-15", 0.05, false},
+    {"2023-07-12", 0, true},
+    {"2023-07-10", 0, false},
+    {"2023-07-17", 0, false},
+  }
+  got := FindRecordRain(days)
+  if len(got) != len(want) {
+    t.Fatalf("got %d records %+v, want %d (the missing day left out)", len(got), got, len(want))
+  }
+  for i := range want {
+    if got[i] != want[i] {
+      t.Errorf("record %d = %+v, want %+v", i, got[i], want[i])
+    }
+  }
+
+  if got := FindRecordRain(historyDays(start, precips("", "-9999.00", "M"))); len(got) != 0 {
+    t.Errorf("days with no data gave %+v, want none", got)
+  }
+}
+
+func TestPrintRecordRain(t *testing.T) {
+  start := time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC)
+  var buf bytes.Buffer
+  PrintRecordRain(historyDays(start, precips("0.00", "0.45", "T", "1.00", "-9999", "0.05", "2.34", "0.00", "0.80", "0.10")), "KLNK", &buf)
+  want := `Wettest day: 2023-07-16 with 2.34 inches
+The 5 wettest days at KLNK:
+   1. 2023-07-16: 2.34 inches
+   2. 2023-07-13: 1.00 inch
+   3. 2023-07-18: 0.80 inches
+   4. 2023-07-11: 0.45 inches
+   5. 2023-07-19: 0.10 inches
+`
+  if buf.String() != want {
+    t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+  }
+
+  buf.Reset()
+  PrintRecordRain(historyDays(start, precips("-9999")), "KLNK", &buf)
+  if want := "No precipitation data for KLNK in this range.\n"; buf.String() != want {
+    t.Errorf("got %q, want %q", buf.String(), want)
+  }
+}
+
+func TestStationUptime(t *testing.T) {
+  summary := `{"history": {"dailysummary": [{"maxtempi": "75", "mintempi": "55"}]}}`
+  responses := make(map[string]string)
+  start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+  for i := 0; i < 10; i++ {
+    responses[start.AddDate(0, 0, i).Format("20060102")] = summary
+  }
+  delete(responses, "20230303") // no summary at all
+  responses["20230306"] = `{"history": {"dailysummary": [{"maxtempi": "-9999", "mintempi": "-9999"}]}}`
+  responses["20230309"] = `{"history": {"dailysummary": [{"maxtempi": "-9999.00", "mintempi": "-9999.00"}]}}`
+  serveHistory(t, responses)
+
+  days, err := FetchHistoryRange(start, start.AddDate(0, 0, 9), "KLNK")
+  if err != nil {
+    t.Fatal(err)
+  }
+  reported, total, pct := StationUptime(days)
+  if reported != 7 || total != 10 || pct != 70 {
+    t.Errorf("StationUptime = %d, %d, %v, want 7, 10, 70", reported, total, pct)
+  }
+  var buf bytes.Buffer
+  PrintStationUptime(days, "KLNK", &buf)
+  want := "Station KLNK has reported data 7 out of the last 10 days (70%).\n" +
+    "⚠ Station reliability: 70% — consider using an alternate station.\n"
+  if buf.String() != want {
+    t.Errorf("PrintStationUptime = %q, want %q", buf.String(), want)
+  }
+
+  buf.Reset()
+  PrintStationUptime(days[:2], "KLNK", &buf)
+  if want := "Station KLNK has reported data 2 out of the last 2 days (100%).\n"; buf.String() != want {
+    t.Errorf("PrintStationUptime = %q, want %q", buf.String(), want)
+  }
+
+  if _, total, pct := StationUptime(nil); total != 0 || pct != 0 {
+    t.Errorf("StationUptime(nil) = %d, %v, want 0, 0", total, pct)
+  }
+}
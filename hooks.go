@@ -0,0 +1,90 @@
+/*
+* hooks.go
+*
+* This file is part of wu.  It contains functions related to
+* the --on-weather switch (running commands for the current
+* weather).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:02:47 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "os"
+  "os/exec"
+  "strings"
+)
+
+// WeatherHook is a command to run when the current weather matches
+// Condition
+type WeatherHook struct {
+  Condition string
+  Command   string
+}
+
+// weatherHooks collects the (repeatable) -on-weather switches
+type weatherHooks []WeatherHook
+
+func (h *weatherHooks) String() string {
+  pairs := make([]string, len(*h))
+  for i, hook := range *h {
+    pairs[i] = hook.Condition + ":" + hook.Command
+  }
+  return strings.Join(pairs, ", ")
+}
+
+// Set parses one CONDITION:COMMAND pair
+func (h *weatherHooks) Set(s string) error {
+  parts := strings.SplitN(s, ":", 2)
+  if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+    return fmt.Errorf("expected CONDITION:COMMAND (e.g. \"rain:lights on\")")
+  }
+  *h = append(*h, WeatherHook{strings.TrimSpace(parts[0]), parts[1]})
+  return nil
+}
+
+// EvalHooks returns the commands of the hooks whose condition appears
+// in weather (ignoring case), in order
+func EvalHooks(hooks []WeatherHook, weather string) []string {
+  commands := make([]string, 0)
+  for _, hook := range hooks {
+    if strings.Contains(strings.ToLower(weather), strings.ToLower(hook.Condition)) {
+      commands = append(commands, hook.Command)
+    }
+  }
+  return commands
+}
+
+// RunHooks runs the commands of the hooks matching weather with sh.
+// This is best-effort: a command that fails doesn't stop the others.
+func RunHooks(hooks []WeatherHook, weather string) {
+  for _, command := range EvalHooks(hooks, weather) {
+    cmd := exec.Command("sh", "-c", command)
+    cmd.Stdout = os.Stdout
+    cmd.Stderr = os.Stderr
+    if err := cmd.Run(); err != nil && verbose {
+      fmt.Fprintf(os.Stderr, "-on-weather command %q: %v\n", command, err)
+    }
+  }
+}
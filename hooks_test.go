@@ -0,0 +1,81 @@
+/*
+* hooks_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --on-weather (hooks.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:36 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "strings"
+  "testing"
+)
+
+func TestEvalHooks(t *testing.T) {
+  var hooks weatherHooks
+  for _, s := range []string{"rain:lights on", "Snow:plow start", "CLEAR:blinds up", "rain:echo wet"} {
+    if err := hooks.Set(s); err != nil {
+      t.Fatalf("Set(%q): %v", s, err)
+    }
+  }
+  tests := []struct {
+    weather string
+    want    []string
+  }{
+    {"Rain", []string{"lights on", "echo wet"}},
+    {"Light Freezing Rain", []string{"lights on", "echo wet"}},
+    {"Heavy Snow", []string{"plow start"}},
+    {"Clear", []string{"blinds up"}},
+    {"Overcast", []string{}},
+  }
+  for _, tt := range tests {
+    if got := EvalHooks(hooks, tt.weather); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+      t.Errorf("EvalHooks(%q) = %q, want %q", tt.weather, got, tt.want)
+    }
+  }
+}
+
+func TestWeatherHooksSet(t *testing.T) {
+  var hooks weatherHooks
+  if err := hooks.Set(" rain : curl -s http://hub/lights?on=1"); err != nil {
+    t.Fatal(err)
+  }
+  if hooks[0].Condition != "rain" || hooks[0].Command != " curl -s http://hub/lights?on=1" {
+    t.Errorf("Set = %+v", hooks[0])
+  }
+  for _, s := range []string{"rain", "rain:", ":lights on", "  :  "} {
+    if err := hooks.Set(s); err == nil {
+      t.Errorf("Set(%q): no error", s)
+    }
+  }
+}
+
+func TestRunHooks(t *testing.T) {
+  hooks := []WeatherHook{{"rain", "echo raining"}, {"snow", "echo snowing"}, {"rain", "exit 3"}, {"rain", "echo still here"}}
+  out := captureStdout(t, func() { RunHooks(hooks, "Rain") })
+  if out != "raining\nstill here\n" {
+    t.Errorf("RunHooks(Rain) printed %q", out)
+  }
+}
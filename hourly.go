@@ -0,0 +1,226 @@
+/*
+* hourly.go
+*
+* This file is part of wu.  It contains functions related to
+* the --hourly, --hourly-next, --forecast-uv-peak, and
+* --hourly-rain-window switches (hourly forecast).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:25:50 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+)
+
+type HourlyPeriod struct {
+  FCTTIME   FCTTIME        `json:"fcttime"`
+  Temp      Hourly_measure `json:"temp"`
+  Condition string         `json:"condition"`
+  Icon      string         `json:"icon"`
+  Pop       Value          `json:"pop"`
+  Wspd      Hourly_measure `json:"wspd"`
+  Qpf       Hourly_measure `json:"qpf"`
+  UVI       Value          `json:"uvi"`
+}
+
+type FCTTIME struct {
+  Hour                string `json:"hour"`
+  Mday                string `json:"mday"`
+  Mon                 string `json:"mon"`
+  Year                string `json:"year"`
+  Civil               string `json:"civil"`
+  Pretty              string `json:"pretty"`
+  Weekday_name_abbrev string `json:"weekday_name_abbrev"`
+  Epoch               string `json:"epoch"`
+}
+
+type Hourly_measure struct {
+  English Value `json:"english"`
+  Metric  Value `json:"metric"`
+}
+
+// Time returns the start of the hourly period
+func (h *HourlyPeriod) Time() time.Time {
+  epoch, _ := strconv.ParseInt(h.FCTTIME.Epoch, 10, 64)
+  return time.Unix(epoch, 0)
+}
+
+// FilterNextHours returns the first n hourly periods that start after now
+func FilterNextHours(hourly []HourlyPeriod, n int, now time.Time) []HourlyPeriod {
+  next := make([]HourlyPeriod, 0)
+  for _, h := range hourly {
+    if len(next) < n && h.Time().After(now) {
+      next = append(next, h)
+    }
+  }
+  return next
+}
+
+// FilterHourly is FilterForecast for the hourly forecast, matching
+// each hour's condition or icon
+func FilterHourly(hourly []HourlyPeriod, pattern string, invert bool) ([]HourlyPeriod, error) {
+  keep, err := conditionFilter(pattern, invert)
+  if err != nil {
+    return nil, err
+  }
+  filtered := make([]HourlyPeriod, 0)
+  for _, h := range hourly {
+    if keep(h.Condition, h.Icon) {
+      filtered = append(filtered, h)
+    }
+  }
+  return filtered, nil
+}
+
+// PrintHourly prints the hourly forecast for a given station to standard out
+func PrintHourly(obs *Conditions, stationId string, units *Units) {
+  if len(obs.Hourly_forecast) == 0 {
+    fmt.Println("No hourly forecast available")
+    return
+  }
+  fmt.Printf("Hourly forecast for %s\n", stationId)
+  for _, h := range obs.Hourly_forecast {
+    fmt.Printf("   %s %s: %s, %s, %s%% chance of precipitation\n",
+      h.FCTTIME.Weekday_name_abbrev, h.FCTTIME.Civil,
+      units.Temp(string(h.Temp.English), string(h.Temp.Metric)), h.Condition, h.Pop)
+  }
+}
+
+// UVLabel returns the WHO category for a UV index (e.g. "Very High")
+func UVLabel(uv float64) string {
+  switch {
+  case uv >= 11:
+    return "Extreme"
+  case uv >= 8:
+    return "Very High"
+  case uv >= 6:
+    return "High"
+  case uv >= 3:
+    return "Moderate"
+  }
+  return "Low"
+}
+
+// FindPeakUV returns the highest UV index of the next daylight period
+// (the first run of hours with a UV index above zero) within the next
+// 24 hours, and the time it is expected (e.g. "1 PM", or "1 PM
+// tomorrow").  If the sun won't be up in that time, it is the highest
+// of the 24 hours.  The time is "" if no period has a UV index.
+func FindPeakUV(hours []HourlyPeriod) (maxUV float64, peakTime string) {
+  const window = 24
+
+  if len(hours) > window {
+    hours = hours[:window]
+  }
+  daylight := false
+  for _, h := range hours {
+    uv, ok := h.UVI.Float()
+    if !ok {
+      continue
+    }
+    if uv <= 0 && daylight {
+      break
+    }
+    daylight = daylight || uv > 0
+    if peakTime == "" || uv > maxUV {
+      maxUV, peakTime = uv, strings.Replace(h.FCTTIME.Civil, ":00", "", 1)
+      if h.FCTTIME.Mday != hours[0].FCTTIME.Mday {
+        peakTime += " tomorrow"
+      }
+    }
+  }
+  return maxUV, peakTime
+}
+
+// PrintPeakUV prints the day's peak UV index, falling back to the
+// current UV index when the hourly forecast has none
+func PrintPeakUV(obs *Conditions, w io.Writer) {
+  uv, at := FindPeakUV(obs.Hourly_forecast)
+  if at != "" {
+    fmt.Fprintf(w, "Peak UV: %.0f (%s) expected at %s.\n", uv, UVLabel(uv), at)
+  } else if uv, ok := obs.Current_observation.UV.Float(); ok {
+    fmt.Fprintf(w, "Current UV: %.0f (%s); no hourly UV forecast is available.\n", uv, UVLabel(uv))
+  } else {
+    fmt.Fprintln(w, "No UV index available.")
+  }
+}
+
+// TimeWindow is a stretch of time from Start up to End
+type TimeWindow struct {
+  Start time.Time `json:"start"`
+  End   time.Time `json:"end"`
+}
+
+// rainWindowHours is how far ahead -hourly-rain-window looks
+const rainWindowHours = 12
+
+var rainConditionPattern = regexp.MustCompile(`(?i)rain|shower|drizzle|thunder|storm`)
+
+// FindRainWindows returns the stretches of hours whose chance of
+// precipitation is above popThreshold (or whose condition is some kind
+// of rain), merging back-to-back hours into one window
+func FindRainWindows(hours []HourlyPeriod, popThreshold int) []TimeWindow {
+  windows := make([]TimeWindow, 0)
+  for _, h := range hours {
+    pop, _ := strconv.Atoi(string(h.Pop))
+    if pop <= popThreshold && !rainConditionPattern.MatchString(h.Condition) {
+      continue
+    }
+    start := h.Time()
+    if n := len(windows); n > 0 && windows[n-1].End.Equal(start) {
+      windows[n-1].End = start.Add(time.Hour)
+    } else {
+      windows = append(windows, TimeWindow{start, start.Add(time.Hour)})
+    }
+  }
+  return windows
+}
+
+// PrintRainWindows prints the windows of rain expected in the next 12
+// hours, in the station's time zone
+func PrintRainWindows(obs *Conditions, w io.Writer) {
+  hours := obs.Hourly_forecast
+  if len(hours) > rainWindowHours {
+    hours = hours[:rainWindowHours]
+  }
+  windows := FindRainWindows(hours, 50)
+  if len(windows) == 0 {
+    fmt.Fprintln(w, "No rain expected in the next 12 hours.")
+    return
+  }
+  loc := time.Local
+  if now, ok := LocalTime(&obs.Current_observation); ok {
+    loc = now.Location()
+  }
+  spans := make([]string, len(windows))
+  for i, win := range windows {
+    spans[i] = win.Start.In(loc).Format("3:04 PM") + " – " + win.End.In(loc).Format("3:04 PM")
+  }
+  fmt.Fprintf(w, "Rain windows: %s.\n", strings.Join(spans, ", "))
+}
@@ -0,0 +1,225 @@
+/*
+* hourly_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the hourly forecast (hourly.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:44 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "strconv"
+  "strings"
+  "testing"
+  "time"
+)
+
+// hoursFrom returns n hourly periods starting at start
+func hoursFrom(start time.Time, n int) []HourlyPeriod {
+  hours := make([]HourlyPeriod, n)
+  for i := range hours {
+    t := start.Add(time.Duration(i) * time.Hour)
+    hours[i].FCTTIME = FCTTIME{Hour: strconv.Itoa(t.Hour()), Epoch: strconv.FormatInt(t.Unix(), 10)}
+  }
+  return hours
+}
+
+func TestFilterNextHours(t *testing.T) {
+  midnight := time.Date(2023, 7, 1, 0, 0, 0, 0, time.FixedZone("CDT", -5*3600))
+  noon := midnight.Add(12 * time.Hour)
+  day := hoursFrom(midnight, 36)
+  tests := []struct {
+    n     int
+    first string
+    count int
+  }{
+    {3, "13", 3},
+    {6, "13", 6},
+    {1, "13", 1},
+    {40, "13", 23}, // only 23 hours remain after noon
+  }
+  for _, tt := range tests {
+    got := FilterNextHours(day, tt.n, noon)
+    if len(got) != tt.count {
+      t.Errorf("FilterNextHours(%d) returned %d hours, want %d", tt.n, len(got), tt.count)
+      continue
+    }
+    if got[0].FCTTIME.Hour != tt.first {
+      t.Errorf("FilterNextHours(%d) starts at hour %s, want %s", tt.n, got[0].FCTTIME.Hour, tt.first)
+    }
+    for _, h := range got {
+      if !h.Time().After(noon) {
+        t.Errorf("FilterNextHours(%d) kept hour %s, which isn't after noon", tt.n, h.FCTTIME.Hour)
+      }
+    }
+  }
+  if got := FilterNextHours(day, 3, midnight.Add(48*time.Hour)); len(got) != 0 {
+    t.Errorf("FilterNextHours after the forecast returned %d hours", len(got))
+  }
+}
+
+// uvHours returns hourly periods starting at start with the given UV
+// indexes
+func uvHours(start time.Time, uvs []string) []HourlyPeriod {
+  hours := hoursFrom(start, len(uvs))
+  for i := range hours {
+    t := start.Add(time.Duration(i) * time.Hour)
+    hours[i].FCTTIME.Civil = t.Format("3:04 PM")
+    hours[i].FCTTIME.Mday = strconv.Itoa(t.Day())
+    hours[i].UVI = Value(uvs[i])
+  }
+  return hours
+}
+
+func TestFindPeakUV(t *testing.T) {
+  morning := time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC)
+  evening := time.Date(2023, 7, 1, 20, 0, 0, 0, time.UTC)
+  tests := []struct {
+    name  string
+    hours []HourlyPeriod
+    uv    float64
+    at    string
+  }{
+    {"daytime", uvHours(morning, strings.Fields("1 2 4 6 7 9 8 6 4 2 1 0")), 9, "1 PM"},
+    // from 8 PM, the next daylight is tomorrow's
+    {"evening", uvHours(evening, strings.Fields("0 0 0 0 0 0 0 0 0 0 0 1 2 4 6 7 8 7 5 3 2 1 0 0 0 0 11")), 8, "12 PM tomorrow"},
+    // today's daylight ends before tomorrow's (higher) morning
+    {"afternoon", uvHours(morning.Add(6*time.Hour), strings.Fields("6 5 3 2 1 0 0 0 0 0 0 0 0 0 0 0 0 0 2 9")), 6, "2 PM"},
+    {"night", uvHours(evening, strings.Fields("0 0 0 0 0 0")), 0, "8 PM"},
+    {"missing", uvHours(morning, []string{"", "", "-9999x"}), 0, ""},
+  }
+  for _, tt := range tests {
+    uv, at := FindPeakUV(tt.hours)
+    if uv != tt.uv || at != tt.at {
+      t.Errorf("%s: FindPeakUV = %g at %q, want %g at %q", tt.name, uv, at, tt.uv, tt.at)
+    }
+  }
+}
+
+func TestFindRainWindows(t *testing.T) {
+  start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.FixedZone("CDT", -5*3600))
+  hours := hoursFrom(start, 12)
+  for i := range hours {
+    hours[i].Pop = "10"
+    hours[i].Condition = "Partly Cloudy"
+  }
+  for _, i := range []int{2, 3, 4} {
+    hours[i].Pop = "70"
+  }
+  hours[9].Pop = "20"
+  hours[9].Condition = "Light Rain" // rainy by condition, not by chance
+  hours[10].Pop = "60"
+  hours[11].Pop = "50" // at the threshold, not above it
+
+  got := FindRainWindows(hours, 50)
+  want := []TimeWindow{
+    {start.Add(2 * time.Hour), start.Add(5 * time.Hour)},
+    {start.Add(9 * time.Hour), start.Add(11 * time.Hour)},
+  }
+  if len(got) != len(want) {
+    t.Fatalf("got %d windows %v, want %d", len(got), got, len(want))
+  }
+  for i := range want {
+    if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+      t.Errorf("window %d = %v – %v, want %v – %v", i, got[i].Start, got[i].End, want[i].Start, want[i].End)
+    }
+  }
+
+  if got := FindRainWindows(hoursFrom(start, 12), 50); len(got) != 0 {
+    t.Errorf("dry hours gave windows %v, want none", got)
+  }
+}
+
+func TestPrintRainWindows(t *testing.T) {
+  start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.Local)
+  var obs Conditions
+  obs.Hourly_forecast = hoursFrom(start, 12)
+  obs.Hourly_forecast[2].Pop = "70"
+  obs.Hourly_forecast[3].Pop = "70"
+  var buf bytes.Buffer
+  PrintRainWindows(&obs, &buf)
+  if want := "Rain windows: 2:00 PM – 4:00 PM.\n"; buf.String() != want {
+    t.Errorf("got %q, want %q", buf.String(), want)
+  }
+
+  obs.Hourly_forecast = hoursFrom(start, 12)
+  buf.Reset()
+  PrintRainWindows(&obs, &buf)
+  if want := "No rain expected in the next 12 hours.\n"; buf.String() != want {
+    t.Errorf("got %q, want %q", buf.String(), want)
+  }
+}
+
+func TestFilterHourly(t *testing.T) {
+  hours := hoursFrom(time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC), 4)
+  for i, c := range []struct{ condition, icon string }{
+    {"Clear", "clear"}, {"Rain", "rain"}, {"Thunderstorm", "tstorms"}, {"Partly Cloudy", "partlycloudy"},
+  } {
+    hours[i].Condition, hours[i].Icon = c.condition, c.icon
+  }
+  tests := []struct {
+    pattern string
+    invert  bool
+    want    []string
+  }{
+    {"rain", false, []string{"13"}},
+    {"rain|TSTORMS", false, []string{"13", "14"}},
+    {"rain|tstorms", true, []string{"12", "15"}},
+    {"snow", false, []string{}},
+  }
+  for _, tt := range tests {
+    got, err := FilterHourly(hours, tt.pattern, tt.invert)
+    if err != nil {
+      t.Fatalf("FilterHourly(%q): %v", tt.pattern, err)
+    }
+    if len(got) != len(tt.want) {
+      t.Errorf("FilterHourly(%q, %v) kept %d hours, want %d", tt.pattern, tt.invert, len(got), len(tt.want))
+      continue
+    }
+    for i, hour := range tt.want {
+      if got[i].FCTTIME.Hour != hour {
+        t.Errorf("FilterHourly(%q, %v)[%d] is hour %s, want %s", tt.pattern, tt.invert, i, got[i].FCTTIME.Hour, hour)
+      }
+    }
+  }
+  if _, err := FilterHourly(hours, "rain(", false); err == nil {
+    t.Error("FilterHourly accepted an invalid pattern")
+  }
+}
+
+func TestHourlyFilterCondition(t *testing.T) {
+  serveAPI(t, `{"hourly_forecast": [
+    {"fcttime": {"civil": "1:00 PM", "weekday_name_abbrev": "Sat", "epoch": "1688230800"}, "condition": "Clear", "icon": "clear"},
+    {"fcttime": {"civil": "2:00 PM", "weekday_name_abbrev": "Sat", "epoch": "1688234400"}, "condition": "Rain", "icon": "rain"}]}`)
+  defer func(f string, i bool) { filtercond, invertfilter = f, i }(filtercond, invertfilter)
+  filtercond = "rain"
+  for _, invert := range []bool{false, true} {
+    invertfilter = invert
+    out := captureStdout(t, func() { weather([]string{"hourly"}, "KLNK") })
+    if strings.Contains(out, "Rain") == invert || strings.Contains(out, "Clear") != invert {
+      t.Errorf("-hourly -filter-condition rain (invert %v) printed:\n%s", invert, out)
+    }
+  }
+}
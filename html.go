@@ -0,0 +1,101 @@
+/*
+* html.go
+*
+* This file is part of wu.  It contains functions related to
+* the --format html switch (a self-contained HTML page).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:02:33 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "embed"
+  "html/template"
+  "io"
+  "strings"
+)
+
+//go:embed templates/report.html
+var templates embed.FS
+
+type htmlPeriod struct {
+  Title string
+  Text  string
+}
+
+type htmlReport struct {
+  Station      string
+  Theme        string
+  Version      string
+  Alerts       []Alerts
+  Conditions   *Current
+  Temperature  string
+  TempClass    string
+  ForecastDate string
+  Forecast     []htmlPeriod
+}
+
+// tempClass picks the color used for a temperature (F)
+func tempClass(tempF float64) string {
+  switch {
+  case tempF < 40:
+    return "cold"
+  case tempF < 70:
+    return "mild"
+  case tempF < 90:
+    return "warm"
+  }
+  return "hot"
+}
+
+// PrintHTML renders the requested operations as a standalone HTML page
+func PrintHTML(obs *Conditions, operations []string, stationId, theme string, units *Units, w io.Writer) error {
+  t, err := template.ParseFS(templates, "templates/report.html")
+  if err != nil {
+    return err
+  }
+  report := htmlReport{Station: stationId, Theme: theme, Version: GetVersion()}
+  for _, operation := range operations {
+    switch strings.Split(operation, "_")[0] {
+    case "alerts":
+      report.Alerts = obs.Alerts
+    case "conditions":
+      current := obs.Current_observation
+      report.Conditions = &current
+      report.Temperature = current.Temperature_string
+      if current.Temp_f != "" {
+        report.Temperature = units.Temp(string(current.Temp_f), string(current.Temp_c))
+      }
+      if f, ok := current.Temp_f.Float(); ok {
+        report.TempClass = tempClass(f)
+      }
+    case "forecast", "forecast10day":
+      report.ForecastDate = obs.Forecast.Txt_forecast.Date
+      report.Forecast = report.Forecast[:0]
+      for _, f := range obs.Forecast.Txt_forecast.Forecastday {
+        report.Forecast = append(report.Forecast, htmlPeriod{f.Title, f.Detail(units)})
+      }
+    }
+  }
+  return t.Execute(w, report)
+}
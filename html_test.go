@@ -0,0 +1,120 @@
+/*
+* html_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the HTML report (html.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:12:38 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/xml"
+  "io"
+  "strings"
+  "testing"
+)
+
+// htmlText returns the text of every element in page whose tag is tag
+// and whose class attribute contains class ("" for any)
+func htmlText(t *testing.T, page, tag, class string) []string {
+  dec := xml.NewDecoder(strings.NewReader(page))
+  dec.Strict = false
+  dec.AutoClose = xml.HTMLAutoClose
+  dec.Entity = xml.HTMLEntity
+  var found []string
+  depth := 0 // nesting within a matching element
+  var text strings.Builder
+  for {
+    tok, err := dec.Token()
+    if err == io.EOF {
+      return found
+    }
+    if err != nil {
+      t.Fatalf("parsing the page: %v", err)
+    }
+    switch tok := tok.(type) {
+    case xml.StartElement:
+      if depth > 0 {
+        depth++
+        continue
+      }
+      if tok.Name.Local != tag {
+        continue
+      }
+      for _, a := range tok.Attr {
+        if a.Name.Local == "class" && strings.Contains(" "+a.Value+" ", " "+class+" ") {
+          depth = 1
+        }
+      }
+      if class == "" {
+        depth = 1
+      }
+      text.Reset()
+    case xml.EndElement:
+      if depth > 0 {
+        if depth--; depth == 0 {
+          found = append(found, strings.TrimSpace(text.String()))
+        }
+      }
+    case xml.CharData:
+      if depth > 0 {
+        text.Write(tok)
+      }
+    }
+  }
+}
+
+func TestPrintHTML(t *testing.T) {
+  obs := &Conditions{
+    Alerts: []Alerts{{Description: "Winter Storm Warning", Message: "Snow & ice <expected>"}},
+    Current_observation: Current{Station_id: "KLNK", Weather: "Clear", Temp_f: "94.1", Temp_c: "34.5"},
+  }
+  obs.Forecast.Txt_forecast.Forecastday = []Forecastday{{Title: "Monday", Fcttext: "Sunny."}, {Title: "Monday Night", Fcttext: "Clear."}}
+  for _, theme := range []string{"light", "dark"} {
+    var buf bytes.Buffer
+    if err := PrintHTML(obs, []string{"alerts", "conditions", "forecast"}, "KLNK", theme, &Units{}, &buf); err != nil {
+      t.Fatal(err)
+    }
+    page := buf.String()
+    if got := htmlText(t, page, "title", ""); len(got) != 1 || got[0] != "Weather for KLNK" {
+      t.Errorf("%s: title = %q", theme, got)
+    }
+    if got := htmlText(t, page, "div", "temp"); len(got) != 1 || got[0] != "94.1 F (34.5 C)" {
+      t.Errorf("%s: temperature = %q", theme, got)
+    }
+    if got := htmlText(t, page, "div", "hot"); len(got) != 1 {
+      t.Errorf("%s: 94 F isn't colored hot", theme)
+    }
+    if got := htmlText(t, page, "div", "alert"); len(got) != 1 || !strings.Contains(got[0], "Snow & ice <expected>") {
+      t.Errorf("%s: alert banner = %q", theme, got)
+    }
+    if got := htmlText(t, page, "div", "card"); len(got) != 2 {
+      t.Errorf("%s: %d forecast cards, want 2", theme, len(got))
+    }
+    if got := htmlText(t, page, "body", theme); len(got) != 1 {
+      t.Errorf("body isn't in the %s theme", theme)
+    }
+  }
+}
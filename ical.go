@@ -0,0 +1,159 @@
+/*
+* ical.go
+*
+* This file is part of wu.  It contains functions related to
+* the --history-export-ical switch (history as iCalendar events).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:12:48 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "os"
+  "strings"
+  "time"
+)
+
+// icalWriter accumulates all-day events for an RFC 5545 calendar
+type icalWriter struct {
+  events []icalEvent
+  stamp  time.Time
+}
+
+type icalEvent struct {
+  date        time.Time
+  title       string
+  description string
+}
+
+// AddDayEvent adds an all-day event on date
+func (c *icalWriter) AddDayEvent(date time.Time, title, description string) {
+  c.events = append(c.events, icalEvent{date, title, description})
+}
+
+// icalEscape escapes the characters RFC 5545 reserves in text values
+var icalEscape = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icalFold splits a content line into lines of at most 75 octets, as
+// RFC 5545 requires, without breaking a UTF-8 sequence
+func icalFold(line string) string {
+  var b strings.Builder
+  n := 0
+  for _, r := range line {
+    size := len(string(r))
+    if n+size > 75 {
+      b.WriteString("\r\n ")
+      n = 1
+    }
+    b.WriteRune(r)
+    n += size
+  }
+  b.WriteString("\r\n")
+  return b.String()
+}
+
+// WriteTo writes the calendar to w
+func (c *icalWriter) WriteTo(w io.Writer) (int64, error) {
+  stamp := c.stamp
+  if stamp.IsZero() {
+    stamp = time.Now()
+  }
+  lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//wu//wu " + GetVersion() + "//EN", "CALSCALE:GREGORIAN"}
+  for _, e := range c.events {
+    lines = append(lines,
+      "BEGIN:VEVENT",
+      "UID:"+e.date.Format("20060102")+"-weather@wu",
+      "DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"),
+      "DTSTART;VALUE=DATE:"+e.date.Format("20060102"),
+      "DTEND;VALUE=DATE:"+e.date.AddDate(0, 0, 1).Format("20060102"),
+      "SUMMARY:"+icalEscape.Replace(e.title),
+      "DESCRIPTION:"+icalEscape.Replace(e.description),
+      "TRANSP:TRANSPARENT",
+      "END:VEVENT")
+  }
+  lines = append(lines, "END:VCALENDAR")
+  var total int64
+  for _, line := range lines {
+    n, err := io.WriteString(w, icalFold(line))
+    total += int64(n)
+    if err != nil {
+      return total, err
+    }
+  }
+  return total, nil
+}
+
+// historyEvents describes what happened on a day (rain, snow, fog),
+// or "" if nothing did
+func historyEvents(s *Dailysummary) string {
+  events := make([]string, 0)
+  for _, e := range []struct{ flag, name string }{
+    {s.Rain, "Rain"}, {s.Snow, "Snow"}, {s.Fog, "Fog"}, {s.Hail, "Hail"}, {s.Thunder, "Thunder"}, {s.Tornado, "Tornado"},
+  } {
+    if e.flag == "1" {
+      events = append(events, e.name)
+    }
+  }
+  return strings.Join(events, ", ")
+}
+
+// ExportHistoryICal writes one event per day of days (skipping days
+// without data) to the iCalendar file at path
+func ExportHistoryICal(days []HistoryDay, stationId string, units *Units, path string) error {
+  var cal icalWriter
+  for _, day := range days {
+    s := day.Summary
+    if s.Maxtempi == "" {
+      continue
+    }
+    title := fmt.Sprintf("Weather: High %s°F / Low %s°F", s.Maxtempi, s.Mintempi)
+    if events := historyEvents(&s); events != "" {
+      title += ", " + events
+    }
+    description := strings.Join([]string{
+      "Weather summary for " + stationId,
+      "Max temperature: " + units.Temp(s.Maxtempi, s.Maxtempm),
+      "Min temperature: " + units.Temp(s.Mintempi, s.Mintempm),
+      "Mean temperature: " + units.Temp(s.Meantempi, s.Meantempm),
+      "Precipitation: " + units.Precip(s.Precipi, s.Precipm),
+    }, "\n")
+    if s.Humidity != "" {
+      description += "\nHumidity: " + s.Humidity + "%"
+    }
+    if s.Meanwindspdi != "" {
+      description += "\nMean wind speed: " + s.Meanwindspdi + " mph"
+    }
+    cal.AddDayEvent(day.Date, title, description)
+  }
+  f, err := os.Create(path)
+  if err != nil {
+    return err
+  }
+  if _, err := cal.WriteTo(f); err != nil {
+    f.Close()
+    return err
+  }
+  return f.Close()
+}
@@ -0,0 +1,138 @@
+/*
+* ical_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for -history-export-ical (ical.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:12:43 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "io/ioutil"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+)
+
+// parseICal unfolds an iCalendar file into its content lines, failing
+// the test if a line isn't CRLF-terminated or is longer than 75 octets
+func parseICal(t *testing.T, s string) []string {
+  if !strings.HasSuffix(s, "\r\n") {
+    t.Fatalf("calendar doesn't end in CRLF: %q", s)
+  }
+  var lines []string
+  for _, raw := range strings.Split(strings.TrimSuffix(s, "\r\n"), "\r\n") {
+    if len(raw) > 75 {
+      t.Errorf("line is %d octets: %q", len(raw), raw)
+    }
+    if strings.HasPrefix(raw, " ") && len(lines) > 0 {
+      lines[len(lines)-1] += raw[1:]
+      continue
+    }
+    lines = append(lines, raw)
+  }
+  return lines
+}
+
+// icalProperties returns the value of each property (by name, without
+// parameters) of the lines, in order
+func icalProperties(lines []string) map[string][]string {
+  props := make(map[string][]string)
+  for _, line := range lines {
+    i := strings.Index(line, ":")
+    if i < 0 {
+      continue
+    }
+    name := strings.SplitN(line[:i], ";", 2)[0]
+    props[name] = append(props[name], line[i+1:])
+  }
+  return props
+}
+
+func TestICalWriter(t *testing.T) {
+  cal := icalWriter{stamp: time.Date(2023, 8, 20, 12, 0, 0, 0, time.UTC)}
+  long := strings.Repeat("Partly cloudy, then clearing; ", 5)
+  for day := 15; day <= 17; day++ {
+    cal.AddDayEvent(time.Date(2023, 8, day, 0, 0, 0, 0, time.UTC), "Weather: High 85°F / Low 62°F, Rain", long+"\nLine two")
+  }
+  var b strings.Builder
+  if _, err := cal.WriteTo(&b); err != nil {
+    t.Fatal(err)
+  }
+  lines := parseICal(t, b.String())
+  if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+    t.Errorf("calendar isn't wrapped in VCALENDAR: %q ... %q", lines[0], lines[len(lines)-1])
+  }
+  props := icalProperties(lines)
+  if len(props["VERSION"]) != 1 || props["VERSION"][0] != "2.0" || len(props["PRODID"]) != 1 {
+    t.Errorf("VERSION = %q, PRODID = %q", props["VERSION"], props["PRODID"])
+  }
+  begins, ends := 0, 0
+  for _, line := range lines {
+    switch line {
+    case "BEGIN:VEVENT":
+      begins++
+    case "END:VEVENT":
+      ends++
+    }
+  }
+  if begins != 3 || ends != 3 {
+    t.Errorf("%d BEGIN:VEVENT and %d END:VEVENT, want 3 of each", begins, ends)
+  }
+  if got := strings.Join(props["DTSTART"], ","); got != "20230815,20230816,20230817" {
+    t.Errorf("DTSTART = %s", got)
+  }
+  if got := props["DTEND"][0]; got != "20230816" {
+    t.Errorf("first DTEND = %s, want the next day", got)
+  }
+  if props["DTSTAMP"][0] != "20230820T120000Z" {
+    t.Errorf("DTSTAMP = %s", props["DTSTAMP"][0])
+  }
+  if got := props["SUMMARY"][0]; got != `Weather: High 85°F / Low 62°F\, Rain` {
+    t.Errorf("SUMMARY = %q", got)
+  }
+  if got := props["DESCRIPTION"][0]; !strings.HasSuffix(got, `clearing\; \nLine two`) {
+    t.Errorf("DESCRIPTION isn't escaped and unfolded intact: %q", got)
+  }
+}
+
+func TestExportHistoryICal(t *testing.T) {
+  days := historyDays(time.Date(2013, 9, 1, 0, 0, 0, 0, time.UTC), maxTemps("88", "", "79"), precips("0.10", "", "0.00"))
+  path := filepath.Join(t.TempDir(), "weather.ics")
+  if err := ExportHistoryICal(days, "KLNK", &Units{Temperature: "f", Precipitation: "in"}, path); err != nil {
+    t.Fatal(err)
+  }
+  b, err := ioutil.ReadFile(path)
+  if err != nil {
+    t.Fatal(err)
+  }
+  props := icalProperties(parseICal(t, string(b)))
+  if got := strings.Join(props["DTSTART"], ","); got != "20130901,20130903" {
+    t.Errorf("events on %s, want the two days with data", got)
+  }
+  if !strings.HasPrefix(props["SUMMARY"][0], "Weather: High 88°F") {
+    t.Errorf("SUMMARY = %q", props["SUMMARY"][0])
+  }
+}
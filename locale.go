@@ -0,0 +1,132 @@
+/*
+* locale.go
+*
+* This file is part of wu.  It contains functions related to
+* the --locale switch (locale-aware numbers and dates).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:12:48 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Languages that write 3,5 rather than 3.5
+var commaDecimalLanguages = map[string]bool{
+  "cs": true, "da": true, "de": true, "es": true, "fi": true, "fr": true,
+  "it": true, "nb": true, "nl": true, "pl": true, "pt": true, "ru": true,
+  "sv": true, "tr": true,
+}
+
+// Languages that write 3.5, along with English
+var pointDecimalLanguages = map[string]bool{
+  "en": true, "ga": true, "he": true, "hi": true, "ja": true, "ko": true,
+  "ms": true, "th": true, "zh": true,
+}
+
+// Date layouts by locale, and then by language
+var dateLayouts = map[string]string{
+  "en_US": "January 2, 2006",
+  "en_GB": "2 January 2006",
+  "en":    "January 2, 2006",
+  "de":    "2.1.2006",
+  "fr":    "02/01/2006",
+  "es":    "02/01/2006",
+  "it":    "02/01/2006",
+  "pt":    "02/01/2006",
+  "nl":    "2-1-2006",
+  "sv":    "2006-01-02",
+  "ja":    "2006/01/02",
+  "zh":    "2006/01/02",
+}
+
+// Layouts used by the API's "pretty" dates
+var prettyLayouts = []string{"January 2, 2006", "3:04 PM MST on January 2, 2006"}
+
+var decimalPattern = regexp.MustCompile(`(\d)\.(\d)`)
+
+// A region subtag is two letters (DE) or three digits (419)
+var regionPattern = regexp.MustCompile(`^([A-Za-z]{2}|[0-9]{3})$`)
+
+// Localizer formats numbers and dates for a locale such as "de_DE" or
+// "fr-CA".  A nil Localizer leaves everything as the API reports it.
+type Localizer struct {
+  Tag        string
+  decimal    string
+  dateLayout string
+}
+
+// NewLocalizer returns a Localizer for a BCP 47 (or POSIX-style) tag
+func NewLocalizer(tag string) (*Localizer, error) {
+  norm := strings.Replace(tag, "-", "_", -1)
+  parts := strings.Split(norm, "_")
+  lang := strings.ToLower(parts[0])
+  if !commaDecimalLanguages[lang] && !pointDecimalLanguages[lang] {
+    return nil, fmt.Errorf("unrecognized locale %q", tag)
+  }
+  if len(parts) > 2 || len(parts) == 2 && !regionPattern.MatchString(parts[1]) {
+    return nil, fmt.Errorf("unrecognized locale %q", tag)
+  }
+  l := &Localizer{Tag: tag, decimal: ".", dateLayout: "January 2, 2006"}
+  if commaDecimalLanguages[lang] {
+    l.decimal = ","
+  }
+  if layout, ok := dateLayouts[norm]; ok {
+    l.dateLayout = layout
+  } else if layout, ok := dateLayouts[lang]; ok {
+    l.dateLayout = layout
+  }
+  return l, nil
+}
+
+// FormatFloat formats f with the locale's decimal separator
+func (l *Localizer) FormatFloat(f float64) string {
+  return l.FormatNumbers(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// FormatNumbers rewrites every decimal number in s with the locale's
+// decimal separator
+func (l *Localizer) FormatNumbers(s string) string {
+  if l == nil || l.decimal == "." {
+    return s
+  }
+  return decimalPattern.ReplaceAllString(s, "${1}"+l.decimal+"${2}")
+}
+
+// FormatDate reformats one of the API's "pretty" dates for the locale
+func (l *Localizer) FormatDate(pretty string) string {
+  if l == nil {
+    return pretty
+  }
+  for _, layout := range prettyLayouts {
+    if t, err := time.Parse(layout, pretty); err == nil {
+      return t.Format(l.dateLayout)
+    }
+  }
+  return pretty
+}
@@ -0,0 +1,94 @@
+/*
+* locale_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for locale-aware formatting (locale.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:48 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "testing"
+)
+
+func TestNewLocalizer(t *testing.T) {
+  tests := []struct {
+    tag     string
+    decimal string
+    ok      bool
+  }{
+    {"de_DE", ",", true},
+    {"de-AT", ",", true},
+    {"de", ",", true},
+    {"fr-CA", ",", true},
+    {"en_US", ".", true},
+    {"ja", ".", true},
+    {"es-419", ",", true},
+    {"zz", "", false},
+    {"zz_ZZ", "", false},
+    {"x", "", false},
+    {"german", "", false},
+    {"de_Deutschland", "", false},
+    {"de_DE_x", "", false},
+    {"", "", false},
+  }
+  for _, tt := range tests {
+    l, err := NewLocalizer(tt.tag)
+    if (err == nil) != tt.ok {
+      t.Errorf("NewLocalizer(%q) error = %v, want ok = %v", tt.tag, err, tt.ok)
+      continue
+    }
+    if tt.ok && l.decimal != tt.decimal {
+      t.Errorf("NewLocalizer(%q) decimal = %q, want %q", tt.tag, l.decimal, tt.decimal)
+    }
+  }
+}
+
+func TestLocalizerFormat(t *testing.T) {
+  de, _ := NewLocalizer("de_DE")
+  en, _ := NewLocalizer("en_US")
+  gb, _ := NewLocalizer("en_GB")
+  var none *Localizer
+  tests := []struct {
+    l    *Localizer
+    in   func(*Localizer) string
+    want string
+  }{
+    {de, func(l *Localizer) string { return l.FormatFloat(72.3) }, "72,3"},
+    {de, func(l *Localizer) string { return l.FormatFloat(-3) }, "-3"},
+    {en, func(l *Localizer) string { return l.FormatFloat(72.3) }, "72.3"},
+    {de, func(l *Localizer) string { return l.FormatNumbers("72.3 F (22.4 C), 29.92 in") }, "72,3 F (22,4 C), 29,92 in"},
+    {de, func(l *Localizer) string { return l.FormatNumbers("Version 3. Ende.") }, "Version 3. Ende."},
+    {none, func(l *Localizer) string { return l.FormatNumbers("72.3") }, "72.3"},
+    {de, func(l *Localizer) string { return l.FormatDate("September 1, 2013") }, "1.9.2013"},
+    {gb, func(l *Localizer) string { return l.FormatDate("3:04 PM CDT on September 1, 2013") }, "1 September 2013"},
+    {de, func(l *Localizer) string { return l.FormatDate("sometime") }, "sometime"},
+    {none, func(l *Localizer) string { return l.FormatDate("September 1, 2013") }, "September 1, 2013"},
+  }
+  for i, tt := range tests {
+    if got := tt.in(tt.l); got != tt.want {
+      t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+    }
+  }
+}
@@ -2,12 +2,13 @@
 * lookup.go
 *
 * This file is part of wu.  It contains functions related to
-* the -lookup switch (station lookup).
+* the -lookup, -station-info, and -airport-info switches (station
+* lookup).
 *
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:11:47 CST 2013
+* Last Modified: Wed Oct 14 16:04:37 CDT 2026
 *
 * Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
 *
@@ -28,23 +29,47 @@
 
 package main
 
-import "fmt"
+import (
+  "fmt"
+  "io"
+  "regexp"
+)
 
 type SLocation struct {
-  Nearby_weather_stations Nearby_weather_stations
+  City                    string                  `json:"city"`
+  State                   string                  `json:"state"`
+  Country                 string                  `json:"country"`
+  Country_name            string                  `json:"country_name"`
+  Lat                     string                  `json:"lat"`
+  Lon                     string                  `json:"lon"`
+  Tz_long                 string                  `json:"tz_long"`
+  Elevation               Value                   `json:"elevation"`
+  Nearby_weather_stations Nearby_weather_stations `json:"nearby_weather_stations"`
 }
 
 type Nearby_weather_stations struct {
-  Airport Airport
+  Airport Airport `json:"airport"`
+  Pws     Pws     `json:"pws"`
 }
 
 type Airport struct {
-  Station []Station
+  Station []Station `json:"station"`
+}
+
+type Pws struct {
+  Station []Station `json:"station"`
 }
 
 type Station struct {
-  City string
-  Icao string
+  City         string `json:"city"`
+  State        string `json:"state"`
+  Country      string `json:"country"`
+  Icao         string `json:"icao"`
+  Id           string `json:"id"`
+  Neighborhood string `json:"neighborhood"`
+  Lat          Value  `json:"lat"`
+  Lon          Value  `json:"lon"`
+  Distance_mi  Value  `json:"distance_mi"`
 }
 
 // printLookup prints nearby stations
@@ -58,3 +83,94 @@ func PrintLookup(obs *Conditions) {
     }
   }
 }
+
+// stationNetwork guesses the network a station reports through.  The
+// API doesn't distinguish ASOS from AWOS, so airports are lumped
+// together.
+func stationNetwork(obs *Conditions, id string) string {
+  for _, s := range obs.Location.Nearby_weather_stations.Pws.Station {
+    if s.Id == id {
+      return "Personal Weather Station (PWS)"
+    }
+  }
+  for _, s := range obs.Location.Nearby_weather_stations.Airport.Station {
+    if s.Icao == id {
+      return "Airport (ASOS/AWOS)"
+    }
+  }
+  if isPWSID(id) {
+    return "Personal Weather Station (PWS)"
+  }
+  if icaoPattern.MatchString(id) {
+    return "Airport (ASOS/AWOS)"
+  }
+  return "Unknown"
+}
+
+// PrintStationInfo prints metadata about the reporting station
+func PrintStationInfo(obs *Conditions, w io.Writer) {
+  current := obs.Current_observation
+  station := current.Observation_location
+  location := obs.Location
+
+  fmt.Fprintf(w, "Station information for %s\n", current.Station_id)
+  fmt.Fprintln(w, "   Name:", station.Full)
+  fmt.Fprintln(w, "   Call letters:", current.Station_id)
+  fmt.Fprintln(w, "   City:", station.City)
+  fmt.Fprintln(w, "   State:", station.State)
+  fmt.Fprintln(w, "   Country:", station.Country)
+  fmt.Fprintln(w, "   Latitude:", station.Latitude)
+  fmt.Fprintln(w, "   Longitude:", station.Longitude)
+  if station.Elevation != "" {
+    fmt.Fprintln(w, "   Elevation:", station.Elevation)
+  } else if location.Elevation != "" {
+    fmt.Fprintf(w, "   Elevation: %s m\n", location.Elevation)
+  }
+  if km, ok := StationDistance(obs); ok {
+    fmt.Fprintf(w, "   Distance: %.1f miles (%.1f km) from %s, %s\n",
+      km/kmPerMile, km, location.City, location.State)
+  }
+  fmt.Fprintln(w, "   Network:", stationNetwork(obs, current.Station_id))
+}
+
+// airportPattern matches a four-letter ICAO airport code
+var airportPattern = regexp.MustCompile(`^[A-Z]{4}$`)
+
+// FAAIdentifier returns the FAA location identifier for an ICAO code,
+// which for the contiguous US is the code without its leading K
+func FAAIdentifier(icao string) string {
+  if len(icao) == 4 && icao[0] == 'K' {
+    return icao[1:]
+  }
+  return icao
+}
+
+// PrintAirportInfo prints the name, location, and identifiers of the
+// airport station being reported on
+func PrintAirportInfo(obs *Conditions, w io.Writer) {
+  current := obs.Current_observation
+  icao := current.Station_id
+  city, state, country := obs.Location.City, obs.Location.State, obs.Location.Country
+  for _, s := range obs.Location.Nearby_weather_stations.Airport.Station {
+    if s.Icao == icao {
+      city, state, country = s.City, s.State, s.Country
+      break
+    }
+  }
+  name := current.Observation_location.Full
+  if name == "" {
+    name = city + " Airport"
+  }
+  fmt.Fprintf(w, "Airport information for %s\n", icao)
+  fmt.Fprintln(w, "   Name:", name)
+  fmt.Fprintln(w, "   City:", city)
+  fmt.Fprintln(w, "   State:", state)
+  fmt.Fprintln(w, "   Country:", country)
+  if e := current.Observation_location.Elevation; e != "" {
+    fmt.Fprintf(w, "   Elevation: %s MSL\n", e)
+  } else if obs.Location.Elevation != "" {
+    fmt.Fprintf(w, "   Elevation: %s m MSL\n", obs.Location.Elevation)
+  }
+  fmt.Fprintln(w, "   ICAO identifier:", icao)
+  fmt.Fprintln(w, "   FAA identifier:", FAAIdentifier(icao))
+}
@@ -0,0 +1,140 @@
+/*
+* lookup_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for station lookups and metadata (lookup.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:53 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "strings"
+  "testing"
+)
+
+const stationFixture = `{
+  "location": {
+    "city": "Lincoln", "state": "NE", "country": "US", "lat": "40.81", "lon": "-96.68", "elevation": "357",
+    "nearby_weather_stations": {
+      "airport": {"station": [{"city": "Lincoln", "state": "NE", "country": "US", "icao": "KLNK"}]},
+      "pws": {"station": [{"city": "Lincoln", "state": "NE", "country": "US", "id": "KNELINCO12"}]}
+    }
+  },
+  "current_observation": {
+    "station_id": "KLNK",
+    "observation_location": {
+      "full": "Lincoln Municipal Airport, Nebraska", "city": "Lincoln Municipal Airport", "state": "Nebraska",
+      "country": "US", "latitude": "40.85", "longitude": "-96.75", "elevation": "1188 ft"
+    }
+  }
+}`
+
+func TestPrintStationInfo(t *testing.T) {
+  var obs Conditions
+  if err := json.Unmarshal([]byte(stationFixture), &obs); err != nil {
+    t.Fatal(err)
+  }
+  var buf bytes.Buffer
+  PrintStationInfo(&obs, &buf)
+  out := buf.String()
+  for _, want := range []string{
+    "Station information for KLNK",
+    "Name: Lincoln Municipal Airport, Nebraska",
+    "Call letters: KLNK",
+    "City: Lincoln Municipal Airport",
+    "State: Nebraska",
+    "Country: US",
+    "Latitude: 40.85",
+    "Longitude: -96.75",
+    "Elevation: 1188 ft",
+    "Distance: 4.6 miles (7.4 km) from Lincoln, NE",
+    "Network: Airport (ASOS/AWOS)",
+  } {
+    if !strings.Contains(out, want) {
+      t.Errorf("station info is missing %q:\n%s", want, out)
+    }
+  }
+}
+
+func TestStationNetwork(t *testing.T) {
+  var obs Conditions
+  if err := json.Unmarshal([]byte(stationFixture), &obs); err != nil {
+    t.Fatal(err)
+  }
+  tests := []struct {
+    id, want string
+  }{
+    {"KLNK", "Airport (ASOS/AWOS)"},
+    {"KNELINCO12", "Personal Weather Station (PWS)"},
+    {"KOMA", "Airport (ASOS/AWOS)"},
+    {"lincoln", "Unknown"},
+  }
+  for _, tt := range tests {
+    if got := stationNetwork(&obs, tt.id); got != tt.want {
+      t.Errorf("stationNetwork(%q) = %q, want %q", tt.id, got, tt.want)
+    }
+  }
+}
+
+func TestPrintAirportInfo(t *testing.T) {
+  var obs Conditions
+  if err := json.Unmarshal([]byte(stationFixture), &obs); err != nil {
+    t.Fatal(err)
+  }
+  var buf bytes.Buffer
+  PrintAirportInfo(&obs, &buf)
+  out := buf.String()
+  for _, want := range []string{
+    "Airport information for KLNK",
+    "Name: Lincoln Municipal Airport, Nebraska",
+    "City: Lincoln\n",
+    "State: NE\n",
+    "Elevation: 1188 ft MSL",
+    "ICAO identifier: KLNK",
+    "FAA identifier: LNK",
+  } {
+    if !strings.Contains(out, want) {
+      t.Errorf("airport info is missing %q:\n%s", want, out)
+    }
+  }
+}
+
+func TestFAAIdentifier(t *testing.T) {
+  tests := []struct {
+    icao, want string
+  }{
+    {"KLNK", "LNK"},
+    {"KORD", "ORD"},
+    {"PANC", "PANC"},
+    {"CYYZ", "CYYZ"},
+    {"LNK", "LNK"},
+  }
+  for _, tt := range tests {
+    if got := FAAIdentifier(tt.icao); got != tt.want {
+      t.Errorf("FAAIdentifier(%q) = %q, want %q", tt.icao, got, tt.want)
+    }
+  }
+}
@@ -0,0 +1,90 @@
+/*
+* metrics.go
+*
+* This file is part of wu.  It contains functions related to
+* the --timing switch (how long requests and parsing take).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:58:13 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "sync"
+  "time"
+)
+
+// Metrics records where wu spends its time.  Requests may run in
+// parallel, so Fetch is the slowest single request, while Parse is the
+// total time spent decoding responses.
+type Metrics struct {
+  sync.Mutex
+  Start time.Time
+  Fetch time.Duration
+  Parse time.Duration
+}
+
+var metrics = Metrics{Start: time.Now()}
+
+// TimingOutput is the "_timing" object added to --format json output
+type TimingOutput struct {
+  FetchMs int64 `json:"fetch_ms"`
+  ParseMs int64 `json:"parse_ms"`
+  TotalMs int64 `json:"total_ms"`
+}
+
+// recordFetch notes how long one request took
+func (m *Metrics) recordFetch(d time.Duration) {
+  m.Lock()
+  defer m.Unlock()
+  if d > m.Fetch {
+    m.Fetch = d
+  }
+}
+
+// parseJSON is json.Unmarshal, timed
+func parseJSON(b []byte, v interface{}) error {
+  start := time.Now()
+  err := json.Unmarshal(b, v)
+  metrics.Lock()
+  metrics.Parse += time.Since(start)
+  metrics.Unlock()
+  return err
+}
+
+// Timing returns the durations recorded so far
+func (m *Metrics) Timing() TimingOutput {
+  m.Lock()
+  defer m.Unlock()
+  return TimingOutput{m.Fetch.Milliseconds(), m.Parse.Milliseconds(), time.Since(m.Start).Milliseconds()}
+}
+
+// PrintTiming writes the durations recorded so far to w
+func (m *Metrics) PrintTiming(w io.Writer) {
+  m.Lock()
+  defer m.Unlock()
+  fmt.Fprintf(w, "API fetch: %s, JSON parse: %s, Total: %s\n", m.Fetch.Round(time.Millisecond),
+    m.Parse.Round(time.Microsecond), time.Since(m.Start).Round(time.Millisecond))
+}
@@ -0,0 +1,92 @@
+/*
+* metrics_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the --timing switch (metrics.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:15:45 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "net/http"
+  "net/http/httptest"
+  "regexp"
+  "testing"
+  "time"
+)
+
+// timedServer answers each request after the given delay
+func timedServer(t *testing.T, delay time.Duration) string {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    time.Sleep(delay)
+    w.Write([]byte(`{"current_observation": {"temp_f": "72.3"}}`))
+  }))
+  t.Cleanup(srv.Close)
+  return srv.URL
+}
+
+func TestMetricsFetch(t *testing.T) {
+  saved := metrics.Fetch
+  t.Cleanup(func() { metrics.Fetch = saved })
+  metrics.Fetch = 0
+  fast, slow := timedServer(t, 5*time.Millisecond), timedServer(t, 30*time.Millisecond)
+  for _, url := range []string{slow, fast} {
+    if _, err := Fetch(url); err != nil {
+      t.Fatalf("Fetch(%s): %v", url, err)
+    }
+  }
+  if metrics.Fetch < 30*time.Millisecond {
+    t.Errorf("Fetch duration = %v, want the slowest request (at least 30ms)", metrics.Fetch)
+  }
+}
+
+func TestMetricsParse(t *testing.T) {
+  saved := metrics.Parse
+  t.Cleanup(func() { metrics.Parse = saved })
+  metrics.Parse = 0
+  var obs Conditions
+  b := []byte(`{"current_observation": {"temp_f": "72.3", "weather": "Clear"}}`)
+  for i := 0; i < 2; i++ {
+    if err := parseJSON(b, &obs); err != nil {
+      t.Fatal(err)
+    }
+  }
+  if metrics.Parse <= 0 {
+    t.Errorf("Parse duration = %v, want more than zero", metrics.Parse)
+  }
+  if got := metrics.Timing(); got.TotalMs <= 0 {
+    t.Errorf("Timing().TotalMs = %d, want more than zero", got.TotalMs)
+  }
+}
+
+func TestPrintTiming(t *testing.T) {
+  m := Metrics{Start: time.Now().Add(-250 * time.Millisecond), Fetch: 234 * time.Millisecond, Parse: 12 * time.Millisecond}
+  var buf bytes.Buffer
+  m.PrintTiming(&buf)
+  want := regexp.MustCompile(`^API fetch: 234ms, JSON parse: 12ms, Total: 2[5-9]\dms\n$`)
+  if !want.MatchString(buf.String()) {
+    t.Errorf("PrintTiming = %q", buf.String())
+  }
+}
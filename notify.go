@@ -0,0 +1,87 @@
+/*
+* notify.go
+*
+* This file is part of wu.  It contains functions related to
+* the --notify-desktop switch (desktop notifications for
+* alerts).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:26:09 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "os"
+  "os/exec"
+  "runtime"
+  "strings"
+)
+
+const notifyTitle = "Wu Weather Alert"
+
+// execCommand is exec.Command, swapped out to test notifications
+// without sending them
+var execCommand = exec.Command
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+  return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// notifyCommand returns the command that shows a desktop notification
+// on goos (a runtime.GOOS value)
+func notifyCommand(goos, title, body string) ([]string, error) {
+  switch goos {
+  case "linux", "freebsd", "openbsd", "netbsd":
+    return []string{"notify-send", "-u", "critical", title, body}, nil
+  case "darwin":
+    script := "display notification " + appleScriptString(body) + " with title " + appleScriptString(title)
+    return []string{"osascript", "-e", script}, nil
+  }
+  return nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+}
+
+// SendDesktopNotification shows a desktop notification with notify-send
+// (Linux and the BSDs) or osascript (macOS)
+func SendDesktopNotification(title, body string) error {
+  args, err := notifyCommand(runtime.GOOS, title, body)
+  if err != nil {
+    return err
+  }
+  return execCommand(args[0], args[1:]...).Run()
+}
+
+// NotifyAlerts sends a desktop notification for each alert, warning
+// (but carrying on) if one can't be sent
+func NotifyAlerts(alerts []Alerts) {
+  for _, a := range alerts {
+    body := a.Description
+    if a.Expires != "" {
+      body += " until " + a.Expires
+    }
+    if err := SendDesktopNotification(notifyTitle, body); err != nil {
+      fmt.Fprintln(os.Stderr, "Desktop notification skipped: "+err.Error())
+      return
+    }
+  }
+}
@@ -0,0 +1,85 @@
+/*
+* notify_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --notify-desktop (notify.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:41 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "os"
+  "os/exec"
+  "runtime"
+  "strings"
+  "testing"
+)
+
+func TestNotifyCommand(t *testing.T) {
+  tests := []struct {
+    goos string
+    want []string
+  }{
+    {"linux", []string{"notify-send", "-u", "critical", "Wu Weather Alert", `Flood "Watch"`}},
+    {"freebsd", []string{"notify-send", "-u", "critical", "Wu Weather Alert", `Flood "Watch"`}},
+    {"darwin", []string{"osascript", "-e", `display notification "Flood \"Watch\"" with title "Wu Weather Alert"`}},
+  }
+  for _, tt := range tests {
+    got, err := notifyCommand(tt.goos, notifyTitle, `Flood "Watch"`)
+    if err != nil || strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+      t.Errorf("notifyCommand(%s) = %q, %v; want %q", tt.goos, got, err, tt.want)
+    }
+  }
+  if got, err := notifyCommand("windows", notifyTitle, "Flood Watch"); err == nil {
+    t.Errorf("notifyCommand(windows) = %q, want an error", got)
+  }
+}
+
+// mockExec replaces execCommand until the test ends, recording each
+// command instead of running it
+func mockExec(t *testing.T) *[][]string {
+  commands := make([][]string, 0)
+  saved := execCommand
+  t.Cleanup(func() { execCommand = saved })
+  execCommand = func(name string, args ...string) *exec.Cmd {
+    commands = append(commands, append([]string{name}, args...))
+    return exec.Command(os.Args[0], "-test.run=^$")
+  }
+  return &commands
+}
+
+func TestNotifyAlerts(t *testing.T) {
+  if _, err := notifyCommand(runtime.GOOS, "", ""); err != nil {
+    t.Skip(err)
+  }
+  commands := mockExec(t)
+  NotifyAlerts([]Alerts{{Description: "Heat Advisory", Expires: "8:00 PM CDT"}, {Description: "Flood Watch"}})
+  if len(*commands) != 2 {
+    t.Fatalf("NotifyAlerts ran %q, want 2 commands", *commands)
+  }
+  first, second := strings.Join((*commands)[0], " "), strings.Join((*commands)[1], " ")
+  if !strings.Contains(first, "Heat Advisory until 8:00 PM CDT") || !strings.Contains(second, "Flood Watch") {
+    t.Errorf("NotifyAlerts ran %q", *commands)
+  }
+}
@@ -0,0 +1,430 @@
+/*
+* output.go
+*
+* This file is part of wu.  It contains functions related to
+* the --format switch (machine-readable output).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 15:20:37 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "io"
+  "math"
+  "path/filepath"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// SchemaVersion is incremented whenever a change to the JSON output
+// would break existing parsers.
+const SchemaVersion = 1
+
+// JSONOutput is the envelope wrapped around every JSON document wu
+// produces.
+type JSONOutput struct {
+  SchemaVersion int           `json:"schema_version"`
+  WuVersion     string        `json:"wu_version"`
+  Data          interface{}   `json:"data"`
+  Timing        *TimingOutput `json:"_timing,omitempty"`
+}
+
+// OperationData returns the part of obs that belongs to the given
+// API operation (e.g. "conditions" or "history_20130901")
+func OperationData(obs *Conditions, operation string) interface{} {
+  switch strings.Split(operation, "_")[0] {
+  case "almanac":
+    return obs.Almanac
+  case "astronomy":
+    return obs.Moon_phase
+  case "moonillumination":
+    return obs.Moon_phase.PercentIlluminated
+  case "moonphasetext":
+    var buf strings.Builder
+    PrintMoonPhaseText(obs, &buf)
+    return strings.TrimSpace(buf.String())
+  case "conditionsbadge":
+    temp, ok := obs.Current_observation.Temp_f.Float()
+    if !ok {
+      return nil
+    }
+    return ShieldsBadgeURL(temp, obs.Current_observation.Weather)
+  case "dewpointcomfort":
+    current := obs.Current_observation
+    dp, ok := current.DewpointF()
+    if !ok {
+      return nil
+    }
+    return map[string]interface{}{
+      "dewpoint_f":        current.Dewpoint_f,
+      "dewpoint_c":        current.Dewpoint_c,
+      "dew_point_comfort": DewPointComfort(dp),
+    }
+  case "conditionsepoch":
+    epoch, err := strconv.ParseInt(obs.Current_observation.Observation_epoch, 10, 64)
+    if err != nil {
+      return nil
+    }
+    return epoch
+  case "localtime":
+    t, ok := LocalTime(&obs.Current_observation)
+    if !ok {
+      return nil
+    }
+    return t.Format(time.RFC3339)
+  case "emojisummary":
+    lang, supportUTF8 := utf8Locale()
+    return BestIcon(obs.Current_observation.Icon, lang, supportUTF8)
+  case "conditionshistory":
+    return map[string]interface{}{"conditions": obs.Current_observation, "almanac": obs.Almanac}
+  case "historyanomaly":
+    a, ok := ComputeAnomaly(&obs.Current_observation, &obs.Almanac)
+    if !ok {
+      return nil
+    }
+    return a
+  case "windrose":
+    current := obs.Current_observation
+    degrees, _ := current.Wind_degrees.Float()
+    return map[string]interface{}{
+      "direction":    compassPoints[compassPoint(degrees)].name,
+      "wind_degrees": current.Wind_degrees,
+      "wind_mph":     current.Wind_mph,
+      "wind_kph":     current.Wind_kph,
+    }
+  case "conditionsdiff":
+    if obs.previous == nil {
+      return nil
+    }
+    return DiffCurrents(obs.previous, &obs.Current_observation)
+  case "conditionstrend":
+    if obs.trendBaseline == nil {
+      return nil
+    }
+    return ComputeTrend(&obs.Current_observation, obs.trendBaseline)
+  case "airquality":
+    aqi := obs.Air_quality
+    if index, ok := aqi.Index.Float(); ok && aqi.Category == "" {
+      aqi.Category = AQICategory(int(math.Round(index)))
+    }
+    return aqi
+  case "pollen":
+    if obs.Pollen == nil {
+      return nil
+    }
+    p := *obs.Pollen
+    p.RiskLevel = p.Risk()
+    return p
+  case "alerts":
+    return obs.Alerts
+  case "conditions":
+    current := obs.Current_observation
+    current.Observation_epoch_int, _ = strconv.ParseInt(current.Observation_epoch, 10, 64)
+    if dp, ok := current.DewpointF(); ok {
+      current.Dew_point_comfort = DewPointComfort(dp)
+    }
+    if omitzero {
+      return withoutNullish(current)
+    }
+    return current
+  case "forecast", "forecast10day":
+    return obs.Forecast
+  case "forecastbestday":
+    days := obs.Forecast.Simpleforecast.Forecastday
+    i := BestForecastDay(days, dobestday)
+    if i < 0 {
+      return nil
+    }
+    return map[string]interface{}{"mode": dobestday, "score": math.Round(ScoreForecastDay(days[i], dobestday)), "day": days[i]}
+  case "forecastdelta":
+    if obs.forecastBaseline == nil {
+      return nil
+    }
+    days := obs.Forecast.Simpleforecast.Forecastday
+    if len(days) > forecastDeltaDays {
+      days = days[:forecastDeltaDays]
+    }
+    return DiffForecasts(obs.forecastBaseline.Days, days, &units)
+  case "forecastweekendscore":
+    return UpcomingWeekends(obs.Forecast.Simpleforecast.Forecastday)
+  case "forecasttravelindex":
+    modes := travelModes
+    if travelmode != "all" {
+      modes = []string{travelmode}
+    }
+    var scores []map[string]interface{}
+    for _, d := range obs.Forecast.Simpleforecast.Forecastday {
+      day := map[string]interface{}{"weekday": d.Date.Weekday}
+      for _, m := range modes {
+        score := TravelScore(d, m)
+        day[m] = map[string]interface{}{"score": score, "label": TravelLabel(score)}
+      }
+      scores = append(scores, day)
+    }
+    return scores
+  case "forecastraintotal":
+    total, n := SumQPF(obs.Forecast.Simpleforecast.Forecastday)
+    if n == 0 {
+      return nil
+    }
+    return map[string]interface{}{"in": math.Round(total*100) / 100, "mm": math.Round(total * 25.4), "days": n}
+  case "forecastsummary":
+    return SummarizeForecast(obs.Forecast.Simpleforecast.Forecastday)
+  case "forecastclothing":
+    days := obs.Forecast.Simpleforecast.Forecastday
+    if len(days) == 0 {
+      return nil
+    }
+    high, ok1 := days[0].High.Fahrenheit.Float()
+    low, ok2 := days[0].Low.Fahrenheit.Float()
+    if !ok1 || !ok2 {
+      return nil
+    }
+    pop, _ := strconv.Atoi(string(days[0].Pop))
+    return ClothingSuggestion(high, low, pop, days[0].Conditions)
+  case "hourlyrainwindow":
+    hours := obs.Hourly_forecast
+    if len(hours) > rainWindowHours {
+      hours = hours[:rainWindowHours]
+    }
+    return FindRainWindows(hours, 50)
+  case "forecastuvpeak":
+    uv, at := FindPeakUV(obs.Hourly_forecast)
+    if at == "" {
+      return nil
+    }
+    return map[string]interface{}{"uvi": uv, "label": UVLabel(uv), "time": at}
+  case "forecastpack":
+    return PackForecast(obs.Forecast.Simpleforecast.Forecastday, units.Metric(), 80)
+  case "forecasthighlow":
+    return obs.Forecast.Simpleforecast.Forecastday
+  case "hourly":
+    return obs.Hourly_forecast
+  case "yesterday", "history":
+    return obs.History
+  case "yesterdaynormal":
+    c, ok := CompareYesterdayToNormal(&obs.History, &obs.Almanac)
+    if !ok {
+      return nil
+    }
+    return c
+  case "yesterdayrainfall":
+    if len(obs.History.Dailysummary) == 0 {
+      return nil
+    }
+    s := obs.History.Dailysummary[0]
+    return map[string]string{"precipi": s.Precipi, "precipm": s.Precipm}
+  case "recentprecip":
+    return obs.today
+  case "planner":
+    return obs.Trip
+  case "airportinfo":
+    current := obs.Current_observation
+    return map[string]interface{}{
+      "icao":                 current.Station_id,
+      "faa":                  FAAIdentifier(current.Station_id),
+      "observation_location": current.Observation_location,
+    }
+  case "plannerconfidence":
+    years, rating := obs.Trip.Confidence()
+    return map[string]interface{}{"years": years, "confidence": rating}
+  case "plannerraindays":
+    expected, total := EstimateRainyDays(&obs.Trip)
+    return map[string]interface{}{"rainy_days": expected, "days": total, "estimated": rainyDaysEstimated(&obs.Trip)}
+  case "tide":
+    return obs.Tide
+  case "geolookup", "stationlistcsv":
+    return obs.Location
+  case "stationinfo":
+    return map[string]interface{}{
+      "station_id":           obs.Current_observation.Station_id,
+      "observation_location": obs.Current_observation.Observation_location,
+      "network":              stationNetwork(obs, obs.Current_observation.Station_id),
+    }
+  }
+  return nil
+}
+
+// withoutNullish returns v as decoded JSON with the string values
+// -omit-zero leaves out (see omitted) removed
+func withoutNullish(v interface{}) interface{} {
+  b, err := json.Marshal(v)
+  if err != nil {
+    return v
+  }
+  var data interface{}
+  if err := json.Unmarshal(b, &data); err != nil {
+    return v
+  }
+  return dropNullish(data)
+}
+
+func dropNullish(data interface{}) interface{} {
+  obj, ok := data.(map[string]interface{})
+  if !ok {
+    return data
+  }
+  for k, v := range obj {
+    if s, ok := v.(string); ok && omitted(s) {
+      delete(obj, k)
+    } else {
+      obj[k] = dropNullish(v)
+    }
+  }
+  return obj
+}
+
+// operationsData collects the data for each operation, keyed by
+// operation name
+func operationsData(obs *Conditions, operations []string) map[string]interface{} {
+  data := make(map[string]interface{})
+  for _, operation := range operations {
+    data[strings.Split(operation, "_")[0]] = OperationData(obs, operation)
+  }
+  return data
+}
+
+var pathIndexPattern = regexp.MustCompile(`^(\w*)\[(\d+)\]$`)
+
+// jsonPath follows a dot-separated path of keys (and [n] array
+// indexes) through decoded JSON data
+func jsonPath(data interface{}, path string) (interface{}, error) {
+  if path == "" {
+    return data, nil
+  }
+  parts := strings.SplitN(path, ".", 2)
+  key, rest := parts[0], ""
+  if len(parts) == 2 {
+    rest = parts[1]
+  }
+  index := -1
+  if m := pathIndexPattern.FindStringSubmatch(key); m != nil {
+    key = m[1]
+    index, _ = strconv.Atoi(m[2])
+  }
+  if key != "" {
+    obj, ok := data.(map[string]interface{})
+    if !ok {
+      return nil, fmt.Errorf("%s: not an object", key)
+    }
+    if data, ok = obj[key]; !ok {
+      return nil, fmt.Errorf("%s: no such key", key)
+    }
+  }
+  if index >= 0 {
+    arr, ok := data.([]interface{})
+    if !ok || index >= len(arr) {
+      return nil, fmt.Errorf("%s[%d]: no such element", key, index)
+    }
+    data = arr[index]
+  }
+  return jsonPath(data, rest)
+}
+
+// PrintJSONPath writes the single value at path (see jsonPath) to w
+func PrintJSONPath(obs *Conditions, operations []string, path string, w io.Writer) error {
+  b, err := json.Marshal(operationsData(obs, operations))
+  if err != nil {
+    return err
+  }
+  // Numbers are kept as written, so that e.g. an epoch isn't printed
+  // as 1.7e+09
+  var data interface{}
+  dec := json.NewDecoder(bytes.NewReader(b))
+  dec.UseNumber()
+  if err := dec.Decode(&data); err != nil {
+    return err
+  }
+  value, err := jsonPath(data, path)
+  if err != nil {
+    return err
+  }
+  switch v := value.(type) {
+  case string:
+    fmt.Fprintln(w, v)
+  case map[string]interface{}, []interface{}:
+    b, _ := json.Marshal(v)
+    fmt.Fprintln(w, string(b))
+  default:
+    fmt.Fprintln(w, v)
+  }
+  return nil
+}
+
+// ExportPath returns the file that -export writes: path, with format
+// as its extension if it has none
+func ExportPath(path, format string) string {
+  if filepath.Ext(path) != "" {
+    return path
+  }
+  return path + "." + format
+}
+
+// PrintJSON writes the data for each operation to w as a single JSON
+// document, keyed by operation name
+func PrintJSON(obs *Conditions, operations []string, w io.Writer) error {
+  data := operationsData(obs, operations)
+  out := JSONOutput{SchemaVersion: SchemaVersion, WuVersion: GetVersion(), Data: data}
+  if timing {
+    t := metrics.Timing()
+    out.Timing = &t
+  }
+  var b []byte
+  var err error
+  if jsonindent > 0 {
+    b, err = json.MarshalIndent(out, "", strings.Repeat(" ", jsonindent))
+  } else {
+    b, err = json.Marshal(out)
+  }
+  if err != nil {
+    return err
+  }
+  _, err = fmt.Fprintln(w, string(b))
+  return err
+}
+
+// NDJSONLine is one line of --format ndjson output
+type NDJSONLine struct {
+  Operation string      `json:"operation"`
+  Station   string      `json:"station"`
+  Timestamp string      `json:"timestamp"`
+  Data      interface{} `json:"data"`
+}
+
+// PrintNDJSON writes one JSON object per operation to w, one per line
+func PrintNDJSON(obs *Conditions, operations []string, stationId string, w io.Writer) error {
+  now := time.Now().Format(time.RFC3339)
+  enc := json.NewEncoder(w)
+  for _, operation := range operations {
+    line := NDJSONLine{strings.Split(operation, "_")[0], stationId, now, OperationData(obs, operation)}
+    if err := enc.Encode(line); err != nil {
+      return err
+    }
+  }
+  return nil
+}
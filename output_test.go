@@ -0,0 +1,197 @@
+/*
+* output_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the --format switch (output.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:44 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestPrintJSONEnvelope(t *testing.T) {
+  for _, operations := range [][]string{{}, {"conditions"}, {"conditions", "forecast", "alerts"}} {
+    var buf bytes.Buffer
+    if err := PrintJSON(&Conditions{}, operations, &buf); err != nil {
+      t.Fatal(err)
+    }
+    var out map[string]json.RawMessage
+    if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+      t.Fatalf("%v: invalid JSON: %v", operations, err)
+    }
+    for _, key := range []string{"schema_version", "wu_version", "data"} {
+      if _, ok := out[key]; !ok {
+        t.Errorf("%v: no %q at the top level of %s", operations, key, buf.String())
+      }
+    }
+    if string(out["schema_version"]) != "1" {
+      t.Errorf("schema_version = %s, want 1", out["schema_version"])
+    }
+  }
+}
+
+func TestJSONPath(t *testing.T) {
+  var data interface{}
+  doc := `{"conditions": {"temp_f": 72.5, "display_location": {"city": "Lincoln"}},
+    "forecast": {"txt_forecast": {"forecastday": [{"title": "Monday"}, {"title": "Monday Night"}]}},
+    "hours": [[1, 2], [3, 4]]}`
+  if err := json.Unmarshal([]byte(doc), &data); err != nil {
+    t.Fatal(err)
+  }
+  tests := []struct {
+    path string
+    want interface{}
+    ok   bool
+  }{
+    {"conditions.temp_f", 72.5, true},
+    {"conditions.display_location.city", "Lincoln", true},
+    {"forecast.txt_forecast.forecastday[1].title", "Monday Night", true},
+    {"forecast.txt_forecast.forecastday[0].title", "Monday", true},
+    {"hours[1]", []interface{}{3.0, 4.0}, true},
+    {"conditions.wind_mph", nil, false},
+    {"conditions.temp_f.value", nil, false},
+    {"forecast.txt_forecast.forecastday[2].title", nil, false},
+    {"conditions[0]", nil, false},
+    {"alerts", nil, false},
+  }
+  for _, tt := range tests {
+    got, err := jsonPath(data, tt.path)
+    if (err == nil) != tt.ok {
+      t.Errorf("jsonPath(%q) error = %v, want ok = %v", tt.path, err, tt.ok)
+      continue
+    }
+    if tt.ok && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+      t.Errorf("jsonPath(%q) = %v, want %v", tt.path, got, tt.want)
+    }
+  }
+}
+
+func TestPrintJSONPathNumbers(t *testing.T) {
+  obs := &Conditions{Current_observation: Current{Observation_epoch: "1700000000", Temp_f: "72.5"}}
+  tests := []struct {
+    path string
+    want string
+  }{
+    {"conditions.observation_epoch_int", "1700000000\n"},
+    {"conditions.temp_f", "72.5\n"},
+    {"conditionsepoch", "1700000000\n"},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    if err := PrintJSONPath(obs, []string{"conditions", "conditionsepoch"}, tt.path, &buf); err != nil {
+      t.Errorf("PrintJSONPath(%q): %v", tt.path, err)
+      continue
+    }
+    if buf.String() != tt.want {
+      t.Errorf("PrintJSONPath(%q) = %q, want %q", tt.path, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestExportPath(t *testing.T) {
+  tests := []struct {
+    path, format, want string
+  }{
+    {"weather", "html", "weather.html"},
+    {"weather.htm", "html", "weather.htm"},
+    {"/tmp/out/report", "json", "/tmp/out/report.json"},
+    {"data.txt", "csv", "data.txt"},
+  }
+  for _, tt := range tests {
+    if got := ExportPath(tt.path, tt.format); got != tt.want {
+      t.Errorf("ExportPath(%q, %q) = %q, want %q", tt.path, tt.format, got, tt.want)
+    }
+  }
+}
+
+func TestPrintNDJSON(t *testing.T) {
+  obs := &Conditions{Current_observation: Current{Station_id: "KLNK", Temp_f: "72.5"}}
+  operations := []string{"conditions", "forecast", "alerts", "history_20130901"}
+  var buf bytes.Buffer
+  if err := PrintNDJSON(obs, operations, "KLNK", &buf); err != nil {
+    t.Fatal(err)
+  }
+  lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+  if len(lines) != len(operations) {
+    t.Fatalf("PrintNDJSON wrote %d lines, want %d:\n%s", len(lines), len(operations), buf.String())
+  }
+  for i, line := range lines {
+    var got NDJSONLine
+    if err := json.Unmarshal([]byte(line), &got); err != nil {
+      t.Errorf("line %d isn't JSON: %v\n%s", i+1, err, line)
+      continue
+    }
+    if want := strings.Split(operations[i], "_")[0]; got.Operation != want {
+      t.Errorf("line %d operation = %q, want %q", i+1, got.Operation, want)
+    }
+    if got.Station != "KLNK" {
+      t.Errorf("line %d station = %q, want KLNK", i+1, got.Station)
+    }
+    if _, err := time.Parse(time.RFC3339, got.Timestamp); err != nil {
+      t.Errorf("line %d timestamp %q isn't RFC 3339", i+1, got.Timestamp)
+    }
+  }
+}
+
+func TestPrintJSONIndent(t *testing.T) {
+  saved := jsonindent
+  t.Cleanup(func() { jsonindent = saved })
+  var obs Conditions
+  obs.Current_observation = Current{Station_id: "KLNK", Temp_f: "72.3"}
+  tests := []struct {
+    indent int
+    prefix string // how the first nested line should start
+  }{
+    {0, ""},
+    {2, "\n  \""},
+    {4, "\n    \""},
+  }
+  for _, tt := range tests {
+    jsonindent = tt.indent
+    var buf bytes.Buffer
+    if err := PrintJSON(&obs, []string{"conditions"}, &buf); err != nil {
+      t.Fatal(err)
+    }
+    out := strings.TrimSuffix(buf.String(), "\n")
+    if !json.Valid([]byte(out)) {
+      t.Errorf("indent %d: invalid JSON %s", tt.indent, out)
+    }
+    if tt.indent == 0 {
+      if strings.Contains(out, "\n") {
+        t.Errorf("compact output has a newline: %q", out)
+      }
+      continue
+    }
+    if !strings.Contains(out, tt.prefix) {
+      t.Errorf("indent %d: output isn't indented by %d spaces:\n%s", tt.indent, tt.indent, out)
+    }
+  }
+}
@@ -2,12 +2,13 @@
 * history.go
 *
 * This file is part of wu.  It contains functions related to
-* the --planner switch (travel planner based on historical data).
+* the --planner, --compare-planner, and --planner-rain-days switches
+* (travel planner based on historical data).
 *
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:11:55 CST 2013
+* Last Modified: Wed Oct 14 19:21:05 CDT 2026
 *
 * Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
 *
@@ -30,163 +31,243 @@ package main
 
 import (
   "fmt"
+  "io"
+  "math"
   "os"
+  "regexp"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
 )
 
 type Trip struct {
-  Title        string
-  Airport_code string
-  Error        string
-  Chance_of    Chance_of
+  Title            string           `json:"title"`
+  Airport_code     string           `json:"airport_code"`
+  Error            string           `json:"error"`
+  Period_of_record Period_of_record `json:"period_of_record"`
+  Temp_high        Trip_temp        `json:"temp_high"`
+  Temp_low         Trip_temp        `json:"temp_low"`
+  Precip           Trip_precip      `json:"precip"`
+  Chance_of        Chance_of        `json:"chance_of"`
+}
+
+type Period_of_record struct {
+  Date_start Record_date `json:"date_start"`
+  Date_end   Record_date `json:"date_end"`
+}
+
+type Record_date struct {
+  Date Date `json:"date"`
+}
+
+// Confidence returns the number of years of data behind the planner
+// averages and a rating for them (0 and "Unknown" if the API didn't
+// say)
+func (t *Trip) Confidence() (int, string) {
+  start, err1 := strconv.Atoi(t.Period_of_record.Date_start.Date.Year)
+  end, err2 := strconv.Atoi(t.Period_of_record.Date_end.Date.Year)
+  if err1 != nil || err2 != nil || end < start {
+    return 0, "Unknown"
+  }
+  years := end - start + 1
+  switch {
+  case years < 10:
+    return years, "Low"
+  case years <= 20:
+    return years, "Medium"
+  }
+  return years, "High"
+}
+
+// PrintPlannerConfidence prints how much history the planner is based on
+func PrintPlannerConfidence(trip *Trip, w io.Writer) {
+  years, rating := trip.Confidence()
+  if years == 0 {
+    fmt.Fprintln(w, "Confidence: Unknown")
+    return
+  }
+  fmt.Fprintf(w, "Based on %d years of historical data (%s confidence)\n", years, strings.ToUpper(rating))
+}
+
+type Trip_temp struct {
+  Min Trip_degrees `json:"min"`
+  Avg Trip_degrees `json:"avg"`
+  Max Trip_degrees `json:"max"`
+}
+
+type Trip_degrees struct {
+  F string `json:"f"`
+  C string `json:"c"`
+}
+
+type Trip_precip struct {
+  Min Trip_amount `json:"min"`
+  Avg Trip_amount `json:"avg"`
+  Max Trip_amount `json:"max"`
+}
+
+type Trip_amount struct {
+  In string `json:"in"`
+  Cm string `json:"cm"`
+}
+
+// Mm returns the amount in millimeters
+func (a Trip_amount) Mm() string {
+  cm, err := strconv.ParseFloat(a.Cm, 64)
+  if err != nil {
+    return a.Cm
+  }
+  return strconv.FormatFloat(cm*10, 'f', 1, 64)
 }
 
 type Chance_of struct {
-  Tempoversixty           Tempoversixty
-  Chanceofwindyday        Chanceofwindyday
-  Chanceofsunnycloudyday  Chanceofsunnycloudyday
-  Chanceofprecip          Chanceofprecip
-  Chanceofrainday         Chanceofrainday
-  Chanceofpartlycloudyday Chanceofpartlycloudyday
-  Chanceofthunderday      Chanceofthunderday
-  Chanceofhumidday        Chanceofhumidday
-  Chanceofcloudyday       Chanceofcloudyday
-  Tempoverfreezing        Tempoverfreezing
-  Tempoverninety          Tempoverninety
-  Chanceoffogday          Chanceoffogday
-  Chanceofsnowonground    Chanceofsnowonground
-  Chanceoftornadoday      Chanceoftornadoday
-  Chanceofsultryday       Chanceofsultryday
-  Tempbelowfreezing       Tempbelowfreezing
-  Chanceofhailday         Chanceofhailday
-  Chanceofsnowday         Chanceofsnowday
+  Tempoversixty           Tempoversixty           `json:"tempoversixty"`
+  Chanceofwindyday        Chanceofwindyday        `json:"chanceofwindyday"`
+  Chanceofsunnycloudyday  Chanceofsunnycloudyday  `json:"chanceofsunnycloudyday"`
+  Chanceofprecip          Chanceofprecip          `json:"chanceofprecip"`
+  Chanceofrainday         Chanceofrainday         `json:"chanceofrainday"`
+  Chanceofpartlycloudyday Chanceofpartlycloudyday `json:"chanceofpartlycloudyday"`
+  Chanceofthunderday      Chanceofthunderday      `json:"chanceofthunderday"`
+  Chanceofhumidday        Chanceofhumidday        `json:"chanceofhumidday"`
+  Chanceofcloudyday       Chanceofcloudyday       `json:"chanceofcloudyday"`
+  Tempoverfreezing        Tempoverfreezing        `json:"tempoverfreezing"`
+  Tempoverninety          Tempoverninety          `json:"tempoverninety"`
+  Chanceoffogday          Chanceoffogday          `json:"chanceoffogday"`
+  Chanceofsnowonground    Chanceofsnowonground    `json:"chanceofsnowonground"`
+  Chanceoftornadoday      Chanceoftornadoday      `json:"chanceoftornadoday"`
+  Chanceofsultryday       Chanceofsultryday       `json:"chanceofsultryday"`
+  Tempbelowfreezing       Tempbelowfreezing       `json:"tempbelowfreezing"`
+  Chanceofhailday         Chanceofhailday         `json:"chanceofhailday"`
+  Chanceofsnowday         Chanceofsnowday         `json:"chanceofsnowday"`
 }
 
 type Tempoversixty struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofwindyday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofsunnycloudyday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofprecip struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofrainday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofpartlycloudyday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofthunderday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofhumidday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofcloudyday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Tempoverfreezing struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Tempoverninety struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceoffogday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofsnowonground struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceoftornadoday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofsultryday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Tempbelowfreezing struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofhailday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
 type Chanceofsnowday struct {
-  Name        string
-  Description string
-  Percentage  string
+  Name        string `json:"name"`
+  Description string `json:"description"`
+  Percentage  string `json:"percentage"`
 }
 
-func PrintPlanner(obs *Conditions, stationId string) {
+func PrintPlanner(obs *Conditions, stationId string, units *Units) {
 
   if obs.Trip.Error != "" {
-    fmt.Println(obs.Trip.Error)
-    os.Exit(0)
+    Fail(APIError, obs.Trip.Error)
   }
 
   planner := obs.Trip.Chance_of
   fmt.Println(obs.Trip.Title)
   fmt.Println("Station: " + obs.Trip.Airport_code)
+  PrintPlannerConfidence(&obs.Trip, os.Stdout)
   fmt.Println("Chance of: ")
   fmt.Println("   Temps:")
-  fmt.Printf("      Over 90 F (32 C): %s%%\n", planner.Tempoverninety.Percentage)
-  fmt.Printf("      Between 60 F (15 C) and 90 F (32 C): %s%%\n", planner.Tempoversixty.Percentage)
-  fmt.Printf("      Between 32 F (0 C) and 60 (16 C): %s%%\n", planner.Tempoversixty.Percentage)
-  fmt.Printf("      Below 32 F (0 C): %s%%\n", planner.Tempbelowfreezing.Percentage)
-  fmt.Printf("   Dewpoint above 70 F (21 C): %s%%\n", planner.Chanceofsultryday.Percentage)
-  fmt.Printf("   Dewpoint above 60 F (15 C): %s%%\n", planner.Chanceofhumidday.Percentage)
+  fmt.Printf("      Over %s: %s%%\n", units.Temp("90", "32"), planner.Tempoverninety.Percentage)
+  fmt.Printf("      Between %s and %s: %s%%\n", units.Temp("60", "15"), units.Temp("90", "32"), planner.Tempoversixty.Percentage)
+  fmt.Printf("      Between %s and %s: %s%%\n", units.Temp("32", "0"), units.Temp("60", "16"), planner.Tempoversixty.Percentage)
+  fmt.Printf("      Below %s: %s%%\n", units.Temp("32", "0"), planner.Tempbelowfreezing.Percentage)
+  fmt.Printf("   Dewpoint above %s: %s%%\n", units.Temp("70", "21"), planner.Chanceofsultryday.Percentage)
+  fmt.Printf("   Dewpoint above %s: %s%%\n", units.Temp("60", "15"), planner.Chanceofhumidday.Percentage)
   fmt.Printf("   Winds over 10 mph (15 km/h): %s%%\n", planner.Chanceofwindyday.Percentage)
   fmt.Printf("   %s day: %s%%\n", planner.Chanceofsunnycloudyday.Name, planner.Chanceofsunnycloudyday.Percentage)
   fmt.Printf("   %s day: %s%%\n", planner.Chanceofcloudyday.Name, planner.Chanceofcloudyday.Percentage)
@@ -200,3 +281,168 @@ func PrintPlanner(obs *Conditions, stationId string) {
   fmt.Printf("   %s: %s%%\n", planner.Chanceofsnowday.Name, planner.Chanceofsnowday.Percentage)
   fmt.Printf("   %s: %s%%\n", planner.Chanceofsnowonground.Name, planner.Chanceofsnowonground.Percentage)
 }
+
+var plannerPattern = regexp.MustCompile(`^\d{8}$`)
+
+// Days returns the number of days the planner covers, from its title
+// (e.g. "October 14 - October 20")
+func (t *Trip) Days() (int, bool) {
+  dates := strings.Split(t.Title, " - ")
+  if len(dates) != 2 {
+    return 0, false
+  }
+  var start, end time.Time
+  var err1, err2 error
+  for _, layout := range []string{"January 2", "Jan 2", "Jan 02"} {
+    start, err1 = time.Parse(layout, strings.TrimSpace(dates[0]))
+    end, err2 = time.Parse(layout, strings.TrimSpace(dates[1]))
+    if err1 == nil && err2 == nil {
+      break
+    }
+  }
+  if err1 != nil || err2 != nil {
+    return 0, false
+  }
+  if end.Before(start) {
+    end = end.AddDate(1, 0, 0) // the range spans New Year's
+  }
+  return int(end.Sub(start).Hours()/24) + 1, true
+}
+
+// EstimateRainyDays returns how many of the planner's days have
+// historically seen measurable precipitation, out of the total.  The
+// API gives the share of rainy days directly, but failing that the
+// number expected from the average chance of precipitation is used
+// (see rainyDaysEstimated).
+func EstimateRainyDays(trip *Trip) (expected float64, total int) {
+  total, _ = trip.Days()
+  if pct, err := strconv.ParseFloat(trip.Chance_of.Chanceofrainday.Percentage, 64); err == nil {
+    return math.Round(pct / 100 * float64(total)), total
+  }
+  pop, _ := strconv.ParseFloat(trip.Chance_of.Chanceofprecip.Percentage, 64)
+  return pop / 100 * float64(total), total
+}
+
+// rainyDaysEstimated reports whether EstimateRainyDays had to fall back
+// on the chance of precipitation
+func rainyDaysEstimated(trip *Trip) bool {
+  _, err := strconv.ParseFloat(trip.Chance_of.Chanceofrainday.Percentage, 64)
+  return err != nil
+}
+
+// PrintRainyDays prints how many of the planner's days have
+// historically been rainy
+func PrintRainyDays(trip *Trip, w io.Writer) {
+  expected, total := EstimateRainyDays(trip)
+  if total == 0 {
+    fmt.Fprintln(w, "The planner's date range is unknown.")
+    return
+  }
+  share := 100 * expected / float64(total)
+  if rainyDaysEstimated(trip) {
+    fmt.Fprintf(w, "Historically, about %.1f out of %d days (%.1f%%) in this date range have seen measurable precipitation.\n", expected, total, share)
+    fmt.Fprintln(w, "(This is an estimate from the average chance of precipitation.)")
+    return
+  }
+  fmt.Fprintf(w, "Historically, %.0f out of %d days (%.1f%%) in this date range have seen measurable precipitation.\n", expected, total, share)
+}
+
+// plannerMetric is one row of a --compare-planner table
+type plannerMetric struct {
+  name   string
+  value  func(t *Trip, units *Units) string
+  higher bool // whether a higher value is the better one
+}
+
+var plannerMetrics = []plannerMetric{
+  {"Average high", func(t *Trip, units *Units) string { return tripDegrees(t.Temp_high.Avg, units) }, true},
+  {"Average low", func(t *Trip, units *Units) string { return tripDegrees(t.Temp_low.Avg, units) }, true},
+  {"Chance of precipitation", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofprecip.Percentage }, false},
+  {"Chance of rain", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofrainday.Percentage }, false},
+  {"Chance of sun", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofsunnycloudyday.Percentage }, true},
+  {"Chance of clouds", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofcloudyday.Percentage }, false},
+  {"Chance of wind", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofwindyday.Percentage }, false},
+  {"Chance of humidity", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofhumidday.Percentage }, false},
+  {"Chance of thunderstorms", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofthunderday.Percentage }, false},
+  {"Chance of snow", func(t *Trip, _ *Units) string { return t.Chance_of.Chanceofsnowday.Percentage }, false},
+}
+
+// tripDegrees picks the temperature in the units being displayed
+func tripDegrees(d Trip_degrees, units *Units) string {
+  if units.Metric() {
+    return d.C
+  }
+  return d.F
+}
+
+const (
+  colorGreen = "\x1b[32m"
+  colorRed   = "\x1b[31m"
+  colorBlue  = "\x1b[34m"
+  colorReset = "\x1b[0m"
+)
+
+// FetchPlanners requests the planner for each of ranges (MMDDMMDD) in
+// parallel
+func FetchPlanners(stationId string, ranges ...string) []*Trip {
+  trips := make([]*Trip, len(ranges))
+  errs := make([]error, len(ranges))
+  var wg sync.WaitGroup
+  for i, r := range ranges {
+    wg.Add(1)
+    go func(i int, r string) {
+      defer wg.Done()
+      var obs Conditions
+      b, err := Fetch(BuildURL([]string{"planner_" + r}, stationId))
+      if err == nil {
+        err = parseJSON(b, &obs)
+      }
+      if err == nil {
+        err = obs.Response.Err()
+      }
+      trips[i], errs[i] = &obs.Trip, err
+    }(i, r)
+  }
+  wg.Wait()
+  for i, err := range errs {
+    CheckError(err)
+    if trips[i].Error != "" {
+      Fail(APIError, trips[i].Error)
+    }
+  }
+  return trips
+}
+
+// PrintPlannerComparison prints two planners side by side, coloring
+// the better value for each metric green and the worse one red if w
+// is a terminal
+func PrintPlannerComparison(a, b *Trip, rangeA, rangeB string, units *Units, w io.Writer) {
+  writePlannerComparison(a, b, rangeA, rangeB, units, colorEnabled(w), w)
+}
+
+// writePlannerComparison is PrintPlannerComparison, coloring the
+// values if color is set
+func writePlannerComparison(a, b *Trip, rangeA, rangeB string, units *Units, color bool, w io.Writer) {
+  fmt.Fprintf(w, "%-24s %12s %12s\n", "", rangeA, rangeB)
+  for _, m := range plannerMetrics {
+    va, vb := m.value(a, units), m.value(b, units)
+    unit := "%"
+    if strings.HasPrefix(m.name, "Average") {
+      unit = " F"
+      if units.Metric() {
+        unit = " C"
+      }
+    }
+    ca, cb := fmt.Sprintf("%12s", va+unit), fmt.Sprintf("%12s", vb+unit)
+    fa, errA := strconv.ParseFloat(va, 64)
+    fb, errB := strconv.ParseFloat(vb, 64)
+    if color && errA == nil && errB == nil && fa != fb {
+      if (fa > fb) == m.higher {
+        ca, cb = colorGreen+ca+colorReset, colorRed+cb+colorReset
+      } else {
+        ca, cb = colorRed+ca+colorReset, colorGreen+cb+colorReset
+      }
+    }
+    fmt.Fprintf(w, "%-24s %s %s\n", m.name, ca, cb)
+  }
+}
@@ -0,0 +1,184 @@
+/*
+* planner_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the travel planner (planner.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:15:44 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "math"
+  "strings"
+  "testing"
+)
+
+// plannerTrip decodes a planner response's trip
+func plannerTrip(t *testing.T, doc string) *Trip {
+  var obs Conditions
+  if err := json.Unmarshal([]byte(`{"trip": `+doc+`}`), &obs); err != nil {
+    t.Fatal(err)
+  }
+  return &obs.Trip
+}
+
+// comparisonFixture is two planners, for June and October
+func comparisonFixture(t *testing.T) (*Trip, *Trip) {
+  june := plannerTrip(t, `{
+    "temp_high": {"avg": {"F": "84", "C": "29"}}, "temp_low": {"avg": {"F": "62", "C": "17"}},
+    "chance_of": {"chanceofprecip": {"percentage": "40"}, "chanceofrainday": {"percentage": "35"},
+      "chanceofsunnycloudyday": {"percentage": "60"}, "chanceofsnowday": {"percentage": "0"}}}`)
+  october := plannerTrip(t, `{
+    "temp_high": {"avg": {"F": "66", "C": "19"}}, "temp_low": {"avg": {"F": "42", "C": "6"}},
+    "chance_of": {"chanceofprecip": {"percentage": "20"}, "chanceofrainday": {"percentage": "35"},
+      "chanceofsunnycloudyday": {"percentage": "70"}, "chanceofsnowday": {"percentage": "0"}}}`)
+  return june, october
+}
+
+func TestPrintPlannerComparison(t *testing.T) {
+  june, october := comparisonFixture(t)
+  var buf bytes.Buffer
+  writePlannerComparison(june, october, "06010615", "10011015", &Units{Temperature: "f"}, true, &buf)
+  rows := make(map[string]string)
+  for _, line := range strings.Split(buf.String(), "\n")[1:] {
+    if len(line) > 24 {
+      rows[strings.TrimSpace(line[:24])] = line[24:]
+    }
+  }
+  tests := []struct {
+    metric       string
+    first, other string // the color of each column ("" for none)
+  }{
+    {"Average high", colorGreen, colorRed},
+    {"Average low", colorGreen, colorRed},
+    {"Chance of precipitation", colorRed, colorGreen},
+    {"Chance of sun", colorRed, colorGreen},
+    {"Chance of rain", "", ""},
+    {"Chance of snow", "", ""},
+    {"Chance of wind", "", ""}, // no data for either range
+  }
+  for _, tt := range tests {
+    row, ok := rows[tt.metric]
+    if !ok {
+      t.Errorf("no %s row in\n%s", tt.metric, buf.String())
+      continue
+    }
+    if tt.first == "" {
+      if strings.Contains(row, "\x1b[") {
+        t.Errorf("%s is colored for a tie: %q", tt.metric, row)
+      }
+      continue
+    }
+    a, b := strings.Index(row, tt.first), strings.Index(row, tt.other)
+    if a < 0 || b < 0 || a > b {
+      t.Errorf("%s = %q, want the first range %q and the second %q", tt.metric, row, tt.first, tt.other)
+    }
+  }
+  if !strings.Contains(rows["Average high"], "84 F") || !strings.Contains(rows["Average high"], "66 F") {
+    t.Errorf("Average high = %q, want 84 F and 66 F", rows["Average high"])
+  }
+}
+
+func TestPrintPlannerComparisonUncolored(t *testing.T) {
+  june, october := comparisonFixture(t)
+  var buf bytes.Buffer
+  PrintPlannerComparison(june, october, "06010615", "10011015", &Units{Temperature: "c"}, &buf)
+  out := buf.String()
+  if strings.Contains(out, "\x1b[") {
+    t.Errorf("comparison not to a terminal is colored:\n%q", out)
+  }
+  if !strings.Contains(out, "29 C") || !strings.Contains(out, "19 C") || strings.Contains(out, " F") {
+    t.Errorf("comparison in Celsius = \n%s", out)
+  }
+}
+
+func TestPrintPlannerConfidence(t *testing.T) {
+  tests := []struct {
+    trip string
+    want string
+  }{
+    {`{"period_of_record": {"date_start": {"date": {"year": "1990"}}, "date_end": {"date": {"year": "2013"}}}}`,
+      "Based on 24 years of historical data (HIGH confidence)\n"},
+    {`{"period_of_record": {"date_start": {"date": {"year": "1998"}}, "date_end": {"date": {"year": "2013"}}}}`,
+      "Based on 16 years of historical data (MEDIUM confidence)\n"},
+    {`{"period_of_record": {"date_start": {"date": {"year": "2004"}}, "date_end": {"date": {"year": "2013"}}}}`,
+      "Based on 10 years of historical data (MEDIUM confidence)\n"},
+    {`{"period_of_record": {"date_start": {"date": {"year": "2008"}}, "date_end": {"date": {"year": "2013"}}}}`,
+      "Based on 6 years of historical data (LOW confidence)\n"},
+    {`{"title": "no period of record"}`, "Confidence: Unknown\n"},
+    {`{"period_of_record": {"date_start": {"date": {"year": "2013"}}, "date_end": {"date": {"year": "1990"}}}}`,
+      "Confidence: Unknown\n"},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintPlannerConfidence(plannerTrip(t, tt.trip), &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintPlannerConfidence(%s) = %q, want %q", tt.trip, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestEstimateRainyDays(t *testing.T) {
+  tests := []struct {
+    name      string
+    trip      string
+    expected  float64
+    total     int
+    estimated bool
+    want      string
+  }{
+    {"direct count",
+      `{"title": "Jul 01 - Jul 14", "chance_of": {"chanceofrainday": {"percentage": "28.6"}, "chanceofprecip": {"percentage": "50"}}}`,
+      4, 14, false,
+      "Historically, 4 out of 14 days (28.6%) in this date range have seen measurable precipitation.\n"},
+    {"estimate from the chance of precipitation",
+      `{"title": "Jul 01 - Jul 14", "chance_of": {"chanceofprecip": {"percentage": "25"}}}`,
+      3.5, 14, true,
+      "Historically, about 3.5 out of 14 days (25.0%) in this date range have seen measurable precipitation.\n" +
+        "(This is an estimate from the average chance of precipitation.)\n"},
+    {"across New Year's",
+      `{"title": "Dec 30 - Jan 02", "chance_of": {"chanceofrainday": {"percentage": "50"}}}`,
+      2, 4, false,
+      "Historically, 2 out of 4 days (50.0%) in this date range have seen measurable precipitation.\n"},
+    {"no date range", `{"chance_of": {"chanceofrainday": {"percentage": "50"}}}`,
+      0, 0, false, "The planner's date range is unknown.\n"},
+  }
+  for _, tt := range tests {
+    trip := plannerTrip(t, tt.trip)
+    expected, total := EstimateRainyDays(trip)
+    if math.Abs(expected-tt.expected) > 1e-9 || total != tt.total {
+      t.Errorf("%s: EstimateRainyDays = %v, %d, want %v, %d", tt.name, expected, total, tt.expected, tt.total)
+    }
+    if got := rainyDaysEstimated(trip); got != tt.estimated {
+      t.Errorf("%s: rainyDaysEstimated = %v, want %v", tt.name, got, tt.estimated)
+    }
+    var buf bytes.Buffer
+    PrintRainyDays(trip, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("%s: PrintRainyDays = %q, want %q", tt.name, buf.String(), tt.want)
+    }
+  }
+}
@@ -0,0 +1,300 @@
+/*
+* plot.go
+*
+* This file is part of wu.  It contains functions related to
+* the --history-plot switch (terminal charts of historical data).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:31:02 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "math"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// plotTemperatures draws daily high (▲) and low (▽) temperatures as a
+// chart no more than width columns by height rows, including the axes
+// and date labels
+func plotTemperatures(days []HistoryDay, width, height int) string {
+  const labelWidth = 6 // "-10 ┤"
+
+  highs := make([]float64, 0)
+  lows := make([]float64, 0)
+  plotted := make([]HistoryDay, 0)
+  for _, day := range days {
+    high, hErr := strconv.ParseFloat(day.Summary.Maxtempi, 64)
+    low, lErr := strconv.ParseFloat(day.Summary.Mintempi, 64)
+    if hErr == nil && lErr == nil {
+      highs = append(highs, high)
+      lows = append(lows, low)
+      plotted = append(plotted, day)
+    }
+  }
+  if len(plotted) == 0 || width <= labelWidth || height < 3 {
+    return "No temperature data to plot\n"
+  }
+
+  // Leave room for the X axis and the date labels
+  plotWidth := width - labelWidth
+  plotHeight := height - 2
+  if len(plotted) > plotWidth {
+    plotted, highs, lows = plotted[:plotWidth], highs[:plotWidth], lows[:plotWidth]
+  }
+  colWidth := plotWidth / len(plotted)
+
+  hi, lo := highs[0], lows[0]
+  for i := range highs {
+    hi = math.Max(hi, highs[i])
+    lo = math.Min(lo, lows[i])
+  }
+  if hi == lo {
+    hi = lo + 1
+  }
+  row := func(t float64) int {
+    return plotHeight - 1 - int(math.Floor((t-lo)/(hi-lo)*float64(plotHeight-1)+0.5))
+  }
+
+  grid := make([][]rune, plotHeight)
+  for y := range grid {
+    grid[y] = []rune(strings.Repeat(" ", plotWidth))
+  }
+  for i := range plotted {
+    x := i*colWidth + colWidth/2
+    top, bottom := row(highs[i]), row(lows[i])
+    for y := top + 1; y < bottom; y++ {
+      grid[y][x] = '|'
+    }
+    grid[bottom][x] = '▽'
+    grid[top][x] = '▲'
+  }
+
+  var chart string
+  for y := range grid {
+    if y == 0 || y == plotHeight-1 || y == plotHeight/2 {
+      t := hi - float64(y)/float64(plotHeight-1)*(hi-lo)
+      chart += fmt.Sprintf("%4.0f ┤", t)
+    } else {
+      chart += "     │"
+    }
+    chart += strings.TrimRight(string(grid[y]), " ") + "\n"
+  }
+  chart += "     └" + strings.Repeat("─", plotWidth) + "\n"
+
+  // Label as many columns as fit without overlapping
+  labels := []rune(strings.Repeat(" ", plotWidth))
+  next := 0
+  for i, day := range plotted {
+    label := day.Date.Format("1/2")
+    x := i*colWidth + colWidth/2
+    if x >= next && x+len(label) <= plotWidth {
+      copy(labels[x:], []rune(label))
+      next = x + len(label) + 1
+    }
+  }
+  chart += "      " + strings.TrimRight(string(labels), " ") + "\n"
+  return chart
+}
+
+// PrecipBarChart draws daily precipitation as a bar chart (in mm) no
+// more than width columns by height rows, including the axes and date
+// labels.  A trace of precipitation is drawn as a half block.
+func PrecipBarChart(days []HistoryDay, width, height int) string {
+  const labelWidth = 6 // "12.5 ┤"
+
+  amounts := make([]float64, 0)
+  plotted := make([]HistoryDay, 0)
+  for _, day := range days {
+    p := day.Summary.Precipi
+    if p == "T" {
+      amounts = append(amounts, -1)
+      plotted = append(plotted, day)
+    } else if in, err := strconv.ParseFloat(p, 64); err == nil {
+      amounts = append(amounts, in*25.4)
+      plotted = append(plotted, day)
+    }
+  }
+  if len(plotted) == 0 || width <= labelWidth || height < 3 {
+    return "No precipitation data to plot\n"
+  }
+
+  plotWidth := width - labelWidth
+  plotHeight := height - 2
+  if len(plotted) > plotWidth {
+    plotted, amounts = plotted[:plotWidth], amounts[:plotWidth]
+  }
+  colWidth := plotWidth / len(plotted)
+  barWidth := colWidth - 1
+  if barWidth < 1 {
+    barWidth = 1
+  }
+
+  max := 0.0
+  for _, a := range amounts {
+    max = math.Max(max, a)
+  }
+  if max == 0 {
+    max = 1
+  }
+
+  grid := make([][]rune, plotHeight)
+  for y := range grid {
+    grid[y] = []rune(strings.Repeat(" ", plotWidth))
+  }
+  for i, a := range amounts {
+    x := i * colWidth
+    rows := int(math.Floor(a/max*float64(plotHeight) + 0.5))
+    if a > 0 && rows == 0 {
+      rows = 1
+    }
+    for dx := 0; dx < barWidth && x+dx < plotWidth; dx++ {
+      if a < 0 {
+        grid[plotHeight-1][x+dx] = '▄'
+      }
+      for y := plotHeight - rows; y < plotHeight; y++ {
+        grid[y][x+dx] = '█'
+      }
+    }
+  }
+
+  format := "%4.0f ┤"
+  if max < 10 {
+    format = "%4.1f ┤"
+  }
+  var chart string
+  for y := range grid {
+    if y == 0 || y == plotHeight/2 {
+      chart += fmt.Sprintf(format, max*float64(plotHeight-y)/float64(plotHeight))
+    } else {
+      chart += "     │"
+    }
+    chart += strings.TrimRight(string(grid[y]), " ") + "\n"
+  }
+  chart += "   0 └" + strings.Repeat("─", plotWidth) + "\n"
+
+  labels := []rune(strings.Repeat(" ", plotWidth))
+  next := 0
+  for i, day := range plotted {
+    label := day.Date.Format("1/2")
+    x := i * colWidth
+    if x >= next && x+len(label) <= plotWidth {
+      copy(labels[x:], []rune(label))
+      next = x + len(label) + 1
+    }
+  }
+  chart += "      " + strings.TrimRight(string(labels), " ") + "\n"
+  return chart
+}
+
+// heatShades are the heatmap's cells from coolest to warmest
+var heatShades = []string{"  ", "░░", "▒▒", "▓▓", "██"}
+
+// CalendarHeatmap draws the daily highs of one month as a calendar
+// (a column per weekday, a row per week) shaded from coolest to
+// warmest, with a legend.  Days without data are shown as "··".
+func CalendarHeatmap(days []HistoryDay, year, month int, units *Units) string {
+  first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+  highs := make(map[int]float64)
+  lo, hi := math.Inf(1), math.Inf(-1)
+  for _, day := range days {
+    if day.Date.Year() != year || int(day.Date.Month()) != month {
+      continue
+    }
+    if t, err := strconv.ParseFloat(day.Summary.Maxtempi, 64); err == nil {
+      highs[day.Date.Day()] = t
+      lo, hi = math.Min(lo, t), math.Max(hi, t)
+    }
+  }
+  if len(highs) == 0 {
+    return "No temperature data to plot\n"
+  }
+  level := func(t float64) int {
+    if hi == lo {
+      return len(heatShades) / 2
+    }
+    l := int((t - lo) / (hi - lo) * float64(len(heatShades)))
+    if l >= len(heatShades) {
+      l = len(heatShades) - 1
+    }
+    return l
+  }
+
+  chart := first.Format("January 2006") + "\n"
+  chart += "Su Mo Tu We Th Fr Sa\n"
+  offset := int(first.Weekday())
+  chart += strings.Repeat("   ", offset)
+  last := first.AddDate(0, 1, -1).Day()
+  for d := 1; d <= last; d++ {
+    cell := "··"
+    if t, ok := highs[d]; ok {
+      cell = heatShades[level(t)]
+    }
+    chart += cell
+    if (offset+d)%7 == 0 || d == last {
+      chart = strings.TrimRight(chart, " ") + "\n"
+    } else {
+      chart += " "
+    }
+  }
+
+  // One legend entry per shade, giving the range of highs it covers
+  step := (hi - lo) / float64(len(heatShades))
+  chart += "\n"
+  for i, shade := range heatShades {
+    if hi == lo {
+      if i == len(heatShades)/2 {
+        chart += fmt.Sprintf("[%s] %s\n", shade, units.Degrees(lo, 0))
+      }
+      continue
+    }
+    chart += fmt.Sprintf("[%s] %s to %s\n", shade, units.Degrees(lo+float64(i)*step, 0), units.Degrees(lo+float64(i+1)*step, 0))
+  }
+  return chart
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a single line of block characters, scaled
+// so that the lowest value is ▁ and the highest █
+func sparkline(values []float64) string {
+  if len(values) == 0 {
+    return ""
+  }
+  hi, lo := values[0], values[0]
+  for _, v := range values {
+    hi = math.Max(hi, v)
+    lo = math.Min(lo, v)
+  }
+  line := make([]rune, len(values))
+  for i, v := range values {
+    level := len(sparkBlocks) / 2
+    if hi > lo {
+      level = int(math.Floor((v-lo)/(hi-lo)*float64(len(sparkBlocks)-1)+0.5))
+    }
+    line[i] = sparkBlocks[level]
+  }
+  return string(line)
+}
@@ -0,0 +1,216 @@
+/*
+* plot_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the history charts (plot.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:12:41 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "strconv"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestPlotTemperatures(t *testing.T) {
+  start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+  days := historyDays(start,
+    maxTemps("80", "75", "70", "65", "60", "70", "72"),
+    minTemps("60", "55", "50", "45", "40", "50", "52"))
+  // 70 columns for the plot gives each day 10, centred on column 5;
+  // 18 rows run from 80 (row 0) to 40 (row 17)
+  chart := plotTemperatures(days, 76, 20)
+  lines := strings.Split(strings.TrimRight(chart, "\n"), "\n")
+  if len(lines) != 20 {
+    t.Fatalf("plotTemperatures drew %d lines, want 20:\n%s", len(lines), chart)
+  }
+  at := func(row, day int) rune {
+    line := []rune(lines[row])
+    col := 6 + day*10 + 5
+    if col >= len(line) {
+      return ' '
+    }
+    return line[col]
+  }
+  tests := []struct {
+    row, day int
+    want     rune
+  }{
+    {0, 0, '▲'},  // 80
+    {4, 0, '|'},  // between 80 and 60
+    {8, 0, '▽'},  // 60
+    {9, 0, ' '},  // below the first day's low
+    {17, 4, '▽'}, // 40, the lowest low
+    {8, 4, '▲'},  // 60
+    {3, 6, '▲'},  // 72
+    {12, 6, '▽'}, // 52
+  }
+  for _, tt := range tests {
+    if got := at(tt.row, tt.day); got != tt.want {
+      t.Errorf("row %d, day %d = %q, want %q\n%s", tt.row, tt.day, got, tt.want, chart)
+    }
+  }
+  if !strings.HasPrefix(lines[0], "  80 ┤") || !strings.HasPrefix(lines[17], "  40 ┤") {
+    t.Errorf("Y axis labels wrong:\n%s", chart)
+  }
+  if !strings.HasPrefix(lines[19], "           7/1") {
+    t.Errorf("X axis labels = %q, want 7/1 under the first day", lines[19])
+  }
+}
+
+func TestPlotTemperaturesBounds(t *testing.T) {
+  highs := make([]string, 200)
+  lows := make([]string, 200)
+  for i := range highs {
+    highs[i], lows[i] = "70", "50"
+  }
+  start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+  days := historyDays(start, maxTemps(highs...), minTemps(lows...))
+  chart := plotTemperatures(days, 80, 20)
+  lines := strings.Split(strings.TrimRight(chart, "\n"), "\n")
+  if len(lines) > 20 {
+    t.Errorf("plotTemperatures drew %d lines, want at most 20", len(lines))
+  }
+  for _, l := range lines {
+    if n := len([]rune(l)); n > 80 {
+      t.Errorf("line %q is %d columns wide, want at most 80", l, n)
+    }
+  }
+  if got := plotTemperatures(historyDays(start, maxTemps("NA"), minTemps("NA")), 80, 20); got != "No temperature data to plot\n" {
+    t.Errorf("plotTemperatures with no data = %q", got)
+  }
+}
+
+func TestCalendarHeatmap(t *testing.T) {
+  // August 2023 starts on a Tuesday.  Highs climb a degree a day from
+  // 70 to 100, so each shade covers six degrees; the 15th is missing.
+  start := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+  var days []HistoryDay
+  for d := 1; d <= 31; d++ {
+    high := strconv.Itoa(69 + d)
+    if d == 15 {
+      high = ""
+    }
+    days = append(days, HistoryDay{Date: start.AddDate(0, 0, d-1), Summary: Dailysummary{Maxtempi: high}})
+  }
+  chart := CalendarHeatmap(days, 2023, 8, &Units{Temperature: "f"})
+  lines := strings.Split(chart, "\n")
+  if lines[0] != "August 2023" || lines[1] != "Su Mo Tu We Th Fr Sa" {
+    t.Fatalf("heading is wrong:\n%s", chart)
+  }
+  cell := func(day int) string {
+    i := 2 + day - 1 // Tuesday's index in the first week
+    row := []rune(lines[2+i/7])
+    col := i % 7 * 3
+    if col+2 > len(row) {
+      return "  "
+    }
+    return string(row[col : col+2])
+  }
+  tests := []struct {
+    day  int
+    want string
+  }{
+    {1, "  "},  // 70
+    {7, "░░"},  // 76
+    {13, "▒▒"}, // 82
+    {14, "▒▒"}, // 83
+    {15, "··"}, // no data
+    {19, "▓▓"}, // 88
+    {26, "██"}, // 95
+    {31, "██"}, // 100
+  }
+  for _, tt := range tests {
+    if got := cell(tt.day); got != tt.want {
+      t.Errorf("August %d = %q, want %q\n%s", tt.day, got, tt.want, chart)
+    }
+  }
+  if !strings.HasPrefix(lines[4], "▒▒ ▒▒ ·· ") {
+    t.Errorf("Sunday the 13th doesn't start a week: %q", lines[4])
+  }
+  if !strings.Contains(chart, "[██] 94 F to 100 F\n") {
+    t.Errorf("legend is wrong:\n%s", chart)
+  }
+  metric := CalendarHeatmap(days, 2023, 8, &Units{Temperature: "c"})
+  if !strings.Contains(metric, "[██] 34 C to 38 C\n") || strings.Contains(metric, " F\n") {
+    t.Errorf("metric legend is wrong:\n%s", metric)
+  }
+}
+
+func TestPrecipBarChart(t *testing.T) {
+  start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+  precip := []string{"0.50", "T", "1.00", "0.25", "0.00", "0.75", "0.10"}
+  days := historyDays(start, precips(precip...))
+  // 4 columns for each of the 7 days, and 8 rows of plot
+  chart := PrecipBarChart(days, 6+7*4, 10)
+  lines := strings.Split(chart, "\n")
+  cell := func(x, y int) rune {
+    line := []rune(lines[y])
+    if 6+x >= len(line) {
+      return ' '
+    }
+    return line[6+x]
+  }
+  for i, want := range []int{4, 0, 8, 2, 0, 6, 1} {
+    got := 0
+    for y := 0; y < 8; y++ {
+      if cell(i*4, y) == '█' {
+        got++
+      }
+    }
+    if got != want {
+      t.Errorf("%s (%s in): bar is %d rows, want %d\n%s", days[i].Date.Format("1/2"), precip[i], got, want, chart)
+    }
+  }
+  if cell(4, 7) != '▄' || cell(4, 6) != ' ' {
+    t.Errorf("trace isn't a half block at the bottom:\n%s", chart)
+  }
+  if !strings.HasPrefix(lines[0], "  25 ┤") {
+    t.Errorf("scale doesn't top out at 25 mm:\n%s", chart)
+  }
+  if !strings.Contains(lines[9], "7/1") || !strings.Contains(lines[9], "7/7") {
+    t.Errorf("date labels missing:\n%s", chart)
+  }
+}
+
+func TestSparkline(t *testing.T) {
+  temps := []float64{58, 57, 57, 59, 62, 66, 70, 73, 75, 76, 74, 71}
+  line := sparkline(temps)
+  if n := len([]rune(line)); n != len(temps) {
+    t.Errorf("sparkline has %d characters, want %d: %q", n, len(temps), line)
+  }
+  for _, r := range line {
+    if !strings.ContainsRune("▁▂▃▄▅▆▇█", r) {
+      t.Errorf("sparkline has %q, not a block character: %q", r, line)
+    }
+  }
+  if r := []rune(line); r[1] != '▁' || r[9] != '█' {
+    t.Errorf("sparkline = %q, want ▁ at the low and █ at the high", line)
+  }
+  if line := sparkline([]float64{60, 60, 60}); line != "▅▅▅" {
+    t.Errorf("flat sparkline = %q, want ▅▅▅", line)
+  }
+}
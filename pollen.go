@@ -0,0 +1,118 @@
+/*
+* pollen.go
+*
+* This file is part of wu.  It contains functions related to
+* the --pollen-risk and --pollen-allergy-type switches.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 15:53:40 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+)
+
+// Pollen is the pollen forecast, on a scale of 0 to 10 for each type
+// of pollen, which the API only reports for some locations (and not
+// always for every type)
+type Pollen struct {
+  Tree      Value  `json:"tree"`
+  Grass     Value  `json:"grass"`
+  Weed      Value  `json:"weed"`
+  RiskLevel string `json:"risk_level"`
+}
+
+// pollenLevel returns a pollen level as a whole number, and false if
+// it wasn't reported
+func pollenLevel(v Value) (int, bool) {
+  f, ok := v.Float()
+  return int(math.Round(f)), ok
+}
+
+// PollenLabel describes a pollen level (e.g. "Moderate")
+func PollenLabel(level int) string {
+  switch {
+  case level <= 0:
+    return "None"
+  case level <= 2:
+    return "Low"
+  case level <= 4:
+    return "Moderate"
+  case level <= 6:
+    return "High"
+  }
+  return "Very High"
+}
+
+// Risk returns the overall risk level: the API's, if it gave one, or
+// else the label for the highest of the pollen levels reported (or ""
+// if none was)
+func (p *Pollen) Risk() string {
+  if p.RiskLevel != "" {
+    return p.RiskLevel
+  }
+  highest, found := 0, false
+  for _, v := range []Value{p.Tree, p.Grass, p.Weed} {
+    if level, ok := pollenLevel(v); ok && (!found || level > highest) {
+      highest, found = level, true
+    }
+  }
+  if !found {
+    return ""
+  }
+  return PollenLabel(highest)
+}
+
+// PrintPollen prints the pollen forecast for allergen ("tree",
+// "grass", "weed", or "all")
+func PrintPollen(obs *Conditions, allergen string, w io.Writer) {
+  p := obs.Pollen
+  if p == nil {
+    fmt.Fprintln(w, "No pollen forecast available for this location.")
+    return
+  }
+  levels := []struct {
+    name, label string
+    level       Value
+  }{
+    {"tree", "Tree", p.Tree},
+    {"grass", "Grass", p.Grass},
+    {"weed", "Weed", p.Weed},
+  }
+  fmt.Fprintln(w, "Pollen forecast:")
+  for _, l := range levels {
+    if allergen != "all" && allergen != l.name {
+      continue
+    }
+    if level, ok := pollenLevel(l.level); ok {
+      fmt.Fprintf(w, "   %-6s %2d (%s)\n", l.label+":", level, PollenLabel(level))
+    } else {
+      fmt.Fprintf(w, "   %-6s no data\n", l.label+":")
+    }
+  }
+  if allergen == "all" && p.Risk() != "" {
+    fmt.Fprintf(w, "   Overall risk: %s\n", p.Risk())
+  }
+}
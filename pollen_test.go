@@ -0,0 +1,115 @@
+/*
+* pollen_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the -pollen-risk switch (pollen.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:48 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "testing"
+)
+
+func TestPollenUnmarshal(t *testing.T) {
+  fixture := `{"pollen": {"tree": 8, "grass": "3", "weed": 0}}`
+  var obs Conditions
+  if err := json.Unmarshal([]byte(fixture), &obs); err != nil {
+    t.Fatal(err)
+  }
+  if obs.Pollen == nil {
+    t.Fatal("Pollen is nil")
+  }
+  tests := []struct {
+    name  string
+    v     Value
+    level int
+    label string
+  }{
+    {"tree", obs.Pollen.Tree, 8, "Very High"},
+    {"grass", obs.Pollen.Grass, 3, "Moderate"},
+    {"weed", obs.Pollen.Weed, 0, "None"},
+  }
+  for _, tt := range tests {
+    level, ok := pollenLevel(tt.v)
+    if !ok || level != tt.level {
+      t.Errorf("%s = %d, %v, want %d", tt.name, level, ok, tt.level)
+    }
+    if got := PollenLabel(level); got != tt.label {
+      t.Errorf("PollenLabel(%s) = %q, want %q", tt.name, got, tt.label)
+    }
+  }
+  if got := obs.Pollen.Risk(); got != "Very High" {
+    t.Errorf("Risk() = %q, want %q", got, "Very High")
+  }
+}
+
+func TestPollenLabel(t *testing.T) {
+  tests := []struct {
+    level int
+    want  string
+  }{
+    {0, "None"},
+    {1, "Low"},
+    {2, "Low"},
+    {3, "Moderate"},
+    {4, "Moderate"},
+    {5, "High"},
+    {6, "High"},
+    {7, "Very High"},
+    {10, "Very High"},
+  }
+  for _, tt := range tests {
+    if got := PollenLabel(tt.level); got != tt.want {
+      t.Errorf("PollenLabel(%d) = %q, want %q", tt.level, got, tt.want)
+    }
+  }
+}
+
+func TestPrintPollen(t *testing.T) {
+  tests := []struct {
+    pollen   *Pollen
+    allergen string
+    want     string
+  }{
+    {&Pollen{Tree: "8", Grass: "3", Weed: "0"}, "all",
+      "Pollen forecast:\n   Tree:   8 (Very High)\n   Grass:  3 (Moderate)\n   Weed:   0 (None)\n   Overall risk: Very High\n"},
+    {&Pollen{Tree: "8", Grass: "3", Weed: "0"}, "grass",
+      "Pollen forecast:\n   Grass:  3 (Moderate)\n"},
+    {&Pollen{Grass: "1"}, "all",
+      "Pollen forecast:\n   Tree:  no data\n   Grass:  1 (Low)\n   Weed:  no data\n   Overall risk: Low\n"},
+    {nil, "all", "No pollen forecast available for this location.\n"},
+  }
+  for _, tt := range tests {
+    var obs Conditions
+    obs.Pollen = tt.pollen
+    var buf bytes.Buffer
+    PrintPollen(&obs, tt.allergen, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintPollen(%+v, %q) = %q, want %q", tt.pollen, tt.allergen, buf.String(), tt.want)
+    }
+  }
+}
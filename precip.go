@@ -0,0 +1,95 @@
+/*
+* precip.go
+*
+* This file is part of wu.  It contains functions related to
+* the --recent-precip switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:48:25 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "strconv"
+  "text/tabwriter"
+)
+
+// hourPrecip is the precipitation reported over one hour
+type hourPrecip struct {
+  Hour     string
+  Amount   float64 // inches
+  Trace    bool    // a trace was reported
+  Reported bool    // at least one amount (or trace) was reported
+}
+
+// precipByHour adds up the precipitation reported with each of
+// observations by the hour it was reported in, in the order the hours
+// appear
+func precipByHour(observations []Observations) []hourPrecip {
+  hours := make([]hourPrecip, 0)
+  for _, o := range observations {
+    if len(hours) == 0 || hours[len(hours)-1].Hour != o.Date.Hour {
+      hours = append(hours, hourPrecip{Hour: o.Date.Hour})
+    }
+    h := &hours[len(hours)-1]
+    if o.Precipi == "T" {
+      h.Trace, h.Reported = true, true
+    } else if in, err := strconv.ParseFloat(o.Precipi, 64); err == nil && in >= 0 {
+      h.Amount += in
+      h.Reported = true
+    }
+  }
+  return hours
+}
+
+// PrintRecentPrecip prints a table of the precipitation reported in
+// each hour today, with a running total (traces are shown but not
+// counted).  Without observations it falls back to yesterday's daily
+// total.
+func PrintRecentPrecip(obs *Conditions, w io.Writer) {
+  current := obs.Current_observation
+  if len(obs.today) == 0 {
+    if len(obs.History.Dailysummary) == 0 {
+      Fail(APIError, "No precipitation data available")
+    }
+    fmt.Fprintf(w, "No observations for today at %s; yesterday's total was %s in\n",
+      current.Station_id, obs.History.Dailysummary[0].Precipi)
+    return
+  }
+  fmt.Fprintf(w, "Precipitation today at %s (%s)\n", current.Observation_location.Full, current.Station_id)
+  tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+  fmt.Fprintln(tw, "   Hour\tPrecip (in)\tRunning Total")
+  total := 0.0
+  for _, h := range precipByHour(obs.today) {
+    amount := "-"
+    if h.Trace && h.Amount == 0 {
+      amount = "T"
+    } else if h.Reported {
+      amount = fmt.Sprintf("%.2f", h.Amount)
+    }
+    total += h.Amount
+    fmt.Fprintf(tw, "   %s:00\t%s\t%.2f\n", h.Hour, amount, total)
+  }
+  tw.Flush()
+}
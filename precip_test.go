@@ -0,0 +1,98 @@
+/*
+* precip_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --recent-precip (precip.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:40 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "math"
+  "strconv"
+  "strings"
+  "testing"
+)
+
+func TestPrecipByHour(t *testing.T) {
+  observation := func(hour, min, precipi string) Observations {
+    return Observations{Date: Date{Hour: hour, Min: min}, Precipi: precipi}
+  }
+  observations := []Observations{
+    observation("06", "53", "0.00"),
+    observation("07", "20", "0.05"),
+    observation("07", "53", "0.10"),
+    observation("08", "15", "T"),
+    observation("08", "53", "0.02"),
+    observation("09", "53", "T"),
+    observation("10", "53", "-9999.00"),
+  }
+  want := []hourPrecip{
+    {"06", 0, false, true},
+    {"07", 0.15, false, true},
+    {"08", 0.02, true, true},
+    {"09", 0, true, true},
+    {"10", 0, false, false},
+  }
+  got := precipByHour(observations)
+  if len(got) != len(want) {
+    t.Fatalf("precipByHour = %+v, want %+v", got, want)
+  }
+  for i := range want {
+    if got[i].Hour != want[i].Hour || math.Abs(got[i].Amount-want[i].Amount) > 1e-9 ||
+      got[i].Trace != want[i].Trace || got[i].Reported != want[i].Reported {
+      t.Errorf("hour %d = %+v, want %+v", i, got[i], want[i])
+    }
+  }
+}
+
+func TestPrintRecentPrecip(t *testing.T) {
+  var obs Conditions
+  obs.Current_observation.Station_id = "KLNK"
+  for i, p := range []string{"0.00", "0.12", "T", "0.30", "0.30", "0.05", "T", "0.00", "0.80"} {
+    obs.today = append(obs.today, Observations{Date: Date{Hour: strconv.Itoa(10 + i/2)}, Precipi: p})
+  }
+  var buf bytes.Buffer
+  PrintRecentPrecip(&obs, &buf)
+  lines := strings.Split(strings.TrimSpace(buf.String()), "\n")[2:]
+  if len(lines) != 5 {
+    t.Fatalf("PrintRecentPrecip printed %d hours, want 5:\n%s", len(lines), buf.String())
+  }
+  last := -1.0
+  for _, line := range lines {
+    fields := strings.Fields(line)
+    total, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+    if err != nil {
+      t.Fatalf("running total in %q: %v", line, err)
+    }
+    if total < last {
+      t.Errorf("running total fell from %.2f to %.2f:\n%s", last, total, buf.String())
+    }
+    last = total
+  }
+  if last != 1.57 {
+    t.Errorf("total = %.2f, want 1.57", last)
+  }
+}
@@ -0,0 +1,126 @@
+/*
+* pressure.go
+*
+* This file is part of wu.  It contains functions related to
+* the --conditions-pressure-forecast switch (forecasting from the
+* barometer).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 19:34:52 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "strconv"
+)
+
+// How fast (in inches of mercury per hour) the pressure must change to
+// count as changing rapidly, and how slowly to count as steady
+const (
+  rapidPressureChange  = 0.06
+  steadyPressureChange = 0.01
+)
+
+// pressureWindow is how far back -conditions-pressure-forecast looks
+const pressureWindow = 3
+
+// PressureTendency describes a change in pressure (in inches per hour)
+func PressureTendency(trend float64) string {
+  switch {
+  case trend <= -rapidPressureChange:
+    return "falling rapidly"
+  case trend <= -steadyPressureChange:
+    return "falling slowly"
+  case trend >= rapidPressureChange:
+    return "rising rapidly"
+  case trend >= steadyPressureChange:
+    return "rising slowly"
+  }
+  return "steady"
+}
+
+// PressureForecastText predicts the weather from the pressure (in
+// inches) and how fast it is changing (in inches per hour), the way a
+// ship's barometer is read: a rapid fall brings storms, a slow one
+// rain, a steady glass holds the weather, and a rise clears it
+func PressureForecastText(currentPressure, trend float64) string {
+  switch PressureTendency(trend) {
+  case "falling rapidly":
+    return "Storm likely within 24 hours"
+  case "falling slowly":
+    if currentPressure >= 30.2 {
+      return "Clouds increasing; rain possible within 24 hours"
+    }
+    return "Rain likely within 24 hours"
+  case "rising rapidly":
+    return "Weather improving quickly, becoming windy"
+  case "rising slowly":
+    return "Weather improving"
+  }
+  if currentPressure < 29.8 {
+    return "Unsettled weather continuing"
+  }
+  return "Fair weather continuing"
+}
+
+// PressureChange returns the change in pressure between baseline and
+// current (in inches), and over how many hours
+func PressureChange(current, baseline *Current) (float64, float64, bool) {
+  change, ok := delta(baseline.Pressure_in, current.Pressure_in)
+  now, ok1 := observationTime(current)
+  then, ok2 := observationTime(baseline)
+  hours := now.Sub(then).Hours()
+  return change, hours, ok && ok1 && ok2 && hours > 0
+}
+
+// PrintPressureForecast prints the forecast from the change in pressure
+// since baseline or, without one, from the API's pressure trend
+func PrintPressureForecast(current, baseline *Current, w io.Writer) {
+  pressure, err := strconv.ParseFloat(current.Pressure_in, 64)
+  if err != nil {
+    return
+  }
+  if baseline != nil {
+    if change, hours, ok := PressureChange(current, baseline); ok {
+      trend := change / hours
+      fmt.Fprintf(w, "   Pressure %s (%.2f inHg, %+.2f in past %.0fh): %s.\n",
+        PressureTendency(trend), pressure, change, math.Round(hours), PressureForecastText(pressure, trend))
+      return
+    }
+  }
+  // The API only says which way the pressure is going, so assume it is
+  // going that way slowly
+  var trend float64
+  switch current.Pressure_trend {
+  case "+":
+    trend = steadyPressureChange
+  case "-":
+    trend = -steadyPressureChange
+  case "0":
+  default:
+    return
+  }
+  fmt.Fprintf(w, "   Pressure %s (%.2f inHg): %s.\n", PressureTendency(trend), pressure, PressureForecastText(pressure, trend))
+}
@@ -0,0 +1,83 @@
+/*
+* pressure_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the -conditions-pressure-forecast switch (pressure.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:16:07 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "testing"
+)
+
+func TestPressureForecastText(t *testing.T) {
+  tests := []struct {
+    name     string
+    pressure float64
+    trend    float64
+    want     string
+  }{
+    {"rapid fall", 29.50, -0.06, "Storm likely within 24 hours"},
+    {"rapid fall, high pressure", 30.30, -0.10, "Storm likely within 24 hours"},
+    {"slow fall", 29.90, -0.03, "Rain likely within 24 hours"},
+    {"slow fall, high pressure", 30.25, -0.03, "Clouds increasing; rain possible within 24 hours"},
+    {"steady", 30.00, 0, "Fair weather continuing"},
+    {"steady, low pressure", 29.70, 0.005, "Unsettled weather continuing"},
+    {"slow rise", 29.90, 0.02, "Weather improving"},
+    {"rapid rise", 29.60, 0.08, "Weather improving quickly, becoming windy"},
+  }
+  for _, tt := range tests {
+    if got := PressureForecastText(tt.pressure, tt.trend); got != tt.want {
+      t.Errorf("%s: PressureForecastText(%v, %v) = %q, want %q", tt.name, tt.pressure, tt.trend, got, tt.want)
+    }
+  }
+}
+
+func TestPrintPressureForecast(t *testing.T) {
+  baseline := Current{Observation_epoch: "1378051200", Pressure_in: "29.68"}
+  tests := []struct {
+    name     string
+    current  Current
+    baseline *Current
+    want     string
+  }{
+    {"from the cache", Current{Observation_epoch: "1378062000", Pressure_in: "29.50"}, &baseline,
+      "   Pressure falling rapidly (29.50 inHg, -0.18 in past 3h): Storm likely within 24 hours.\n"},
+    {"from the API's trend", Current{Pressure_in: "29.95", Pressure_trend: "+"}, nil,
+      "   Pressure rising slowly (29.95 inHg): Weather improving.\n"},
+    {"steady by the API's trend", Current{Pressure_in: "30.05", Pressure_trend: "0"}, nil,
+      "   Pressure steady (30.05 inHg): Fair weather continuing.\n"},
+    {"no trend", Current{Pressure_in: "30.05"}, nil, ""},
+    {"no pressure", Current{Pressure_in: "NA", Pressure_trend: "-"}, nil, ""},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintPressureForecast(&tt.current, tt.baseline, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("%s: got %q, want %q", tt.name, buf.String(), tt.want)
+    }
+  }
+}
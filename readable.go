@@ -0,0 +1,127 @@
+/*
+* readable.go
+*
+* This file is part of wu.  It contains functions related to
+* the --readable switch (numbers and units spelled out for screen
+* readers and text-to-speech).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:36:27 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+var (
+  ones = []string{"zero", "one", "two", "three", "four", "five", "six",
+    "seven", "eight", "nine", "ten", "eleven", "twelve", "thirteen",
+    "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+  tens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty",
+    "seventy", "eighty", "ninety"}
+)
+
+// unitNames spells out the unit abbreviations wu prints
+var unitNames = map[string]string{
+  "F":    "degrees Fahrenheit",
+  "°F":   "degrees Fahrenheit",
+  "C":    "degrees Celsius",
+  "°C":   "degrees Celsius",
+  "K":    "kelvins",
+  "MPH":  "miles per hour",
+  "mph":  "miles per hour",
+  "KPH":  "kilometers per hour",
+  "km/h": "kilometers per hour",
+  "in":   "inches",
+  "mm":   "millimeters",
+  "mb":   "millibars",
+  "kPa":  "kilopascals",
+  "atm":  "atmospheres",
+  "mi":   "miles",
+  "km":   "kilometers",
+  "%":    " percent",
+}
+
+var (
+  unitPattern   = regexp.MustCompile(`(\d) ?(°F|°C|F|C|K|MPH|mph|KPH|km/h|kPa|atm|in|mm|mb|mi|km|%)(\W|$)`)
+  numberPattern = regexp.MustCompile(`-?\d+(\.\d+)?`)
+)
+
+// numberToWords spells out n, which should be between 0 and 9999
+func numberToWords(n int) string {
+  switch {
+  case n < 20:
+    return ones[n]
+  case n < 100:
+    if n%10 == 0 {
+      return tens[n/10]
+    }
+    return tens[n/10] + "-" + ones[n%10]
+  case n < 1000:
+    if n%100 == 0 {
+      return ones[n/100] + " hundred"
+    }
+    return ones[n/100] + " hundred " + numberToWords(n%100)
+  }
+  if n%1000 == 0 {
+    return numberToWords(n/1000) + " thousand"
+  }
+  return numberToWords(n/1000) + " thousand " + numberToWords(n%1000)
+}
+
+// spellNumber spells out a decimal number such as "-3.5" ("minus three
+// point five").  Numbers too large for numberToWords are left alone.
+func spellNumber(s string) string {
+  words, digits := "", s
+  if strings.HasPrefix(digits, "-") {
+    words, digits = "minus ", digits[1:]
+  }
+  parts := strings.SplitN(digits, ".", 2)
+  n, err := strconv.Atoi(parts[0])
+  if err != nil || n > 9999 {
+    return s
+  }
+  words += numberToWords(n)
+  if len(parts) == 2 {
+    words += " point"
+    for _, d := range parts[1] {
+      words += " " + ones[d-'0']
+    }
+  }
+  return words
+}
+
+// Readable spells out the numbers and units in s
+func Readable(s string) string {
+  s = unitPattern.ReplaceAllStringFunc(s, func(m string) string {
+    sub := unitPattern.FindStringSubmatch(m)
+    name := unitNames[sub[2]]
+    if !strings.HasPrefix(name, " ") {
+      name = " " + name
+    }
+    return sub[1] + name + sub[3]
+  })
+  return numberPattern.ReplaceAllStringFunc(s, spellNumber)
+}
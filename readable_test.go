@@ -0,0 +1,95 @@
+/*
+* readable_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the --readable switch (readable.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:16:48 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "testing"
+)
+
+func TestNumberToWords(t *testing.T) {
+  tests := []struct {
+    n    int
+    want string
+  }{
+    {0, "zero"},
+    {1, "one"},
+    {12, "twelve"},
+    {20, "twenty"},
+    {72, "seventy-two"},
+    {100, "one hundred"},
+    {101, "one hundred one"},
+    {1000, "one thousand"},
+    {1013, "one thousand thirteen"},
+    {2500, "two thousand five hundred"},
+    {9999, "nine thousand nine hundred ninety-nine"},
+  }
+  for _, tt := range tests {
+    if got := numberToWords(tt.n); got != tt.want {
+      t.Errorf("numberToWords(%d) = %q, want %q", tt.n, got, tt.want)
+    }
+  }
+}
+
+func TestSpellNumber(t *testing.T) {
+  tests := []struct {
+    s, want string
+  }{
+    {"72", "seventy-two"},
+    {"-3.5", "minus three point five"},
+    {"29.92", "twenty-nine point nine two"},
+    {"12345", "12345"},
+    {"-12345", "-12345"},
+  }
+  for _, tt := range tests {
+    if got := spellNumber(tt.s); got != tt.want {
+      t.Errorf("spellNumber(%q) = %q, want %q", tt.s, got, tt.want)
+    }
+  }
+}
+
+func TestReadable(t *testing.T) {
+  tests := []struct {
+    s, want string
+  }{
+    {"72 F (22 C)", "seventy-two degrees Fahrenheit (twenty-two degrees Celsius)"},
+    {"72°F", "seventy-two degrees Fahrenheit"},
+    {"From the NW at 12 MPH", "From the NW at twelve miles per hour"},
+    {"65%", "sixty-five percent"},
+    {"29.92 in (1013 mb)", "twenty-nine point nine two inches (one thousand thirteen millibars)"},
+    {"101.32 kPa", "one hundred one point three two kilopascals"},
+    {"1.000 atm", "one point zero zero zero atmospheres"},
+    {"10 miles", "ten miles"},
+    {"-5 C", "minus five degrees Celsius"},
+  }
+  for _, tt := range tests {
+    if got := Readable(tt.s); got != tt.want {
+      t.Errorf("Readable(%q) = %q, want %q", tt.s, got, tt.want)
+    }
+  }
+}
@@ -0,0 +1,129 @@
+/*
+* schema.go
+*
+* This file is part of wu.  It contains functions related to
+* the --json-schema switch (a JSON Schema for --format json).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:52:08 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "reflect"
+  "strings"
+  "time"
+)
+
+// schemaOperations are the keys that may appear under "data" in the
+// --format json output
+var schemaOperations = []string{
+  "airportinfo", "airquality", "alerts", "almanac", "astronomy", "conditions", "conditionsbadge",
+  "conditionsdiff", "conditionsepoch", "conditionshistory", "conditionstrend", "dewpointcomfort",
+  "emojisummary", "forecast", "forecast10day", "forecastbestday", "forecastclothing",
+  "forecastdelta", "forecasthighlow", "forecastpack", "forecastraintotal", "forecastsummary",
+  "forecasttravelindex", "forecastuvpeak", "forecastweekendscore", "geolookup", "history",
+  "historyanomaly", "hourly", "hourlyrainwindow", "localtime", "moonillumination", "moonphasetext",
+  "planner", "plannerconfidence", "plannerraindays", "pollen", "recentprecip", "stationinfo",
+  "stationlistcsv", "tide", "windrose", "yesterday", "yesterdaynormal", "yesterdayrainfall",
+}
+
+// GenerateSchema returns a JSON Schema for the value v, which is
+// walked by reflection.  Property names come from the json tags.  Go
+// doesn't keep comments around at run time, so fields carry no
+// descriptions.
+func GenerateSchema(v interface{}) map[string]interface{} {
+  if v == nil {
+    return map[string]interface{}{}
+  }
+  return schemaFor(reflect.TypeOf(v))
+}
+
+func schemaFor(t reflect.Type) map[string]interface{} {
+  if t == reflect.TypeOf(time.Time{}) {
+    return map[string]interface{}{"type": "string", "format": "date-time"}
+  }
+  switch t.Kind() {
+  case reflect.Ptr:
+    return schemaFor(t.Elem())
+  case reflect.String:
+    return map[string]interface{}{"type": "string"}
+  case reflect.Bool:
+    return map[string]interface{}{"type": "boolean"}
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+    reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+    return map[string]interface{}{"type": "integer"}
+  case reflect.Float32, reflect.Float64:
+    return map[string]interface{}{"type": "number"}
+  case reflect.Slice, reflect.Array:
+    return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+  case reflect.Map:
+    return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+  case reflect.Struct:
+    properties := make(map[string]interface{})
+    for i := 0; i < t.NumField(); i++ {
+      f := t.Field(i)
+      if f.PkgPath != "" {
+        continue
+      }
+      name := strings.Split(f.Tag.Get("json"), ",")[0]
+      if name == "-" {
+        continue
+      } else if name == "" {
+        name = f.Name
+      }
+      properties[name] = schemaFor(f.Type)
+    }
+    return map[string]interface{}{"type": "object", "properties": properties}
+  }
+  return map[string]interface{}{}
+}
+
+// PrintJSONSchema writes the schema of the --format json envelope
+func PrintJSONSchema(w io.Writer) error {
+  var obs Conditions
+  data := make(map[string]interface{})
+  for _, operation := range schemaOperations {
+    data[operation] = GenerateSchema(OperationData(&obs, operation))
+  }
+  schema := map[string]interface{}{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "title":   fmt.Sprintf("wu --format json output (schema version %d)", SchemaVersion),
+    "type":    "object",
+    "properties": map[string]interface{}{
+      "schema_version": map[string]interface{}{"type": "integer", "const": SchemaVersion},
+      "wu_version":     map[string]interface{}{"type": "string"},
+      "data":           map[string]interface{}{"type": "object", "properties": data},
+      "_timing":        GenerateSchema(TimingOutput{}),
+    },
+    "required": []string{"schema_version", "wu_version", "data"},
+  }
+  b, err := json.MarshalIndent(schema, "", "  ")
+  if err != nil {
+    return err
+  }
+  _, err = fmt.Fprintln(w, string(b))
+  return err
+}
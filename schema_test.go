@@ -0,0 +1,113 @@
+/*
+* schema_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the JSON Schema (schema.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:40 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "testing"
+  "time"
+)
+
+func TestPrintJSONSchema(t *testing.T) {
+  var buf bytes.Buffer
+  if err := PrintJSONSchema(&buf); err != nil {
+    t.Fatal(err)
+  }
+  var schema struct {
+    Schema     string `json:"$schema"`
+    Properties struct {
+      Data struct {
+        Properties map[string]struct {
+          Type       string                     `json:"type"`
+          Properties map[string]json.RawMessage `json:"properties"`
+        } `json:"properties"`
+      } `json:"data"`
+    } `json:"properties"`
+  }
+  if err := json.Unmarshal(buf.Bytes(), &schema); err != nil {
+    t.Fatalf("the schema isn't valid JSON: %v", err)
+  }
+  if schema.Schema != "http://json-schema.org/draft-07/schema#" {
+    t.Errorf("$schema = %q", schema.Schema)
+  }
+  data := schema.Properties.Data.Properties
+  tests := []struct {
+    operation, typ string
+  }{
+    {"conditions", "object"},
+    {"forecast", "object"},
+    {"alerts", "array"},
+  }
+  for _, tt := range tests {
+    op, ok := data[tt.operation]
+    if !ok {
+      t.Errorf("the schema has no %s property", tt.operation)
+    } else if op.Type != tt.typ {
+      t.Errorf("%s is a %q, want %q", tt.operation, op.Type, tt.typ)
+    }
+  }
+  if _, ok := data["conditions"].Properties["temp_f"]; !ok {
+    t.Error("conditions has no temp_f property")
+  }
+}
+
+func TestGenerateSchema(t *testing.T) {
+  type inner struct {
+    Name string `json:"name"`
+  }
+  type sample struct {
+    S      string         `json:"s"`
+    F      float64        `json:"f,omitempty"`
+    I      int64          `json:"i"`
+    B      bool           `json:"b"`
+    T      time.Time      `json:"t"`
+    L      []inner        `json:"l"`
+    M      map[string]int `json:"m"`
+    P      *inner         `json:"p"`
+    Skip   string         `json:"-"`
+    NoTag  string
+    hidden string
+  }
+  got, _ := json.Marshal(GenerateSchema(sample{}))
+  want := `{"properties":{` +
+    `"NoTag":{"type":"string"},` +
+    `"b":{"type":"boolean"},` +
+    `"f":{"type":"number"},` +
+    `"i":{"type":"integer"},` +
+    `"l":{"items":{"properties":{"name":{"type":"string"}},"type":"object"},"type":"array"},` +
+    `"m":{"additionalProperties":{"type":"integer"},"type":"object"},` +
+    `"p":{"properties":{"name":{"type":"string"}},"type":"object"},` +
+    `"s":{"type":"string"},` +
+    `"t":{"format":"date-time","type":"string"}},` +
+    `"type":"object"}`
+  if string(got) != want {
+    t.Errorf("GenerateSchema = %s\nwant %s", got, want)
+  }
+}
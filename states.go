@@ -0,0 +1,111 @@
+/*
+* states.go
+*
+* This file is part of wu.  It contains functions related to
+* "city, state" station names (US state abbreviations).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:31:05 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "strings"
+)
+
+// stateAbbreviations maps the (lower-case) names of US states,
+// the District of Columbia, and the territories to the postal
+// abbreviations the API expects
+var stateAbbreviations = map[string]string{
+  "alabama":                  "AL",
+  "alaska":                   "AK",
+  "arizona":                  "AZ",
+  "arkansas":                 "AR",
+  "california":               "CA",
+  "colorado":                 "CO",
+  "connecticut":              "CT",
+  "delaware":                 "DE",
+  "florida":                  "FL",
+  "georgia":                  "GA",
+  "hawaii":                   "HI",
+  "idaho":                    "ID",
+  "illinois":                 "IL",
+  "indiana":                  "IN",
+  "iowa":                     "IA",
+  "kansas":                   "KS",
+  "kentucky":                 "KY",
+  "louisiana":                "LA",
+  "maine":                    "ME",
+  "maryland":                 "MD",
+  "massachusetts":            "MA",
+  "michigan":                 "MI",
+  "minnesota":                "MN",
+  "mississippi":              "MS",
+  "missouri":                 "MO",
+  "montana":                  "MT",
+  "nebraska":                 "NE",
+  "nevada":                   "NV",
+  "new hampshire":            "NH",
+  "new jersey":               "NJ",
+  "new mexico":               "NM",
+  "new york":                 "NY",
+  "north carolina":           "NC",
+  "north dakota":             "ND",
+  "ohio":                     "OH",
+  "oklahoma":                 "OK",
+  "oregon":                   "OR",
+  "pennsylvania":             "PA",
+  "rhode island":             "RI",
+  "south carolina":           "SC",
+  "south dakota":             "SD",
+  "tennessee":                "TN",
+  "texas":                    "TX",
+  "utah":                     "UT",
+  "vermont":                  "VT",
+  "virginia":                 "VA",
+  "washington":               "WA",
+  "west virginia":            "WV",
+  "wisconsin":                "WI",
+  "wyoming":                  "WY",
+  "district of columbia":     "DC",
+  "american samoa":           "AS",
+  "guam":                     "GU",
+  "northern mariana islands": "MP",
+  "puerto rico":              "PR",
+  "us virgin islands":        "VI",
+  "virgin islands":           "VI",
+}
+
+// CityStatePath turns a city and state (e.g. "Santa Fe" and "New
+// Mexico") into the API's STATE/City form ("NM/Santa_Fe").  A state
+// that is neither a two-letter code nor a US state name (a Canadian
+// province or a country, say) is passed through as it is.
+func CityStatePath(city, state string) string {
+  state = strings.TrimSpace(state)
+  if len(state) == 2 {
+    state = strings.ToUpper(state)
+  } else if abbr, ok := stateAbbreviations[strings.ToLower(strings.Join(strings.Fields(state), " "))]; ok {
+    state = abbr
+  }
+  city = strings.Join(strings.Fields(city), "_")
+  return strings.Replace(state, " ", "_", -1) + "/" + city
+}
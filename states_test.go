@@ -0,0 +1,77 @@
+/*
+* states_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for turning city and state names into stations (states.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:07 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "strings"
+  "testing"
+)
+
+func TestCityStatePath(t *testing.T) {
+  tests := []struct {
+    city, state string
+    want        string
+  }{
+    {"New York", "NY", "NY/New_York"},
+    {"Lincoln", "ne", "NE/Lincoln"},
+    {"Albuquerque", "New Mexico", "NM/Albuquerque"},
+    {"Santa Fe", "new mexico", "NM/Santa_Fe"},
+    {"Charleston", "West  Virginia", "WV/Charleston"},
+    {"Fargo", "North Dakota", "ND/Fargo"},
+    {"Sioux Falls", "SOUTH DAKOTA", "SD/Sioux_Falls"},
+    {"Providence", "Rhode Island", "RI/Providence"},
+    {"Washington", "District of Columbia", "DC/Washington"},
+    {"San Juan", "Puerto Rico", "PR/San_Juan"},
+    {"Hagåtña", "Guam", "GU/Hagåtña"},
+    {"Salt Lake City", " Utah ", "UT/Salt_Lake_City"},
+    {"  Des   Moines ", "Iowa", "IA/Des_Moines"},
+    {"Toronto", "Ontario", "Ontario/Toronto"},
+    {"Paris", "France", "France/Paris"},
+    {"Mexico City", "Distrito Federal", "Distrito_Federal/Mexico_City"},
+  }
+  for _, tt := range tests {
+    if got := CityStatePath(tt.city, tt.state); got != tt.want {
+      t.Errorf("CityStatePath(%q, %q) = %q, want %q", tt.city, tt.state, got, tt.want)
+    }
+  }
+}
+
+func TestStateAbbreviations(t *testing.T) {
+  codes := make(map[string]bool)
+  for name, code := range stateAbbreviations {
+    if len(code) != 2 || code != strings.ToUpper(code) || name != strings.ToLower(name) {
+      t.Errorf("stateAbbreviations[%q] = %q", name, code)
+    }
+    codes[code] = true
+  }
+  // 50 states, DC, and five territories
+  if len(codes) != 56 {
+    t.Errorf("%d distinct codes, want 56", len(codes))
+  }
+}
@@ -0,0 +1,132 @@
+/*
+* syslog.go
+*
+* This file is part of wu.  It contains functions related to
+* the --format syslog and --syslog-host switches (RFC 5424
+* messages).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 17:08:44 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "net"
+  "os"
+  "sort"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Syslog facility and severity codes (RFC 5424, section 6.2.1)
+const (
+  facilityLocal0 = 16
+  severityInfo   = 6
+)
+
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// nilValue replaces empty header fields, as RFC 5424 requires
+func nilValue(s string) string {
+  if s == "" {
+    return "-"
+  }
+  return s
+}
+
+// RFC5424Line formats a single syslog message, stamped with the
+// current time and host name
+func RFC5424Line(facility, severity int, appName, procID, msgID, structuredData, msg string) string {
+  hostname, _ := os.Hostname()
+  line := fmt.Sprintf("<%d>1 %s %s %s %s %s %s", facility*8+severity,
+    time.Now().Format(time.RFC3339), nilValue(hostname), nilValue(appName),
+    nilValue(procID), nilValue(msgID), nilValue(structuredData))
+  if msg != "" {
+    line += " " + msg
+  }
+  return line
+}
+
+// conditionFields returns the simple (non-object) fields of the
+// current observation, keyed by their JSON names
+func conditionFields(current *Current) (map[string]string, error) {
+  b, err := json.Marshal(OperationData(&Conditions{Current_observation: *current}, "conditions"))
+  if err != nil {
+    return nil, err
+  }
+  var raw map[string]interface{}
+  if err := json.Unmarshal(b, &raw); err != nil {
+    return nil, err
+  }
+  fields := make(map[string]string)
+  for k, v := range raw {
+    switch v := v.(type) {
+    case string:
+      if v != "" {
+        fields[k] = v
+      }
+    case float64:
+      fields[k] = strconv.FormatFloat(v, 'f', -1, 64)
+    case bool:
+      fields[k] = strconv.FormatBool(v)
+    }
+  }
+  return fields, nil
+}
+
+// PrintSyslog writes one syslog message per field of the current
+// conditions to w, or to the UDP address host if one is given
+func PrintSyslog(obs *Conditions, stationId, host string, w io.Writer) error {
+  fields, err := conditionFields(&obs.Current_observation)
+  if err != nil {
+    return err
+  }
+  if host != "" {
+    conn, err := net.Dial("udp", host)
+    if err != nil {
+      return Classify(NetworkError, err)
+    }
+    defer conn.Close()
+    w = conn
+  }
+  names := make([]string, 0, len(fields))
+  for name := range fields {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  sd := fmt.Sprintf(`[origin station="%s"]`, sdEscaper.Replace(stationId))
+  pid := strconv.Itoa(os.Getpid())
+  for _, name := range names {
+    line := RFC5424Line(facilityLocal0, severityInfo, "wu", pid, name, sd, fields[name])
+    if host == "" {
+      line += "\n"
+    }
+    if _, err := io.WriteString(w, line); err != nil {
+      return err
+    }
+  }
+  return nil
+}
@@ -0,0 +1,121 @@
+/*
+* syslog_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --format syslog (syslog.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:40 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "net"
+  "os"
+  "regexp"
+  "strconv"
+  "strings"
+  "testing"
+  "time"
+)
+
+// rfc5424Header matches the header of an RFC 5424 message (section
+// 6): PRI, VERSION, TIMESTAMP, HOSTNAME, APP-NAME, PROCID, and MSGID,
+// then STRUCTURED-DATA and an optional MSG
+var rfc5424Header = regexp.MustCompile(`^<([0-9]{1,3})>1 (\S+) ([!-~]{1,255}) ([!-~]{1,48}) ([!-~]{1,128}) ([!-~]{1,32}) (-|\[.+?\])( .*)?$`)
+
+// checkRFC5424 checks line against the grammar, returning its PRI,
+// APP-NAME, PROCID, MSGID, STRUCTURED-DATA, and MSG
+func checkRFC5424(t *testing.T, line string) []string {
+  m := rfc5424Header.FindStringSubmatch(line)
+  if m == nil {
+    t.Fatalf("%q isn't an RFC 5424 message", line)
+  }
+  if _, err := time.Parse(time.RFC3339, m[2]); err != nil {
+    t.Errorf("%q: timestamp %q isn't RFC 3339", line, m[2])
+  }
+  return []string{m[1], m[4], m[5], m[6], m[7], strings.TrimPrefix(m[8], " ")}
+}
+
+func TestRFC5424Line(t *testing.T) {
+  tests := []struct {
+    appName, procID, msgID, sd, msg string
+    want                            []string
+  }{
+    {"wu", "123", "temp_f", `[origin station="KLNK"]`, "72.5", []string{"134", "wu", "123", "temp_f", `[origin station="KLNK"]`, "72.5"}},
+    {"wu", "", "", "", "", []string{"134", "wu", "-", "-", "-", ""}},
+  }
+  for _, tt := range tests {
+    got := checkRFC5424(t, RFC5424Line(facilityLocal0, severityInfo, tt.appName, tt.procID, tt.msgID, tt.sd, tt.msg))
+    for i := range got {
+      if got[i] != tt.want[i] {
+        t.Errorf("RFC5424Line field %d = %q, want %q", i, got[i], tt.want[i])
+      }
+    }
+  }
+}
+
+func TestPrintSyslog(t *testing.T) {
+  obs := &Conditions{Current_observation: Current{Station_id: "KLNK", Weather: "Clear", Temp_f: "72.5"}}
+  var buf bytes.Buffer
+  if err := PrintSyslog(obs, `K"LNK`, "", &buf); err != nil {
+    t.Fatal(err)
+  }
+  pid := strconv.Itoa(os.Getpid())
+  msgs := make(map[string]string)
+  for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+    f := checkRFC5424(t, line)
+    if f[0] != "134" || f[1] != "wu" || f[2] != pid || f[4] != `[origin station="K\"LNK"]` {
+      t.Errorf("%q: header fields %q", line, f)
+    }
+    msgs[f[3]] = f[5]
+  }
+  for field, want := range map[string]string{"station_id": "KLNK", "weather": "Clear", "temp_f": "72.5"} {
+    if msgs[field] != want {
+      t.Errorf("message %s = %q, want %q", field, msgs[field], want)
+    }
+  }
+}
+
+func TestPrintSyslogUDP(t *testing.T) {
+  conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+  if err != nil {
+    t.Skip("can't listen for UDP:", err)
+  }
+  defer conn.Close()
+  obs := &Conditions{Current_observation: Current{Station_id: "KLNK"}}
+  if err := PrintSyslog(obs, "KLNK", conn.LocalAddr().String(), nil); err != nil {
+    t.Fatal(err)
+  }
+  conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+  b := make([]byte, 2048)
+  n, _, err := conn.ReadFrom(b)
+  if err != nil {
+    t.Fatal(err)
+  }
+  msg := string(b[:n])
+  if strings.HasSuffix(msg, "\n") {
+    t.Errorf("UDP message %q ends with a newline", msg)
+  }
+  checkRFC5424(t, msg)
+}
@@ -29,29 +29,33 @@
 package main
 
 import (
+  "errors"
   "fmt"
+  "io"
   "os"
+  "sort"
   "strconv"
+  "strings"
   "time"
 )
 
 type Tide struct {
-  Tideinfo    []Tideinfo
-  Tidesummary []Tidesummary
+  Tideinfo    []Tideinfo    `json:"tideinfo"`
+  Tidesummary []Tidesummary `json:"tidesummary"`
 }
 
 type Tideinfo struct {
-  Tidesite string
+  Tidesite string `json:"tidesite"`
 }
 
 type Tidesummary struct {
-  Date Date // Defined in wu.go
-  Data Data
+  Date Date `json:"date"` // Defined in wu.go
+  Data Data `json:"data"`
 }
 
 type Data struct {
-  Height string
-  Type   string
+  Height string `json:"height"`
+  Type   string `json:"type"`
 }
 
 // printTides prints the tidal data for given station to standard out
@@ -86,3 +90,48 @@ func PrintTides(obs *Conditions, stationID string) {
     }
   }
 }
+
+// NextTideEvent returns the first high or low tide after now
+func NextTideEvent(obs *Conditions, now time.Time) (*Tidesummary, error) {
+  upcoming := make([]Tidesummary, 0)
+  for _, s := range obs.Tide.Tidesummary {
+    if !strings.HasSuffix(s.Data.Type, " Tide") {
+      continue
+    }
+    epoch, err := strconv.ParseInt(s.Date.Epoch, 10, 64)
+    if err == nil && epoch > now.Unix() {
+      upcoming = append(upcoming, s)
+    }
+  }
+  if len(upcoming) == 0 {
+    return nil, errors.New("No upcoming tides.")
+  }
+  sort.Slice(upcoming, func(i, j int) bool {
+    a, _ := strconv.ParseInt(upcoming[i].Date.Epoch, 10, 64)
+    b, _ := strconv.ParseInt(upcoming[j].Date.Epoch, 10, 64)
+    return a < b
+  })
+  return &upcoming[0], nil
+}
+
+// PrintNextTide prints the next high or low tide and how long until it
+func PrintNextTide(obs *Conditions, now time.Time, w io.Writer) {
+  next, err := NextTideEvent(obs, now)
+  if err != nil {
+    fmt.Fprintln(w, err)
+    return
+  }
+  epoch, _ := strconv.ParseInt(next.Date.Epoch, 10, 64)
+  until := time.Unix(epoch, 0).Sub(now)
+  hour, _ := strconv.Atoi(next.Date.Hour)
+  ampm := "AM"
+  if hour >= 12 {
+    ampm = "PM"
+  }
+  if hour = hour % 12; hour == 0 {
+    hour = 12
+  }
+  kind := strings.ToUpper(strings.TrimSuffix(next.Data.Type, " Tide"))
+  fmt.Fprintf(w, "Next tide: %s at %d:%s %s (height: %s) — in %dh %dm\n", kind, hour, next.Date.Min, ampm,
+    next.Data.Height, int(until.Hours()), int(until.Minutes())%60)
+}
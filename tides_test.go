@@ -0,0 +1,82 @@
+/*
+* tides_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for tides (tides.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:29 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "strconv"
+  "testing"
+  "time"
+)
+
+// tideEvent returns a tide summary entry for t
+func tideEvent(t time.Time, kind, height string) Tidesummary {
+  var s Tidesummary
+  s.Date.Epoch = strconv.FormatInt(t.Unix(), 10)
+  s.Date.Hour = strconv.Itoa(t.Hour())
+  s.Date.Min = t.Format("04")
+  s.Data.Type = kind
+  s.Data.Height = height
+  return s
+}
+
+func TestNextTideEvent(t *testing.T) {
+  now := time.Date(2023, time.June, 1, 13, 27, 0, 0, time.UTC)
+  var obs Conditions
+  obs.Tide.Tidesummary = []Tidesummary{
+    tideEvent(now.Add(-6*time.Hour), "High Tide", "5.1 ft"),
+    tideEvent(now.Add(-30*time.Minute), "Low Tide", "0.4 ft"),
+    tideEvent(now.Add(8*time.Hour), "Low Tide", "0.6 ft"),
+    tideEvent(now.Add(time.Hour), "Sunset", ""),
+    tideEvent(now.Add(2*time.Hour+15*time.Minute), "High Tide", "5.3 ft"),
+    tideEvent(now.Add(14*time.Hour), "High Tide", "5.0 ft"),
+  }
+  next, err := NextTideEvent(&obs, now)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if next.Data.Type != "High Tide" || next.Data.Height != "5.3 ft" {
+    t.Errorf("NextTideEvent = %s (%s), want the 5.3 ft high tide", next.Data.Type, next.Data.Height)
+  }
+
+  var buf bytes.Buffer
+  PrintNextTide(&obs, now, &buf)
+  if want := "Next tide: HIGH at 3:42 PM (height: 5.3 ft) — in 2h 15m\n"; buf.String() != want {
+    t.Errorf("PrintNextTide = %q, want %q", buf.String(), want)
+  }
+}
+
+func TestNextTideEventNoneUpcoming(t *testing.T) {
+  now := time.Date(2023, time.June, 1, 13, 27, 0, 0, time.UTC)
+  var obs Conditions
+  obs.Tide.Tidesummary = []Tidesummary{tideEvent(now.Add(-time.Hour), "High Tide", "5.1 ft")}
+  if next, err := NextTideEvent(&obs, now); err == nil {
+    t.Errorf("NextTideEvent = %+v, want an error", next)
+  }
+}
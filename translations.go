@@ -0,0 +1,231 @@
+/*
+* translations.go
+*
+* This file is part of wu.  It contains functions related to
+* the --conditions-translate switch (translated sky conditions).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 19:47:03 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "sort"
+  "strings"
+)
+
+// translations maps each language -conditions-translate supports (by
+// ISO 639-1 code) to the translations of the sky conditions the API
+// reports (e.g. "Partly Cloudy")
+var translations = map[string]map[string]string{
+  "es": { // Spanish
+    "Clear":                  "Despejado",
+    "Sunny":                  "Soleado",
+    "Mostly Sunny":           "Mayormente soleado",
+    "Partly Sunny":           "Parcialmente soleado",
+    "Partly Cloudy":          "Parcialmente nublado",
+    "Mostly Cloudy":          "Mayormente nublado",
+    "Scattered Clouds":       "Nubes dispersas",
+    "Overcast":               "Cubierto",
+    "Cloudy":                 "Nublado",
+    "Fog":                    "Niebla",
+    "Haze":                   "Calima",
+    "Mist":                   "Neblina",
+    "Rain":                   "Lluvia",
+    "Drizzle":                "Llovizna",
+    "Rain Showers":           "Chubascos",
+    "Thunderstorm":           "Tormenta",
+    "Thunderstorms and Rain": "Tormentas con lluvia",
+    "Snow":                   "Nieve",
+    "Snow Showers":           "Chubascos de nieve",
+    "Sleet":                  "Aguanieve",
+    "Freezing Rain":          "Lluvia helada",
+    "Ice Pellets":            "Hielo granulado",
+    "Hail":                   "Granizo",
+    "Blowing Snow":           "Ventisca",
+    "Smoke":                  "Humo",
+    "Dust":                   "Polvo",
+    "Squalls":                "Turbonadas",
+    "Funnel Cloud":           "Nube embudo",
+  },
+  "fr": { // French
+    "Clear":                  "Dégagé",
+    "Sunny":                  "Ensoleillé",
+    "Mostly Sunny":           "Plutôt ensoleillé",
+    "Partly Sunny":           "Partiellement ensoleillé",
+    "Partly Cloudy":          "Partiellement nuageux",
+    "Mostly Cloudy":          "Plutôt nuageux",
+    "Scattered Clouds":       "Nuages épars",
+    "Overcast":               "Couvert",
+    "Cloudy":                 "Nuageux",
+    "Fog":                    "Brouillard",
+    "Haze":                   "Brume sèche",
+    "Mist":                   "Brume",
+    "Rain":                   "Pluie",
+    "Drizzle":                "Bruine",
+    "Rain Showers":           "Averses",
+    "Thunderstorm":           "Orage",
+    "Thunderstorms and Rain": "Orages et pluie",
+    "Snow":                   "Neige",
+    "Snow Showers":           "Averses de neige",
+    "Sleet":                  "Neige fondue",
+    "Freezing Rain":          "Pluie verglaçante",
+    "Ice Pellets":            "Grésil",
+    "Hail":                   "Grêle",
+    "Blowing Snow":           "Neige soufflée",
+    "Smoke":                  "Fumée",
+    "Dust":                   "Poussière",
+    "Squalls":                "Grains",
+    "Funnel Cloud":           "Nuage en entonnoir",
+  },
+  "de": { // German
+    "Clear":                  "Klar",
+    "Sunny":                  "Sonnig",
+    "Mostly Sunny":           "Überwiegend sonnig",
+    "Partly Sunny":           "Teilweise sonnig",
+    "Partly Cloudy":          "Teilweise bewölkt",
+    "Mostly Cloudy":          "Überwiegend bewölkt",
+    "Scattered Clouds":       "Aufgelockerte Bewölkung",
+    "Overcast":               "Bedeckt",
+    "Cloudy":                 "Bewölkt",
+    "Fog":                    "Nebel",
+    "Haze":                   "Dunst",
+    "Mist":                   "Feuchter Dunst",
+    "Rain":                   "Regen",
+    "Drizzle":                "Nieselregen",
+    "Rain Showers":           "Regenschauer",
+    "Thunderstorm":           "Gewitter",
+    "Thunderstorms and Rain": "Gewitter mit Regen",
+    "Snow":                   "Schnee",
+    "Snow Showers":           "Schneeschauer",
+    "Sleet":                  "Schneeregen",
+    "Freezing Rain":          "Gefrierender Regen",
+    "Ice Pellets":            "Eiskörner",
+    "Hail":                   "Hagel",
+    "Blowing Snow":           "Schneetreiben",
+    "Smoke":                  "Rauch",
+    "Dust":                   "Staub",
+    "Squalls":                "Böen",
+    "Funnel Cloud":           "Trichterwolke",
+  },
+  "pt": { // Portuguese
+    "Clear":                  "Limpo",
+    "Sunny":                  "Ensolarado",
+    "Mostly Sunny":           "Predominantemente ensolarado",
+    "Partly Sunny":           "Parcialmente ensolarado",
+    "Partly Cloudy":          "Parcialmente nublado",
+    "Mostly Cloudy":          "Predominantemente nublado",
+    "Scattered Clouds":       "Nuvens dispersas",
+    "Overcast":               "Encoberto",
+    "Cloudy":                 "Nublado",
+    "Fog":                    "Nevoeiro",
+    "Haze":                   "Névoa seca",
+    "Mist":                   "Neblina",
+    "Rain":                   "Chuva",
+    "Drizzle":                "Chuvisco",
+    "Rain Showers":           "Aguaceiros",
+    "Thunderstorm":           "Trovoada",
+    "Thunderstorms and Rain": "Trovoadas com chuva",
+    "Snow":                   "Neve",
+    "Snow Showers":           "Aguaceiros de neve",
+    "Sleet":                  "Chuva com neve",
+    "Freezing Rain":          "Chuva congelante",
+    "Ice Pellets":            "Pelotas de gelo",
+    "Hail":                   "Granizo",
+    "Blowing Snow":           "Neve soprada",
+    "Smoke":                  "Fumaça",
+    "Dust":                   "Poeira",
+    "Squalls":                "Rajadas",
+    "Funnel Cloud":           "Nuvem funil",
+  },
+  "it": { // Italian
+    "Clear":                  "Sereno",
+    "Sunny":                  "Soleggiato",
+    "Mostly Sunny":           "Prevalentemente soleggiato",
+    "Partly Sunny":           "Parzialmente soleggiato",
+    "Partly Cloudy":          "Parzialmente nuvoloso",
+    "Mostly Cloudy":          "Prevalentemente nuvoloso",
+    "Scattered Clouds":       "Nubi sparse",
+    "Overcast":               "Coperto",
+    "Cloudy":                 "Nuvoloso",
+    "Fog":                    "Nebbia",
+    "Haze":                   "Foschia",
+    "Mist":                   "Nebbia leggera",
+    "Rain":                   "Pioggia",
+    "Drizzle":                "Pioviggine",
+    "Rain Showers":           "Rovesci",
+    "Thunderstorm":           "Temporale",
+    "Thunderstorms and Rain": "Temporali con pioggia",
+    "Snow":                   "Neve",
+    "Snow Showers":           "Rovesci di neve",
+    "Sleet":                  "Nevischio",
+    "Freezing Rain":          "Pioggia gelata",
+    "Ice Pellets":            "Granuli di ghiaccio",
+    "Hail":                   "Grandine",
+    "Blowing Snow":           "Neve trasportata dal vento",
+    "Smoke":                  "Fumo",
+    "Dust":                   "Polvere",
+    "Squalls":                "Groppi",
+    "Funnel Cloud":           "Nube a imbuto",
+  },
+}
+
+// intensities maps each language to how to say that a condition is
+// light or heavy (as in "Light Rain"), as a format for the condition's
+// own translation.  The words are ones that needn't agree with the
+// condition's gender.
+var intensities = map[string]map[string]string{
+  "es": {"Light": "%s débil", "Heavy": "%s fuerte"},
+  "fr": {"Light": "%s de faible intensité", "Heavy": "%s de forte intensité"},
+  "de": {"Light": "%s (leicht)", "Heavy": "%s (stark)"},
+  "pt": {"Light": "%s de fraca intensidade", "Heavy": "%s de forte intensidade"},
+  "it": {"Light": "%s debole", "Heavy": "%s forte"},
+}
+
+// translationLanguages returns the languages -conditions-translate
+// accepts
+func translationLanguages() []string {
+  langs := make([]string, 0, len(translations))
+  for lang := range translations {
+    langs = append(langs, lang)
+  }
+  sort.Strings(langs)
+  return langs
+}
+
+// translateCondition translates a sky condition into lang, or if it
+// can't, returns it in English with a note saying so.  A "Light" or
+// "Heavy" condition is translated as the condition and its intensity.
+func translateCondition(condition, lang string) string {
+  if t, ok := translations[lang][condition]; ok {
+    return t
+  }
+  for intensity, format := range intensities[lang] {
+    if base := strings.TrimPrefix(condition, intensity+" "); base != condition {
+      if t, ok := translations[lang][base]; ok {
+        return fmt.Sprintf(format, t)
+      }
+    }
+  }
+  return condition + " (untranslated)"
+}
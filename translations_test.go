@@ -0,0 +1,76 @@
+/*
+* translations_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the -conditions-translate switch (translations.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:20:03 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "testing"
+)
+
+func TestTranslateCondition(t *testing.T) {
+  tests := []struct {
+    condition, lang, want string
+  }{
+    {"Sunny", "es", "Soleado"},
+    {"Partly Cloudy", "es", "Parcialmente nublado"},
+    {"Overcast", "es", "Cubierto"},
+    {"Fog", "es", "Niebla"},
+    {"Light Rain", "es", "Lluvia débil"},
+    {"Heavy Snow", "es", "Nieve fuerte"},
+    {"Sunny", "de", "Sonnig"},
+    {"Partly Cloudy", "de", "Teilweise bewölkt"},
+    {"Overcast", "de", "Bedeckt"},
+    {"Fog", "de", "Nebel"},
+    {"Light Rain", "de", "Regen (leicht)"},
+    {"Heavy Thunderstorms and Rain", "de", "Gewitter mit Regen (stark)"},
+    {"Volcanic Ash", "es", "Volcanic Ash (untranslated)"},
+    {"Light Volcanic Ash", "de", "Light Volcanic Ash (untranslated)"},
+    {"Sunny", "xx", "Sunny (untranslated)"},
+  }
+  for _, tt := range tests {
+    if got := translateCondition(tt.condition, tt.lang); got != tt.want {
+      t.Errorf("translateCondition(%q, %q) = %q, want %q", tt.condition, tt.lang, got, tt.want)
+    }
+  }
+}
+
+func TestTranslationsComplete(t *testing.T) {
+  for lang, table := range translations {
+    if len(table) != len(translations["es"]) {
+      t.Errorf("%s has %d conditions, want %d", lang, len(table), len(translations["es"]))
+    }
+    for condition := range translations["es"] {
+      if _, ok := table[condition]; !ok {
+        t.Errorf("%s has no translation of %q", lang, condition)
+      }
+    }
+    if _, ok := intensities[lang]; !ok {
+      t.Errorf("%s has no intensities", lang)
+    }
+  }
+}
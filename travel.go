@@ -0,0 +1,126 @@
+/*
+* travel.go
+*
+* This file is part of wu.  It contains functions related to
+* the --forecast-travel-index switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 15:49:26 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "strings"
+)
+
+// travelModes are the modes -forecast-travel-index accepts (besides
+// "all"), in the order they are printed
+var travelModes = []string{"flying", "driving", "cycling"}
+
+// visibility guesses how far one can see from a forecast icon, from
+// 0 (not far) to 1, since the forecast itself has no visibility
+func visibility(icon string) float64 {
+  switch strings.TrimPrefix(strings.TrimPrefix(icon, "nt_"), "chance") {
+  case "fog", "hazy", "snow", "sleet":
+    return 0
+  case "rain", "tstorms", "flurries":
+    return 0.5
+  }
+  return 1
+}
+
+// TravelScore rates a forecast day from 0 to 100 for travelling by
+// mode: flying suffers most from strong winds (turbulence) and storms,
+// driving from poor visibility, ice, and snow, and cycling from
+// temperatures far from 65 F, wind, and rain
+func TravelScore(day Simpleforecastday, mode string) int {
+  high, _ := day.High.Fahrenheit.Float()
+  low, _ := day.Low.Fahrenheit.Float()
+  pop, _ := day.Pop.Float()
+  wind, _ := day.Avewind.Mph.Float()
+  gust, _ := day.Maxwind.Mph.Float()
+  dry := 1 - pop/100
+  sky := iconCategory(day.Icon)
+  var score float64
+  switch mode {
+  case "flying":
+    calm := math.Max(0, 1-math.Max(wind, gust)/40)
+    clear := 1.0
+    if sky == skySnow || strings.HasSuffix(day.Icon, "tstorms") {
+      clear = 0
+    }
+    score = 0.5*calm + 0.3*dry + 0.2*clear
+  case "driving":
+    ice := 1.0
+    if sky == skySnow {
+      ice = 0
+    } else if low <= 32 && pop > 0 {
+      ice = 0.5
+    }
+    score = 0.35*visibility(day.Icon) + 0.35*ice + 0.3*dry
+  case "cycling":
+    temp := math.Max(0, 1-math.Abs(high-65)/30)
+    calm := math.Max(0, 1-wind/25)
+    score = 0.3*temp + 0.3*calm + 0.4*dry
+  }
+  return int(math.Round(100 * score))
+}
+
+// TravelLabel describes a TravelScore
+func TravelLabel(score int) string {
+  switch {
+  case score >= 90:
+    return "Excellent"
+  case score >= 70:
+    return "Good"
+  case score >= 50:
+    return "Fair"
+  case score >= 20:
+    return "Poor"
+  }
+  return "Dangerous"
+}
+
+// PrintTravelIndex prints the travel score for mode (or, for "all",
+// every travel mode) for each day of the forecast
+func PrintTravelIndex(obs *Conditions, mode string, w io.Writer) {
+  days := obs.Forecast.Simpleforecast.Forecastday
+  if len(days) == 0 {
+    fmt.Fprintln(w, "No forecast available.")
+    return
+  }
+  modes := travelModes
+  if mode != "all" {
+    modes = []string{mode}
+  }
+  for _, d := range days {
+    scores := make([]string, len(modes))
+    for i, m := range modes {
+      score := TravelScore(d, m)
+      scores[i] = fmt.Sprintf("%s %d (%s)", strings.ToUpper(m[:1])+m[1:], score, TravelLabel(score))
+    }
+    fmt.Fprintf(w, "%s: %s\n", d.Date.Weekday, strings.Join(scores, ", "))
+  }
+}
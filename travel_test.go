@@ -0,0 +1,115 @@
+/*
+* travel_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the -forecast-travel-index switch (travel.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:14:58 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "strings"
+  "testing"
+)
+
+func TestTravelScore(t *testing.T) {
+  days := forecastDays(forecastMonday, dayIcons("clear", "snow"), dayHighs("68", "20"), dayLows("50", "10"),
+    dayPops("0", "90"), dayWinds("5", "35"), dayGusts("10", "50"))
+  clear, blizzard := days[0], days[1]
+  for _, mode := range travelModes {
+    if got := TravelScore(clear, mode); got <= 80 {
+      t.Errorf("TravelScore(clear day, %q) = %d, want above 80", mode, got)
+    }
+    if got := TravelScore(blizzard, mode); got >= 20 {
+      t.Errorf("TravelScore(blizzard, %q) = %d, want below 20", mode, got)
+    }
+  }
+}
+
+func TestTravelLabel(t *testing.T) {
+  tests := []struct {
+    score int
+    want  string
+  }{
+    {100, "Excellent"},
+    {90, "Excellent"},
+    {89, "Good"},
+    {70, "Good"},
+    {69, "Fair"},
+    {50, "Fair"},
+    {49, "Poor"},
+    {20, "Poor"},
+    {19, "Dangerous"},
+    {0, "Dangerous"},
+  }
+  for _, tt := range tests {
+    if got := TravelLabel(tt.score); got != tt.want {
+      t.Errorf("TravelLabel(%d) = %q, want %q", tt.score, got, tt.want)
+    }
+  }
+}
+
+func TestPrintTravelIndex(t *testing.T) {
+  var obs Conditions
+  obs.Forecast.Simpleforecast.Forecastday = forecastDays(forecastMonday, dayIcons("clear"), dayHighs("68"), dayLows("50"),
+    dayPops("0"), dayWinds("5"), dayGusts("10"))
+  tests := []struct {
+    mode string
+    want string
+  }{
+    {"all", "Monday: Flying 88 (Good), Driving 100 (Excellent), Cycling 91 (Excellent)\n"},
+    {"driving", "Monday: Driving 100 (Excellent)\n"},
+  }
+  for _, tt := range tests {
+    var buf bytes.Buffer
+    PrintTravelIndex(&obs, tt.mode, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("PrintTravelIndex(%q) = %q, want %q", tt.mode, buf.String(), tt.want)
+    }
+  }
+}
+
+func TestTravelIndexMode(t *testing.T) {
+  tests := []struct {
+    args []string
+    ok   bool
+  }{
+    {[]string{"-forecast-travel-index"}, true},
+    {[]string{"-forecast-travel-index", "cycling"}, true},
+    {[]string{"-forecast-travel-index", "all"}, true},
+    {[]string{"-forecast-travel-index", "boating"}, false},
+    {[]string{"-forecast-travel-index", "flying", "driving"}, false},
+  }
+  for _, tt := range tests {
+    args := append([]string{"-script-mode", "-s", "KLNK"}, tt.args...)
+    status, _, stderr := runOptions(t, args...)
+    if tt.ok && status != 0 {
+      t.Errorf("%v: status %d, %q", tt.args, status, stderr)
+    }
+    if !tt.ok && (status != int(InvalidInput) || !strings.Contains(stderr, "Usage: wu -forecast-travel-index")) {
+      t.Errorf("%v: status %d, %q, want a usage error", tt.args, status, stderr)
+    }
+  }
+}
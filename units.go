@@ -0,0 +1,217 @@
+/*
+* units.go
+*
+* This file is part of wu.  It contains functions related to
+* the --metric, --temperature-unit, --precipitation-unit, and
+* --pressure-unit switches (units of measurement).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 15:41:09 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "strconv"
+  "strings"
+)
+
+// UnitSystem is the system of units measurements are shown in
+type UnitSystem int
+
+const (
+  BothUnits    UnitSystem = iota // imperial, with metric in parentheses
+  MetricOnly                     // -metric or -conditions-metric-only
+  ImperialOnly                   // -conditions-imperial-only
+)
+
+// Units holds the unit chosen for each kind of measurement.  An empty
+// field means "show both" (e.g. 72 F (22 C)), which is wu's default.
+type Units struct {
+  System        UnitSystem // for wind speeds and visibility
+  Temperature   string     // "f", "c", or "k"
+  Precipitation string     // "in" or "mm"
+  Pressure      string     // "inhg", "mb", "kpa", or "atm"
+  Locale        *Localizer
+  Readable      bool // spell out numbers and units (--readable)
+}
+
+// NewUnits returns the units of system
+func NewUnits(system UnitSystem) Units {
+  switch system {
+  case MetricOnly:
+    return Units{System: system, Temperature: "c", Precipitation: "mm", Pressure: "mb"}
+  case ImperialOnly:
+    return Units{System: system, Temperature: "f", Precipitation: "in", Pressure: "inhg"}
+  }
+  return Units{}
+}
+
+// Value is a measurement that the API reports sometimes as a JSON
+// string and sometimes as a JSON number.
+type Value string
+
+func (v *Value) UnmarshalJSON(b []byte) error {
+  *v = Value(strings.Trim(string(b), "\""))
+  return nil
+}
+
+// Float returns the value as a number, and false if it isn't one
+func (v Value) Float() (float64, bool) {
+  f, err := strconv.ParseFloat(string(v), 64)
+  return f, err == nil
+}
+
+// Temp formats a temperature given in Fahrenheit and Celsius
+func (u *Units) Temp(f, c string) string {
+  switch u.Temperature {
+  case "f":
+    return u.Number(f + " F")
+  case "c":
+    return u.Number(c + " C")
+  case "k":
+    if k, err := strconv.ParseFloat(c, 64); err == nil {
+      return u.Number(fmt.Sprintf("%.1f K", k+273.15))
+    }
+    return u.Number(c + " C")
+  }
+  return u.Number(fmt.Sprintf("%s F (%s C)", f, c))
+}
+
+// Degrees formats a temperature in Fahrenheit (such as one wu has
+// computed) with the given number of decimal places
+func (u *Units) Degrees(f float64, decimals int) string {
+  return u.Temp(strconv.FormatFloat(f, 'f', decimals, 64), strconv.FormatFloat((f-32)*5/9, 'f', decimals, 64))
+}
+
+// Difference formats a difference between two temperatures given in
+// Fahrenheit degrees (so, unlike Degrees, without the 32 offset)
+func (u *Units) Difference(f float64, decimals int) string {
+  c := f * 5 / 9
+  format := func(v float64) string { return strconv.FormatFloat(v, 'f', decimals, 64) }
+  switch u.Temperature {
+  case "f":
+    return u.Number(format(f) + " F")
+  case "c":
+    return u.Number(format(c) + " C")
+  case "k":
+    return u.Number(format(c) + " K")
+  }
+  return u.Number(fmt.Sprintf("%s F (%s C)", format(f), format(c)))
+}
+
+// Precip formats a precipitation amount given in inches and millimeters
+func (u *Units) Precip(in, mm string) string {
+  switch u.Precipitation {
+  case "in":
+    return u.Number(in + " in")
+  case "mm":
+    return u.Number(mm + " mm")
+  }
+  return u.Number(fmt.Sprintf("%s in (%s mm)", in, mm))
+}
+
+const (
+  kPaPerInHg = 3.386389
+  mbPerInHg  = 33.86389
+  inHgPerAtm = 29.9213
+)
+
+// InHgToKPa converts a pressure in inches of mercury to kilopascals
+func InHgToKPa(inHg float64) float64 {
+  return inHg * kPaPerInHg
+}
+
+// InHgToAtm converts a pressure in inches of mercury to atmospheres
+func InHgToAtm(inHg float64) float64 {
+  return inHg / inHgPerAtm
+}
+
+// Press formats a barometric pressure given in inches of mercury and
+// millibars
+func (u *Units) Press(in, mb string) string {
+  switch u.Pressure {
+  case "inhg":
+    return u.Number(in + " in")
+  case "mb":
+    return u.Number(mb + " mb")
+  case "kpa", "atm":
+    inHg, err := strconv.ParseFloat(in, 64)
+    if err != nil {
+      return u.Number(in + " in")
+    }
+    if u.Pressure == "kpa" {
+      return u.Number(fmt.Sprintf("%.2f kPa", InHgToKPa(inHg)))
+    }
+    return u.Number(fmt.Sprintf("%.3f atm", InHgToAtm(inHg)))
+  }
+  return u.Number(fmt.Sprintf("%s in (%s mb)", in, mb))
+}
+
+// WindDescription describes the current wind as the API puts it (in
+// mph), or with km/h too or instead unless the system is ImperialOnly
+func (u *Units) WindDescription(current *Current) string {
+  mph, ok1 := current.Wind_mph.Float()
+  kph, ok2 := current.Wind_kph.Float()
+  if u.System == ImperialOnly || !ok1 || !ok2 || kph == 0 {
+    return u.Number(current.Wind_string)
+  }
+  speed := func(mph, kph float64) string {
+    if u.System == MetricOnly {
+      return fmt.Sprintf("%.1f km/h", kph)
+    }
+    return fmt.Sprintf("%.1f mph (%.1f km/h)", mph, kph)
+  }
+  s := fmt.Sprintf("From the %s at %s", current.Wind_dir, speed(mph, kph))
+  gustMph, ok1 := current.Wind_gust_mph.Float()
+  gustKph, ok2 := current.Wind_gust_kph.Float()
+  if ok1 && ok2 && gustKph > 0 {
+    s += " Gusting to " + speed(gustMph, gustKph)
+  }
+  return u.Number(s)
+}
+
+// Visibility formats the visibility in miles, kilometers, or both
+func (u *Units) Visibility(current *Current) string {
+  km := string(current.Visibility_km)
+  switch {
+  case isNullish(km) || u.System == ImperialOnly:
+    return u.Number(current.Visibility_mi + " miles")
+  case u.System == MetricOnly || isNullish(current.Visibility_mi):
+    return u.Number(km + " km")
+  }
+  return u.Number(current.Visibility_mi + " miles (" + km + " km)")
+}
+
+// Number formats the numbers in s for the locale (if any), or spells
+// them out for --readable
+func (u *Units) Number(s string) string {
+  if u.Readable {
+    return Readable(s)
+  }
+  return u.Locale.FormatNumbers(s)
+}
+
+// Metric reports whether temperatures should be shown in metric units
+func (u *Units) Metric() bool {
+  return u.Temperature == "c" || u.Temperature == "k"
+}
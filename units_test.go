@@ -0,0 +1,129 @@
+/*
+* units_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for unit conversion and formatting (units.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:54 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "math"
+  "strings"
+  "testing"
+)
+
+func TestInHgConversions(t *testing.T) {
+  tests := []struct {
+    inHg, kPa, atm float64
+  }{
+    {29.9213, 101.325, 1},     // the standard atmosphere (1013.25 mb)
+    {29.92, 101.321, 0.99996}, // as stations round it
+    {0, 0, 0},
+    {31.00, 104.978, 1.03605}, // about the highest ever recorded
+    {25.69, 86.996, 0.85859},  // about the lowest (Typhoon Tip)
+  }
+  for _, tt := range tests {
+    if kPa := InHgToKPa(tt.inHg); math.Abs(kPa-tt.kPa) > 0.001 {
+      t.Errorf("InHgToKPa(%v) = %.4f, want %.3f", tt.inHg, kPa, tt.kPa)
+    }
+    if atm := InHgToAtm(tt.inHg); math.Abs(atm-tt.atm) > 0.001 {
+      t.Errorf("InHgToAtm(%v) = %.5f, want %.5f", tt.inHg, atm, tt.atm)
+    }
+    // Round trips back to inches
+    if back := InHgToKPa(tt.inHg) / kPaPerInHg; math.Abs(back-tt.inHg) > 0.001 {
+      t.Errorf("kPa round trip of %v = %v", tt.inHg, back)
+    }
+    if back := InHgToAtm(tt.inHg) * inHgPerAtm; math.Abs(back-tt.inHg) > 0.001 {
+      t.Errorf("atm round trip of %v = %v", tt.inHg, back)
+    }
+  }
+  if mb := InHgToKPa(29.9213) * 10; math.Abs(mb-1013.25) > 0.01 {
+    t.Errorf("the standard atmosphere is %.2f mb, want 1013.25", mb)
+  }
+}
+
+func TestPress(t *testing.T) {
+  tests := []struct {
+    pressure string
+    in, mb   string
+    want     string
+  }{
+    {"", "29.92", "1013", "29.92 in (1013 mb)"},
+    {"inhg", "29.92", "1013", "29.92 in"},
+    {"mb", "29.92", "1013", "1013 mb"},
+    {"kpa", "29.92", "1013", "101.32 kPa"},
+    {"atm", "29.92", "1013", "1.000 atm"},
+    {"kpa", "NA", "1013", "NA in"},
+  }
+  for _, tt := range tests {
+    u := Units{Pressure: tt.pressure}
+    if got := u.Press(tt.in, tt.mb); got != tt.want {
+      t.Errorf("Press(%q, %q) in %q = %q, want %q", tt.in, tt.mb, tt.pressure, got, tt.want)
+    }
+  }
+}
+
+func TestUnitSystems(t *testing.T) {
+  current := Current{Temp_f: "72.3", Temp_c: "22.4", Pressure_in: "29.92", Pressure_mb: "1013",
+    Wind_string: "From the SSW at 12.0 MPH Gusting to 20.0 MPH", Wind_dir: "SSW",
+    Wind_mph: "12.0", Wind_kph: "19.3", Wind_gust_mph: "20.0", Wind_gust_kph: "32.2",
+    Visibility_mi: "10.0", Visibility_km: "16.1"}
+  measurements := func(u Units) []string {
+    return []string{u.Temp(string(current.Temp_f), string(current.Temp_c)), u.WindDescription(&current),
+      u.Press(current.Pressure_in, current.Pressure_mb), u.Visibility(&current)}
+  }
+  tests := []struct {
+    system UnitSystem
+    want   []string
+  }{
+    {BothUnits, []string{"72.3 F (22.4 C)", "From the SSW at 12.0 mph (19.3 km/h) Gusting to 20.0 mph (32.2 km/h)",
+      "29.92 in (1013 mb)", "10.0 miles (16.1 km)"}},
+    {MetricOnly, []string{"22.4 C", "From the SSW at 19.3 km/h Gusting to 32.2 km/h", "1013 mb", "16.1 km"}},
+    {ImperialOnly, []string{"72.3 F", "From the SSW at 12.0 MPH Gusting to 20.0 MPH", "29.92 in", "10.0 miles"}},
+  }
+  for _, tt := range tests {
+    got := measurements(NewUnits(tt.system))
+    if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+      t.Errorf("system %d = %q, want %q", tt.system, got, tt.want)
+    }
+  }
+  for _, m := range measurements(NewUnits(MetricOnly)) {
+    for _, imperial := range []string{" F", "mph", "MPH", " in", "miles"} {
+      if strings.Contains(m, imperial) {
+        t.Errorf("MetricOnly %q has %q", m, imperial)
+      }
+    }
+  }
+}
+
+func TestWindDescriptionCalm(t *testing.T) {
+  current := Current{Wind_string: "Calm", Wind_mph: "0.0", Wind_kph: "0"}
+  for _, system := range []UnitSystem{BothUnits, MetricOnly, ImperialOnly} {
+    u := NewUnits(system)
+    if got := u.WindDescription(&current); got != "Calm" {
+      t.Errorf("system %d: WindDescription = %q, want Calm", system, got)
+    }
+  }
+}
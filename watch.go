@@ -0,0 +1,111 @@
+/*
+* watch.go
+*
+* This file is part of wu.  It contains functions related to
+* the --watch-alert switch (polling for new and cleared alerts).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:51:40 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "context"
+  "fmt"
+  "io"
+  "os"
+  "os/signal"
+  "time"
+)
+
+// alertKey identifies an alert from one poll to the next
+func alertKey(a *Alerts) string {
+  if a.Message_id != "" {
+    return a.Message_id
+  }
+  return a.Description + "|" + a.Date
+}
+
+// fetchAlerts retrieves the active alerts for stationId, returning
+// any error so that a watch can ride out a dropped connection
+func fetchAlerts(stationId string) ([]Alerts, error) {
+  b, err := Fetch(BuildURL([]string{"alerts"}, stationId))
+  if err != nil {
+    return nil, err
+  }
+  var obs Conditions
+  if err := parseJSON(b, &obs); err != nil {
+    return nil, err
+  }
+  return obs.Alerts, obs.Response.Err()
+}
+
+// WatchAlerts polls fetch every interval until ctx is done, printing
+// each alert the first time it appears (with a timestamp) and a notice
+// when one that was seen goes away
+func WatchAlerts(ctx context.Context, interval time.Duration, fetch func() ([]Alerts, error), w io.Writer) {
+  seen := make(map[string]Alerts)
+  ticker := time.NewTicker(interval)
+  defer ticker.Stop()
+  for {
+    alerts, err := fetch()
+    if err != nil {
+      fmt.Fprintln(os.Stderr, "Alert check failed:", err)
+    } else {
+      current := make(map[string]Alerts)
+      for _, a := range alerts {
+        key := alertKey(&a)
+        current[key] = a
+        if _, ok := seen[key]; !ok {
+          fmt.Fprintf(w, "[%s] New alert: %s (issued %s, expires %s)\n%s\n",
+            time.Now().Format("2006-01-02 15:04:05"), a.Description, a.Date, a.Expires, FormatAlertText(&a))
+        }
+      }
+      for key, a := range seen {
+        if _, ok := current[key]; !ok {
+          fmt.Fprintf(w, "[%s] Alert cleared: %s\n", time.Now().Format("2006-01-02 15:04:05"), a.Description)
+        }
+      }
+      seen = current
+    }
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+    }
+  }
+}
+
+// watchAlerts runs WatchAlerts for --watch-alert until interrupted
+func watchAlerts(stationId string) {
+  ctx, cancel := context.WithCancel(context.Background())
+  interrupt := make(chan os.Signal, 1)
+  signal.Notify(interrupt, os.Interrupt)
+  go func() {
+    <-interrupt
+    cancel()
+  }()
+  fmt.Printf("Watching for alerts at %s every %d seconds (Ctrl-C to stop)\n", stationId, watchinterval)
+  WatchAlerts(ctx, time.Duration(watchinterval)*time.Second, func() ([]Alerts, error) {
+    return fetchAlerts(stationId)
+  }, os.Stdout)
+}
@@ -0,0 +1,111 @@
+/*
+* watch_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for --watch-alert (watch.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:13:37 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "context"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestWatchAlerts(t *testing.T) {
+  responses := []string{
+    `{"alerts": [{"description": "Severe Thunderstorm Warning", "message_id": "NE-001",
+      "date": "3:05 PM CDT", "expires": "4:00 PM CDT", "message": "Take cover."}]}`,
+    `{"alerts": []}`,
+  }
+  polls := 0
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    w.Write([]byte(responses[polls]))
+    polls++
+  }))
+  defer srv.Close()
+  url := apiURL
+  t.Cleanup(func() { apiURL = url })
+  apiURL = srv.URL + "/api/"
+
+  ctx, cancel := context.WithCancel(context.Background())
+  var buf bytes.Buffer
+  WatchAlerts(ctx, time.Millisecond, func() ([]Alerts, error) {
+    alerts, err := fetchAlerts("KLNK")
+    if polls == len(responses) {
+      cancel()
+    }
+    return alerts, err
+  }, &buf)
+
+  lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+  if len(lines) != 3 {
+    t.Fatalf("WatchAlerts printed %d lines, want 3:\n%s", len(lines), buf.String())
+  }
+  if !strings.Contains(lines[0], "] New alert: Severe Thunderstorm Warning (issued 3:05 PM CDT, expires 4:00 PM CDT)") ||
+    !strings.HasPrefix(lines[0], "[") {
+    t.Errorf("new alert line = %q", lines[0])
+  }
+  if lines[1] != "Take cover." {
+    t.Errorf("alert text = %q", lines[1])
+  }
+  if !strings.HasSuffix(lines[2], "] Alert cleared: Severe Thunderstorm Warning") {
+    t.Errorf("cleared alert line = %q", lines[2])
+  }
+}
+
+func TestWatchAlertsQuietWhenUnchanged(t *testing.T) {
+  alert := Alerts{Description: "Heat Advisory", Message_id: "NE-002"}
+  polls := 0
+  ctx, cancel := context.WithCancel(context.Background())
+  var buf bytes.Buffer
+  WatchAlerts(ctx, time.Millisecond, func() ([]Alerts, error) {
+    if polls++; polls == 3 {
+      cancel()
+    }
+    return []Alerts{alert}, nil
+  }, &buf)
+  if n := strings.Count(buf.String(), "New alert"); n != 1 {
+    t.Errorf("an unchanged alert was reported %d times:\n%s", n, buf.String())
+  }
+}
+
+func TestFetchAlertsQuotaExceeded(t *testing.T) {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    w.WriteHeader(http.StatusTooManyRequests)
+  }))
+  defer srv.Close()
+  url := apiURL
+  t.Cleanup(func() { apiURL = url })
+  apiURL = srv.URL + "/api/"
+
+  if _, err := fetchAlerts("KLNK"); codeOf(err) != QuotaExceeded {
+    t.Errorf("fetchAlerts on a 429 returned %v (code %d), want QuotaExceeded", err, codeOf(err))
+  }
+}
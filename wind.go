@@ -0,0 +1,195 @@
+/*
+* wind.go
+*
+* This file is part of wu.  It contains functions related to
+* wind chill and the --wind-chill-advisory switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 16:05:44 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "os"
+  "strconv"
+  "strings"
+)
+
+// WindChill returns the NWS wind chill for a temperature (F) and wind
+// speed (mph), and false when wind chill is undefined (temperatures
+// above 50 F or winds below 3 mph)
+func WindChill(tempF, windMph float64) (float64, bool) {
+  if tempF > 50 || windMph < 3 {
+    return 0, false
+  }
+  v := math.Pow(windMph, 0.16)
+  return 35.74 + 0.6215*tempF - 35.75*v + 0.4275*tempF*v, true
+}
+
+// currentWindChill returns the wind chill for the current observation,
+// or the reason there isn't one
+func currentWindChill(current *Current) (float64, string) {
+  temp, tOk := current.Temp_f.Float()
+  wind, wOk := current.Wind_mph.Float()
+  if !tOk || !wOk {
+    return 0, "temperature or wind speed unavailable"
+  }
+  chill, ok := WindChill(temp, wind)
+  if !ok {
+    return 0, "wind chill is undefined above 50°F or below 3 mph"
+  }
+  return chill, ""
+}
+
+// WindChillExceeded reports whether the wind chill for the current
+// observation is below limit (without printing anything)
+func WindChillExceeded(current *Current, limit float64) bool {
+  chill, reason := currentWindChill(current)
+  return reason == "" && chill < limit
+}
+
+// CheckWindChill exits with status 2 if the wind chill for the current
+// observation is below the --wind-chill-advisory limit
+func CheckWindChill(current *Current, limit float64) {
+  chill, reason := currentWindChill(current)
+  if reason != "" {
+    fmt.Fprintln(os.Stderr, "Wind chill advisory skipped: "+reason)
+    return
+  }
+  if chill < limit {
+    fmt.Fprintf(os.Stderr, "⚠ Wind chill is %.0f°F (threshold: %.0f°F)\n", chill, limit)
+    os.Exit(2)
+  }
+}
+
+// currentGust returns the gust speed for the current observation in
+// mph (or km/h if metric), and false if the station reports none
+func currentGust(current *Current, metric bool) (float64, bool) {
+  gust := current.Wind_gust_mph
+  if metric {
+    gust = current.Wind_gust_kph
+  }
+  speed, ok := gust.Float()
+  return speed, ok && speed > 0
+}
+
+// GustExceeded reports whether the current gust speed is above
+// threshold (without printing anything)
+func GustExceeded(current *Current, threshold float64, metric bool) bool {
+  gust, ok := currentGust(current, metric)
+  return ok && gust > threshold
+}
+
+// CheckGusts exits with status 2 if the current gust speed is above
+// the --gust-warning threshold
+func CheckGusts(current *Current, threshold float64, metric bool) {
+  if GustExceeded(current, threshold, metric) {
+    gust, _ := currentGust(current, metric)
+    unit := "mph"
+    if metric {
+      unit = "km/h"
+    }
+    fmt.Fprintf(os.Stderr, "⚠ Wind gusts of %.0f %s detected (threshold: %.0f %s)\n", gust, unit, threshold, unit)
+    os.Exit(2)
+  }
+}
+
+// beaufortLimits are the highest wind speeds (mph) of each force on the
+// Beaufort scale below 12 (hurricane force)
+var beaufortLimits = []float64{1, 3, 7, 12, 18, 24, 31, 38, 46, 54, 63, 72}
+
+// Beaufort returns the Beaufort force (0-12) of a wind speed in mph
+func Beaufort(mph float64) int {
+  for force, limit := range beaufortLimits {
+    if mph < limit+0.5 {
+      return force
+    }
+  }
+  return 12
+}
+
+// compassPoints are the eight points of the wind rose, clockwise from
+// north, with the row and column of each on the rose
+var compassPoints = []struct {
+  name     string
+  row, col int
+}{
+  {"N", 0, 4}, {"NE", 1, 6}, {"E", 2, 7}, {"SE", 3, 6},
+  {"S", 4, 4}, {"SW", 3, 1}, {"W", 2, 1}, {"NW", 1, 1},
+}
+
+// compassPoint returns the index in compassPoints of the point nearest
+// degrees
+func compassPoint(degrees float64) int {
+  return int(math.Mod(math.Mod(degrees, 360)+360+22.5, 360) / 45)
+}
+
+// windRose draws an eight-point compass, 9 characters wide, with the
+// point nearest degrees marked *LIKE THIS* and speed in the middle
+func windRose(degrees float64, speed string) string {
+  const width = 9
+  rows := make([][]rune, 5)
+  for i := range rows {
+    rows[i] = []rune(strings.Repeat(" ", width))
+  }
+  put := func(row, col int, s string) {
+    for i, r := range []rune(s) {
+      if c := col + i; c >= 0 && c < width {
+        rows[row][c] = r
+      }
+    }
+  }
+  for _, p := range compassPoints {
+    put(p.row, p.col, p.name)
+  }
+  p := compassPoints[compassPoint(degrees)]
+  put(p.row, p.col-1, "*"+p.name+"*")
+  if len(speed) > 3 {
+    speed = speed[:3]
+  }
+  put(2, 3+(3-len(speed))/2, speed)
+  lines := make([]string, len(rows))
+  for i, r := range rows {
+    lines[i] = strings.TrimRight(string(r), " ")
+  }
+  return strings.Join(lines, "\n") + "\n"
+}
+
+// PrintWindRose prints the wind rose for the current wind
+func PrintWindRose(obs *Conditions, metric bool, w io.Writer) {
+  current := obs.Current_observation
+  degrees, ok := current.Wind_degrees.Float()
+  if !ok || degrees < 0 {
+    fmt.Fprintln(w, "Wind direction unavailable.")
+    return
+  }
+  speed, unit := current.Wind_mph, "mph"
+  if metric {
+    speed, unit = current.Wind_kph, "km/h"
+  }
+  s, _ := speed.Float()
+  fmt.Fprintf(w, "Wind from the %s at %.0f %s\n", compassPoints[compassPoint(degrees)].name, s, unit)
+  fmt.Fprint(w, windRose(degrees, strconv.FormatFloat(math.Round(s), 'f', 0, 64)))
+}
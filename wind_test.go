@@ -0,0 +1,192 @@
+/*
+* wind_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for wind chill, gusts, and the wind rose (wind.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:12:59 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "errors"
+  "math"
+  "os"
+  "os/exec"
+  "strings"
+  "testing"
+  "unicode/utf8"
+)
+
+// Reference values are from the NWS wind chill chart
+func TestWindChill(t *testing.T) {
+  tests := []struct {
+    temp, wind float64
+    want       float64
+    defined    bool
+  }{
+    {40, 5, 36, true},
+    {30, 10, 21, true},
+    {0, 15, -19, true},
+    {-10, 30, -39, true},
+    {-45, 60, -98, true},
+    {20, 3, 16, true},
+    {51, 20, 0, false},
+    {20, 2.9, 0, false},
+  }
+  for _, tt := range tests {
+    got, ok := WindChill(tt.temp, tt.wind)
+    if ok != tt.defined {
+      t.Errorf("WindChill(%v, %v) defined = %v, want %v", tt.temp, tt.wind, ok, tt.defined)
+      continue
+    }
+    if ok && math.Round(got) != tt.want {
+      t.Errorf("WindChill(%v, %v) = %.1f, want %v", tt.temp, tt.wind, got, tt.want)
+    }
+  }
+}
+
+func TestWindChillExceeded(t *testing.T) {
+  tests := []struct {
+    temp, wind string
+    limit      float64
+    want       bool
+  }{
+    {"0", "15", -10, true},
+    {"0", "15", -20, false},
+    {"60", "15", 100, false},
+    {"NA", "15", 100, false},
+  }
+  for _, tt := range tests {
+    current := &Current{Temp_f: Value(tt.temp), Wind_mph: Value(tt.wind)}
+    if got := WindChillExceeded(current, tt.limit); got != tt.want {
+      t.Errorf("WindChillExceeded(%s F, %s mph, %v) = %v, want %v", tt.temp, tt.wind, tt.limit, got, tt.want)
+    }
+  }
+}
+
+// gustHelperEnv, when set, makes TestGustHelper check a gust of that
+// many mph against a -gust-warning of 40, so that the exit status can
+// be checked from another process
+const gustHelperEnv = "WU_TEST_GUST"
+
+func TestGustHelper(t *testing.T) {
+  gust, ok := os.LookupEnv(gustHelperEnv)
+  if !ok {
+    return
+  }
+  CheckGusts(&Current{Wind_gust_mph: Value(gust)}, 40, false)
+  os.Exit(0)
+}
+
+func TestCheckGusts(t *testing.T) {
+  tests := []struct {
+    gust   string
+    status int
+    stderr string
+  }{
+    {"", 0, ""},
+    {"0", 0, ""},
+    {"35.0", 0, ""},
+    {"40.0", 0, ""},
+    {"45.0", 2, "⚠ Wind gusts of 45 mph detected (threshold: 40 mph)\n"},
+  }
+  for _, tt := range tests {
+    cmd := exec.Command(os.Args[0], "-test.run=^TestGustHelper$")
+    cmd.Env = append(os.Environ(), gustHelperEnv+"="+tt.gust)
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+    err := cmd.Run()
+    status := 0
+    var exit *exec.ExitError
+    if errors.As(err, &exit) {
+      status = exit.ExitCode()
+    } else if err != nil {
+      t.Fatal(err)
+    }
+    if status != tt.status || stderr.String() != tt.stderr {
+      t.Errorf("gust %q: status %d, stderr %q; want %d, %q", tt.gust, status, stderr.String(), tt.status, tt.stderr)
+    }
+  }
+}
+
+func TestGustExceededMetric(t *testing.T) {
+  current := Current{Wind_gust_mph: "30.0", Wind_gust_kph: "48.3"}
+  if GustExceeded(&current, 40, false) {
+    t.Error("a 30 mph gust exceeded 40 mph")
+  }
+  if !GustExceeded(&current, 40, true) {
+    t.Error("a 48.3 km/h gust didn't exceed 40 km/h")
+  }
+}
+
+func TestWindRose(t *testing.T) {
+  tests := []struct {
+    degrees float64
+    want    string
+  }{
+    {0, "N"},
+    {45, "NE"},
+    {90, "E"},
+    {135, "SE"},
+    {180, "S"},
+    {225, "SW"},
+    {270, "W"},
+    {315, "NW"},
+    {337.4, "NW"},
+    {337.5, "N"},
+    {22.4, "N"},
+    {22.5, "NE"},
+    {360, "N"},
+    {-45, "NW"},
+  }
+  for _, tt := range tests {
+    rose := windRose(tt.degrees, "12")
+    lines := strings.Split(strings.TrimSuffix(rose, "\n"), "\n")
+    if len(lines) > 9 {
+      t.Errorf("windRose(%v) is %d lines tall", tt.degrees, len(lines))
+    }
+    for _, line := range lines {
+      if utf8.RuneCountInString(line) > 9 {
+        t.Errorf("windRose(%v) has a line %q wider than 9", tt.degrees, line)
+      }
+    }
+    if strings.Count(rose, "*") != 2 || !strings.Contains(rose, "*"+tt.want+"*") {
+      t.Errorf("windRose(%v) doesn't highlight just %s:\n%s", tt.degrees, tt.want, rose)
+    }
+    if !strings.Contains(lines[2], "12") {
+      t.Errorf("windRose(%v) has no speed in the middle:\n%s", tt.degrees, rose)
+    }
+  }
+}
+
+func TestWindChillAdvisoryInvalid(t *testing.T) {
+  status, _, stderr := runOptions(t, "-script-mode", "-wind-chill-advisory", "cold")
+  if status != int(InvalidInput) || !strings.Contains(stderr, "Usage: wu -wind-chill-advisory") {
+    t.Errorf("-script-mode -wind-chill-advisory cold: status %d, %q", status, stderr)
+  }
+  if status, stdout, _ := runOptions(t, "-wind-chill-advisory", "cold"); status != 0 || !strings.HasPrefix(stdout, "Usage: wu -wind-chill-advisory") {
+    t.Errorf("-wind-chill-advisory cold: status %d, %q", status, stdout)
+  }
+}
@@ -30,51 +30,191 @@ package main
 
 import (
   "encoding/json"
+  "errors"
   "flag"
   "fmt"
+  "io"
   "io/ioutil"
   "net/http"
+  "net/url"
   "os"
   "regexp"
+  "strconv"
   "strings"
+  "time"
 )
 
 type Config struct {
-  Key     string
-  Station string
+  Key     string `json:"key"`
+  Station string `json:"station"`
+  Units   string `json:"units,omitempty"`
+  Locale  string `json:"locale,omitempty"`
 }
 
 var (
-  help         bool
-  version      bool
-  doall        bool
-  doalmanac    bool
-  doalerts     bool
-  doconditions bool
-  dolookup     bool
-  doforecast   bool
-  doforecast10 bool
-  doastro      bool
-  doyesterday  bool
-  dotides      bool
-  dohistory    string
-  doplanner    string
-  date         string
-  conf         Config
+  help             bool
+  version          bool
+  doall            bool
+  doalmanac        bool
+  doalmanacdetail  bool
+  doanomaly        bool
+  docondhistory    bool
+  doalerts         bool
+  doalertdetail    bool
+  dopager          bool
+  watchalert       bool
+  watchinterval    int
+  doconditions     bool
+  doreporttime     bool
+  doemoji          bool
+  dowindrose       bool
+  omitzero         bool
+  doepoch          bool
+  dodewcomfort     bool
+  dowide           bool
+  dounitsall       bool
+  dotrend          bool
+  dodiffprevious   bool
+  dochart          bool
+  dohumidex        bool
+  doheatindex      bool
+  translatelang    string
+  dopressurefcst   bool
+  dorecentprecip   bool
+  pwscalibration   bool
+  trendwindow      int
+  dolookup         bool
+  dostationinfo    bool
+  dostationdist    bool
+  doairport        bool
+  doforecast       bool
+  doforecast10     bool
+  dohighlow        bool
+  dosummary        bool
+  dopack           bool
+  doclothing       bool
+  dostationcsv     bool
+  notifydesktop    bool
+  dobadge          bool
+  dodecade         bool
+  domissing        bool
+  douvpeak         bool
+  doyestnormal     bool
+  dorainwindow     bool
+  metriconly       bool
+  imperialonly     bool
+  doairquality     bool
+  dopollen         bool
+  pollenallergen   string
+  latflag          string
+  lonflag          string
+  doraintotal      bool
+  dobestday        string
+  dotravel         bool
+  travelmode       string
+  doweekend        bool
+  doweekendscore   bool
+  doforecastdelta  bool
+  donight          bool
+  doday            bool
+  doconfidencepop  bool
+  doeventday       string
+  eventday         time.Time // the parsed -forecast-event-day
+  dofreezing       bool
+  freezewarn       bool
+  dohourly         bool
+  hourlynext       int
+  dohourlynext     bool
+  doastro          bool
+  doyesterday      bool
+  doyestrain       bool
+  precipformat     string
+  dotides          bool
+  dotidesnext      bool
+  doastrodetail    bool
+  domoonillum      bool
+  domoontext       bool
+  quiet            bool
+  timing           bool
+  cron             bool
+  verbose          bool
+  exitonalert      int
+  scriptmode       bool
+  apitest          bool
+  doaddalias       bool
+  doschema         bool
+  dojsonschema     bool
+  format           string
+  jsonpath         string
+  jsonindent       int
+  noindent         bool
+  jsoncompact      bool
+  htmltheme        string
+  export           string
+  sysloghost       string
+  csvheader        bool
+  dohistory        string
+  dopercentile     bool
+  doplanner        string
+  docompare        bool
+  doconfidence     bool
+  dorainydays      bool
+  dohistrange      string
+  icalfile         string
+  dohistplot       bool
+  dohistplotprecip bool
+  doheatmap        string
+  dofreezedates    string
+  dosnowfall       string
+  dorecordrain     string
+  uptimedays       int
+  historymode      bool
+  historyrate      int
+  onweather        weatherHooks
+  dosince          string
+  since            time.Time // the parsed -conditions-since
+  doweekdayavg     bool
+  doextremes       bool
+  metric           bool
+  tempunit         string
+  filtercond       string
+  invertfilter     bool
+  rainrisk         int
+  limit            int
+  forecastdetail   string
+  precipunit       string
+  pressunit        string
+  locale           string
+  readable         bool
+  windchilladv     string
+  windchilllimit   float64
+  gustwarn         float64
+  agewarn          int
+  date             string
+  conf             Config
+  noconf           bool
+  confErr          error
+  doconfiginit     bool
+  units            Units
 )
 
 // Struct common to several data streams
 type Date struct {
-  Pretty string
-  Hour   string
-  Min    string
-  Mon    string
-  Mday   string
-  Year   string
+  Pretty string `json:"pretty"`
+  Hour   string `json:"hour"`
+  Min    string `json:"min"`
+  Mon    string `json:"mon"`
+  Mday   string `json:"mday"`
+  Year   string `json:"year"`
+  Epoch  string `json:"epoch"`
 }
 
 const defaultStation = "KLNK"
 
+// apiURL is where requests are sent (a variable so that tests can
+// use a local server)
+var apiURL = "http://api.wunderground.com/api/"
+
 // GetVersion returns the version of the package
 func GetVersion() string {
   return "3.9.7"
@@ -84,15 +224,30 @@ func GetVersion() string {
 // the configuration file at $HOME/.condrc
 func ReadConf() {
 
-  if b, err := ioutil.ReadFile(os.Getenv("HOME") + "/.condrc"); err == nil {
-    jsonErr := json.Unmarshal(b, &conf)
-    CheckError(jsonErr)
+  if b, err := ioutil.ReadFile(ConfFile()); err == nil {
+    // A malformed file is only reported once the options are parsed,
+    // so that -config-init can replace it
+    confErr = json.Unmarshal(b, &conf)
   } else {
-    fmt.Println("You must create a .condrc file in $HOME.")
-    os.Exit(0)
+    noconf = true
   }
 }
 
+// historyFlags are the switches for the reports that fetch history
+// themselves rather than through weather() (see historymode)
+var historyFlags = map[string]bool{
+  "history-range":        true,
+  "history-extremes":     true,
+  "history-heatmap":      true,
+  "history-freeze-dates": true,
+  "history-snowfall":     true,
+  "history-record-rain":  true,
+  "station-uptime":       true,
+  "conditions-since":     true,
+  "almanac-decade":       true,
+  "compare-planner":      true,
+}
+
 // Options handles commandline options and returns a 
 // possibly updated weather station string
 func Options() string {
@@ -106,30 +261,353 @@ func Options() string {
   }
 
   flag.BoolVar(&doconditions, "conditions", false, "Reports the current weather conditions")
+  flag.BoolVar(&dounitsall, "conditions-units-all", false, "Reports the current conditions in every unit (F, C, and K; mph, km/h, and Beaufort; ...)")
+  flag.BoolVar(&dowide, "conditions-wide", false, "Reports the current conditions in two columns on wide terminals")
+  flag.BoolVar(&dodewcomfort, "dew-point-comfort", false, "Prints the dew point and how comfortable it feels")
+  flag.BoolVar(&doepoch, "conditions-epoch", false, "Prints only the time of the current observation as a Unix timestamp")
+  flag.BoolVar(&omitzero, "omit-zero", false, "Leaves out conditions the station reports as zero, empty, -999, or -9999")
+  flag.BoolVar(&dowindrose, "wind-rose", false, "Draws a compass rose marking the current wind direction")
+  flag.BoolVar(&doemoji, "emoji-summary", false, "Prints the current conditions as a single emoji (or a two-letter code without UTF-8)")
+  flag.BoolVar(&doreporttime, "report-time", false, "Prints the local time at the reporting station")
+  flag.BoolVar(&dodiffprevious, "conditions-diff-previous", false, "Reports what has changed since the last time wu fetched the conditions")
+  flag.BoolVar(&dotrend, "conditions-trend", false, "Reports how temperature, humidity, pressure, and wind have changed recently")
+  flag.BoolVar(&pwscalibration, "pws-calibration-warning", false, "Warns when a personal weather station's temperature differs from the nearest official station's by more than 10 F")
+  flag.BoolVar(&dopressurefcst, "conditions-pressure-forecast", false, "Adds a forecast from the change in pressure over the last three hours to the current conditions")
+  flag.StringVar(&translatelang, "conditions-translate", "", "Translates the sky conditions into es, fr, de, pt, or it")
+  flag.BoolVar(&doheatindex, "humidity-comfort", false, "Adds the NWS heat index and its category to the current conditions")
+  flag.BoolVar(&dohumidex, "humidex", false, "Adds the Canadian humidex to the current conditions (always shown for Canadian stations)")
+  flag.StringVar(&dosince, "conditions-since", "", "Reports the observation closest to a time at the station --conditions-since=\"YYYY-MM-DDTHH:MM\"")
+  flag.BoolVar(&dochart, "conditions-chart", false, "Shows today's temperature trend as a sparkline beside the current temperature")
+  flag.IntVar(&trendwindow, "trend-window", 2, "Hours to look back for -conditions-trend")
+  flag.BoolVar(&watchalert, "watch-alert", false, "Checks for alerts every -interval seconds, printing new and cleared ones")
+  flag.IntVar(&watchinterval, "interval", 60, "Seconds between checks for -watch-alert")
+  flag.BoolVar(&doalertdetail, "alert-detail", false, "Reports the full text of each alert, with its zones and message ID")
+  flag.BoolVar(&dopager, "pager", false, "Pages -alert-detail through $PAGER (or less)")
   flag.BoolVar(&doalerts, "alerts", false, "Reports any active weather alerts")
   flag.BoolVar(&dolookup, "lookup", false, "Lookup the codes for the weather stations in a particular area")
+  flag.BoolVar(&dostationinfo, "station-info", false, "Reports the name, location, elevation, and network of the weather station")
+  flag.BoolVar(&dostationdist, "station-distance", false, "Reports how far the reporting station is from the location asked about")
+  flag.BoolVar(&doairport, "airport-info", false, "Reports details about an airport station along with its conditions --airport-info KLNK")
   flag.BoolVar(&doastro, "astro", false, "Reports sunrise, sunset, and lunar phase")
+  flag.BoolVar(&doastrodetail, "astro-detail", false, "Reports twilight times and golden hour along with -astro")
+  flag.BoolVar(&domoontext, "moon-phase-text", false, "Describes the phase of the moon in a sentence")
+  flag.BoolVar(&domoonillum, "moon-illumination-percent", false, "Prints only the percentage of the moon that is illuminated")
   flag.BoolVar(&doforecast, "forecast", false, "Reports the current (3-day) forecast")
   flag.BoolVar(&doforecast10, "forecast10", false, "Reports the current (7-day) forecast")
+  flag.BoolVar(&dofreezing, "forecast-freezing", false, "Marks the -forecast10 periods with lows below freezing (❄)")
+  flag.BoolVar(&freezewarn, "forecast-freezing-warn", false, "Exit with status 2 if any night in the 10-day forecast is below freezing")
+  flag.StringVar(&doeventday, "forecast-event-day", "", "Reports the 10-day forecast for one date --forecast-event-day=\"YYYY-MM-DD\"")
+  flag.BoolVar(&doconfidencepop, "forecast-confidence", false, "Shows each forecast period's chance of precipitation, and what it means")
+  flag.StringVar(&dobestday, "forecast-best-day", "", "Finds the best day of the forecast for outdoor, cycling, gardening, or ski")
+  flag.BoolVar(&doforecastdelta, "forecast-delta", false, "Reports how the 7-day forecast has changed since a day ago")
+  flag.BoolVar(&doweekendscore, "forecast-weekend-score", false, "Scores the weekends in the 10-day forecast for outdoor activities")
+  flag.BoolVar(&dotravel, "forecast-travel-index", false, "Scores each forecast day for flying, driving, cycling, or (by default) all three")
+  flag.BoolVar(&doraintotal, "forecast-rain-total", false, "Reports the total precipitation expected over the 10-day forecast")
+  flag.BoolVar(&dosummary, "forecast-summary", false, "Summarizes the week's forecast in one sentence")
+  flag.BoolVar(&doairquality, "air-quality", false, "Reports the air quality index (on API plans that include it)")
+  flag.BoolVar(&dopollen, "pollen-risk", false, "Reports the pollen forecast (where available)")
+  flag.StringVar(&pollenallergen, "pollen-allergy-type", "all", "Limits -pollen-risk to tree, grass, or weed pollen")
+  flag.BoolVar(&metriconly, "conditions-metric-only", false, "Use only metric units (C, mm, mb, km/h), whatever -metric or .condrc say")
+  flag.BoolVar(&imperialonly, "conditions-imperial-only", false, "Use only imperial units (F, in, inHg, mph), whatever -metric or .condrc say")
+  flag.BoolVar(&doyestnormal, "yesterday-vs-normal", false, "Compares yesterday's high and low with the normals for the date")
+  flag.BoolVar(&dorainwindow, "hourly-rain-window", false, "Reports when rain is expected in the next 12 hours")
+  flag.BoolVar(&douvpeak, "forecast-uv-peak", false, "Reports the day's peak UV index from the hourly forecast, and when to expect it")
+  flag.BoolVar(&domissing, "history-missing-data-report", false, "Lists the days of -history-range that came back without data")
+  flag.BoolVar(&dodecade, "almanac-decade", false, "Compares the average high and low for today's date by decade, over the last four decades")
+  flag.BoolVar(&dobadge, "conditions-markdown-badge", false, "Prints a Markdown shields.io badge of the current conditions (for a README)")
+  flag.BoolVar(&notifydesktop, "notify-desktop", false, "Shows a desktop notification for each active alert (with notify-send or osascript)")
+  flag.BoolVar(&dostationcsv, "station-list-csv", false, "Exports the nearby stations as CSV, to FILE or standard output --station-list-csv [FILE]")
+  flag.BoolVar(&doclothing, "forecast-clothing", false, "Suggests what to wear for today's forecast")
+  flag.BoolVar(&dopack, "forecast-pack", false, "Prints the forecast as a compact strip of days, 80 columns wide")
+  flag.BoolVar(&doday, "forecast-day", false, "Reports only the daytime periods of the forecast (with -forecast or -forecast10)")
+  flag.BoolVar(&donight, "forecast-night", false, "Reports only the night periods of the forecast (with -forecast or -forecast10)")
+  flag.BoolVar(&doweekend, "forecast-weekend", false, "Reports only the Saturday and Sunday periods of the 10-day forecast")
+  flag.BoolVar(&dohighlow, "forecast-high-low-only", false, "Reports only the daily highs and lows of the forecast on one line")
+  flag.BoolVar(&dohourly, "hourly", false, "Reports the hourly forecast")
+  flag.IntVar(&hourlynext, "hourly-next", 3, "Reports the hourly forecast for the next N hours --hourly-next=6")
   flag.BoolVar(&doalmanac, "almanac", false, "Reports average high, low and record temperatures")
+  flag.BoolVar(&docondhistory, "conditions-history", false, "Reports the current conditions beside the normals for the date")
+  flag.BoolVar(&doanomaly, "history-anomaly", false, "Reports how far the current temperature is from the normal high and low")
+  flag.BoolVar(&doalmanacdetail, "almanac-detail", false, "Reports the almanac with each record's departure from normal and age")
   flag.BoolVar(&doyesterday, "yesterday", false, "Reports yesterday's weather data")
+  flag.BoolVar(&doyestrain, "yesterday-rainfall", false, "Prints only yesterday's total precipitation")
+  flag.BoolVar(&dorecentprecip, "recent-precip", false, "Reports the precipitation in each hour today and a running total")
+  flag.StringVar(&precipformat, "precip-format", "trace", "How -yesterday-rainfall prints a trace of precipitation: trace (T) or zero (0.00)")
   flag.StringVar(&dohistory, "history", "", "Reports historical data for a particular day --history=\"YYYYMMDD\"")
+  flag.BoolVar(&dopercentile, "history-percentile", false, "Adds an estimated percentile to the -history and -yesterday high and low")
+  flag.StringVar(&dohistrange, "history-range", "", "Reports daily historical data for a range of days --history-range=\"YYYYMMDD-YYYYMMDD\"")
+  flag.BoolVar(&dohistplot, "history-plot", false, "Plots daily high and low temperatures for -history-range")
+  flag.BoolVar(&dohistplotprecip, "history-plot-precip", false, "Plots daily precipitation for -history-range as a bar chart")
+  flag.StringVar(&icalfile, "history-export-ical", "", "Writes -history-range to an iCalendar file, one all-day event per day")
+  flag.Var(&onweather, "on-weather", "Runs a command when the current weather matches a condition --on-weather=\"rain:lights on\" (may be repeated)")
+  flag.IntVar(&uptimedays, "station-uptime", 0, "Reports how many of the last DAYS days the station has reported data")
+  flag.StringVar(&dorecordrain, "history-record-rain", "", "Finds the wettest days in a range --history-record-rain=\"YYYYMMDD-YYYYMMDD\"")
+  flag.StringVar(&dosnowfall, "history-snowfall", "", "Reports daily snowfall and the season total --history-snowfall=\"YYYYMMDD-YYYYMMDD\"")
+  flag.StringVar(&dofreezedates, "history-freeze-dates", "", "Finds the last spring and first fall freeze of a year --history-freeze-dates=\"YYYY\"")
+  flag.IntVar(&historyrate, "history-rate", 10, "Makes at most N requests a minute for the reports that fetch many days of history (10 on the free API plan)")
+  flag.StringVar(&doheatmap, "history-heatmap", "", "Draws a calendar heatmap of the daily highs for a month --history-heatmap=\"YYYYMM\"")
+  flag.BoolVar(&doweekdayavg, "history-weekday-avg", false, "Reports average conditions by day of the week --history-weekday-avg YYYYMMDD YYYYMMDD")
+  flag.BoolVar(&doextremes, "history-extremes", false, "Reports monthly and all-time record temperatures and precipitation")
   flag.StringVar(&doplanner, "planner", "", "Reports historical data for a particular date range (30-day max) --planner=\"MMDDMMDD\"")
+  flag.BoolVar(&doconfidence, "planner-confidence", false, "Reports only how many years of data back the -planner averages")
+  flag.BoolVar(&dorainydays, "planner-rain-days", false, "Reports how many days of a --planner range have historically been rainy")
+  flag.BoolVar(&docompare, "compare-planner", false, "Compares the planner for two date ranges --compare-planner MMDDMMDD MMDDMMDD")
   flag.BoolVar(&dotides, "tides", false, "Reports tidal data (if available")
+  flag.BoolVar(&dotidesnext, "tides-next", false, "Reports only the next high or low tide")
+  flag.BoolVar(&doaddalias, "add-alias", false, "Add a station alias to ~/.config/wu/stations.json --add-alias NAME STATION")
+  flag.StringVar(&format, "format", "text", "Output format: text, json, ndjson, html, syslog, csv, or tsv")
+  flag.StringVar(&export, "export", "", "Writes -format json, ndjson, html, csv, or tsv output to a file (adding the extension if there is none)")
+  flag.StringVar(&htmltheme, "html-theme", "light", "Color scheme for -format html: light or dark")
+  flag.BoolVar(&csvheader, "csv-header", true, "Print a header row with -format csv or tsv (-csv-header=false to omit it)")
+  flag.StringVar(&sysloghost, "syslog-host", "", "Send -format syslog messages over UDP to HOST:PORT")
+  flag.IntVar(&jsonindent, "indent", 2, "Spaces of indentation for -format json (0 for a single line)")
+  flag.BoolVar(&noindent, "no-indent", false, "Print -format json on a single line (-indent 0)")
+  flag.BoolVar(&jsoncompact, "conditions-json-compact", false, "Print the current conditions as single-line JSON")
+  flag.StringVar(&jsonpath, "json-path", "", "Print a single value from the JSON output --json-path=\"conditions.temp_f\"")
+  flag.BoolVar(&doschema, "schema-version", false, "Print the JSON output schema version")
+  flag.BoolVar(&dojsonschema, "json-schema", false, "Print a JSON Schema describing the JSON output")
+  flag.BoolVar(&metric, "metric", false, "Use metric units for all measurements")
+  flag.StringVar(&tempunit, "temperature-unit", "", "Temperature unit: f, c, or k (default both F and C)")
+  flag.StringVar(&precipunit, "precipitation-unit", "", "Precipitation unit: in or mm (default both)")
+  flag.StringVar(&pressunit, "pressure-unit", "", "Pressure unit: mb, inhg, kpa, or atm (default both in and mb)")
+  flag.StringVar(&locale, "locale", "", "Format numbers and dates for a locale (e.g. de_DE)")
+  flag.BoolVar(&readable, "readable", false, "Spell out numbers and units (for screen readers and text-to-speech)")
+  flag.StringVar(&filtercond, "filter-condition", "", "Only show forecast days and hours matching a regular expression --filter-condition=\"rain|thunder\"")
+  flag.BoolVar(&invertfilter, "invert-filter", false, "Only show forecast periods that don't match -filter-condition")
+  flag.IntVar(&rainrisk, "forecast-rain-risk", 0, "Only show forecast periods with at least an N% chance of precipitation --forecast-rain-risk=40")
+  flag.IntVar(&limit, "limit", 0, "Show at most N forecast periods (after any filtering) --limit=3")
+  flag.StringVar(&forecastdetail, "forecast-detail", "full", "How much of each forecast period to show --forecast-detail=[brief|full]")
+  flag.Float64Var(&gustwarn, "gust-warning", 0, "Exit with status 2 if wind gusts exceed a speed in mph (km/h with -metric) --gust-warning=40")
+  flag.IntVar(&agewarn, "conditions-age-warning", 0, "Exit with status 3 if the current observation is more than this many minutes old")
+  flag.StringVar(&windchilladv, "wind-chill-advisory", "", "Exit with status 2 if the wind chill is below a threshold --wind-chill-advisory=-20")
+  flag.BoolVar(&doconfiginit, "config-init", false, "Create $HOME/.condrc interactively")
+  flag.BoolVar(&apitest, "api-test", false, "Checks the API key and the connection to Weather Underground")
+  flag.BoolVar(&scriptmode, "script-mode", false, "Report errors as \"ERR_TYPE: message\" with a distinct exit status for each type")
+  flag.BoolVar(&cron, "cron", false, "Print nothing unless an alert is active or an advisory threshold is crossed")
+  flag.BoolVar(&verbose, "verbose", false, "Print reports even when -cron would suppress them")
+  flag.IntVar(&exitonalert, "exit-on-alert", 0, "Exit with status N if any weather alert is active --exit-on-alert=2")
+  flag.BoolVar(&timing, "timing", false, "Print how long the API requests and JSON parsing took (to stderr)")
+  flag.BoolVar(&quiet, "quiet", false, "Omit the trailing newline from single-value reports")
   flag.BoolVar(&help, "help", false, "Print this message")
   flag.BoolVar(&version, "version", false, "Print the version number")
   flag.BoolVar(&doall, "all", false, "Show all weather data")
   flag.StringVar(&station, "s", sconf,
     "Weather station: \"city, state-abbreviation\", (US or Canadian) zipcode, 3- or 4-letter airport code, or LAT,LONG")
+  flag.StringVar(&latflag, "lat", "", "Latitude of the location (with -lon, instead of -s LAT,LONG)")
+  flag.StringVar(&lonflag, "lon", "", "Longitude of the location (with -lat, instead of -s LAT,LONG)")
   flag.Parse()
 
+  // -hourly-next has a default, so whether it was asked for depends
+  // on whether it was given
+  flag.Visit(func(f *flag.Flag) {
+    if f.Name == "hourly-next" {
+      dohourlynext = true
+    }
+    if historyFlags[f.Name] {
+      historymode = true
+    }
+  })
+  if dohourlynext && hourlynext < 1 {
+    Fail(InvalidInput, "Usage: wu -hourly-next N (at least 1)")
+  }
+
   // Check for correct usage of wu -lookup
   if dolookup {
     if len(os.Args) == 3 {
       station = os.Args[len(os.Args)-1]
     } else {
-      fmt.Println("Usage: wu -lookup [station] where station is a \"city, state-abbreviation\", (US or Canadian) zipcode, 3- or 4-letter airport code, or LAT,LONG")
-      os.Exit(0)
+      Fail(InvalidInput, "Usage: wu -lookup [station] where station is a \"city, state-abbreviation\", (US or Canadian) zipcode, 3- or 4-letter airport code, or LAT,LONG")
+    }
+  }
+
+  // Check for correct usage of wu -airport-info
+  if doairport {
+    if flag.NArg() == 1 && airportPattern.MatchString(strings.ToUpper(flag.Arg(0))) {
+      station = strings.ToUpper(flag.Arg(0))
+    } else {
+      Fail(InvalidInput, "Usage: wu -airport-info [ICAO] where ICAO is a 4-letter airport code (e.g. KLNK)")
+    }
+  }
+
+  if doconfiginit {
+    CheckError(InitConfig(os.Stdin, os.Stdout))
+    os.Exit(0)
+  }
+
+  if noconf {
+    Fail(ConfigMissing, "You must create a .condrc file in $HOME (or run wu -config-init).")
+  }
+  if confErr != nil {
+    CheckError(Classify(ConfigMissing, fmt.Errorf("Can't read %s: %v", ConfFile(), confErr)))
+  }
+
+  if dojsonschema {
+    CheckError(PrintJSONSchema(os.Stdout))
+    os.Exit(0)
+  }
+
+  if doschema {
+    fmt.Println(SchemaVersion)
+    os.Exit(0)
+  }
+
+  if jsoncompact {
+    format, noindent, doconditions = "json", true, true
+  }
+  if noindent || jsonindent < 0 {
+    jsonindent = 0
+  }
+
+  switch format {
+  case "text", "json", "ndjson", "html", "syslog", "csv", "tsv":
+  default:
+    Fail(InvalidInput, "Usage: wu -format [text|json|ndjson|html|syslog|csv|tsv]")
+  }
+
+  if export != "" && (format == "text" || format == "syslog") {
+    Fail(InvalidInput, "Usage: wu -export FILE -format [json|ndjson|html|csv|tsv]")
+  }
+
+  if limit < 0 {
+    Fail(InvalidInput, "Usage: wu -limit N (at least 1)")
+  }
+
+  if forecastdetail != "brief" && forecastdetail != "full" {
+    Fail(InvalidInput, "Usage: wu -forecast-detail [brief|full]")
+  }
+
+  if htmltheme != "light" && htmltheme != "dark" {
+    Fail(InvalidInput, "Usage: wu -html-theme [light|dark]")
+  }
+
+  SetUnits()
+
+  // -cron is -quiet that also exits non-zero when there's an alert
+  if cron {
+    quiet = true
+    if exitonalert == 0 {
+      exitonalert = 1
+    }
+  }
+
+  if doweekdayavg {
+    if flag.NArg() != 2 {
+      Fail(InvalidInput, "Usage: wu -history-weekday-avg [YYYYMMDD] [YYYYMMDD]")
+    }
+    dohistrange = flag.Arg(0) + "-" + flag.Arg(1)
+  }
+
+  if dosince != "" {
+    var err error
+    if since, err = time.Parse("2006-01-02T15:04", dosince); err != nil {
+      Fail(InvalidInput, "Usage: wu -conditions-since YYYY-MM-DDTHH:MM")
+    }
+  }
+
+  if windchilladv != "" {
+    var err error
+    if windchilllimit, err = strconv.ParseFloat(windchilladv, 64); err != nil {
+      Fail(InvalidInput, "Usage: wu -wind-chill-advisory [degrees F]")
+    }
+  }
+
+  if doeventday != "" {
+    var err error
+    if eventday, err = time.Parse("2006-01-02", doeventday); err != nil {
+      Fail(InvalidInput, "Usage: wu -forecast-event-day YYYY-MM-DD")
+    }
+  }
+
+  if _, ok := activities[dobestday]; dobestday != "" && !ok {
+    Fail(InvalidInput, "Usage: wu -forecast-best-day ["+strings.Join(activityNames(), "|")+"]")
+  }
+
+  if _, ok := translations[translatelang]; translatelang != "" && !ok {
+    Fail(InvalidInput, "Usage: wu -conditions-translate ["+strings.Join(translationLanguages(), "|")+"]")
+  }
+
+  if uptimedays < 0 || uptimedays > 366 {
+    Fail(InvalidInput, "Usage: wu -station-uptime DAYS (at most 366)")
+  }
+  if historyrate < 1 {
+    Fail(InvalidInput, "Usage: wu -history-rate N (requests a minute, at least 1)")
+  }
+
+  switch pollenallergen {
+  case "tree", "grass", "weed", "all":
+  default:
+    Fail(InvalidInput, "Usage: wu -pollen-risk -pollen-allergy-type [tree|grass|weed|all]")
+  }
+
+  if dotravel {
+    travelmode = "all"
+    if flag.NArg() > 0 {
+      travelmode = strings.Join(flag.Args(), " ")
+    }
+    switch travelmode {
+    case "flying", "driving", "cycling", "all":
+    default:
+      Fail(InvalidInput, "Usage: wu -forecast-travel-index [flying|driving|cycling|all]")
+    }
+  }
+
+  if watchinterval < 1 {
+    Fail(InvalidInput, "Usage: wu -watch-alert -interval SECONDS")
+  }
+
+  if precipformat != "trace" && precipformat != "zero" {
+    Fail(InvalidInput, "Usage: wu -precip-format [trace|zero]")
+  }
+
+  if doconfidence && doplanner == "" {
+    Fail(InvalidInput, "Usage: wu -planner-confidence -planner=\"MMDDMMDD\"")
+  }
+
+  if dorainydays && doplanner == "" {
+    Fail(InvalidInput, "Usage: wu -planner-rain-days -planner=\"MMDDMMDD\"")
+  }
+
+  if icalfile != "" && dohistrange == "" {
+    Fail(InvalidInput, "Usage: wu -history-export-ical FILE -history-range=\"YYYYMMDD-YYYYMMDD\"")
+  }
+
+  if dohistplotprecip && dohistrange == "" {
+    Fail(InvalidInput, "Usage: wu -history-plot-precip -history-range=\"YYYYMMDD-YYYYMMDD\"")
+  }
+
+  if donight && doday {
+    Fail(InvalidInput, "Usage: wu -forecast-day or -forecast-night, not both")
+  }
+
+  if dostationcsv && flag.NArg() > 1 {
+    Fail(InvalidInput, "Usage: wu -station-list-csv [FILE]")
+  }
+
+  if metriconly && imperialonly {
+    Fail(InvalidInput, "Usage: wu -conditions-metric-only or -conditions-imperial-only, not both")
+  }
+
+  if domissing && dohistrange == "" {
+    Fail(InvalidInput, "Usage: wu -history-missing-data-report -history-range=\"YYYYMMDD-YYYYMMDD\"")
+  }
+
+  if dohistplot && dohistrange == "" {
+    Fail(InvalidInput, "Usage: wu -history-plot -history-range=\"YYYYMMDD-YYYYMMDD\"")
+  }
+
+  // Record a new station alias and exit
+  if doaddalias {
+    if flag.NArg() != 2 {
+      Fail(InvalidInput, "Usage: wu -add-alias [name] [station]")
+    }
+    CheckError(AddStationAlias(AliasFile(), flag.Arg(0), flag.Arg(1)))
+    fmt.Printf("Added alias %s for %s\n", flag.Arg(0), flag.Arg(1))
+    os.Exit(0)
+  }
+
+  if docompare {
+    if flag.NArg() != 2 || !plannerPattern.MatchString(flag.Arg(0)) || !plannerPattern.MatchString(flag.Arg(1)) {
+      Fail(InvalidInput, "Usage: wu -compare-planner [MMDDMMDD] [MMDDMMDD]")
     }
   }
 
@@ -150,22 +628,90 @@ func Options() string {
     os.Exit(0)
   }
 
-  // Trap for city-state combinations (e.g. "San Francisco, CA") and
-  // make them URL-friendly (e.g. "CA/SanFranciso")
-  cityStatePattern := regexp.MustCompile("([A-Za-z ]+), ([A-Za-z ]+)")
+  // -lat and -lon override -s
+  if latflag != "" || lonflag != "" {
+    coords, err := CoordinatesStation(latflag, lonflag)
+    if err != nil {
+      Fail(InvalidInput, "Usage: wu -lat LAT -lon LON: "+err.Error())
+    }
+    station = coords
+  }
+
+  return StationPath(ResolveStation(station))
+}
 
+var cityStatePattern = regexp.MustCompile("([A-Za-z ]+), ([A-Za-z ]+)")
+
+// StationPath returns station in the form the API expects.  PWS IDs,
+// airport codes, zip codes, and coordinates are used as they are, but
+// city-state combinations (e.g. "San Francisco, CA" or "Santa Fe, New
+// Mexico") are made URL-friendly (e.g. "CA/San_Francisco").
+func StationPath(station string) string {
+  if isPWSID(station) {
+    return station
+  }
   if cityState := cityStatePattern.FindStringSubmatch(station); cityState != nil {
-    station = cityState[2] + "/" + cityState[1]
-    station = strings.Replace(station, " ", "_", -1)
+    station = CityStatePath(cityState[1], cityState[2])
   }
   return station
 }
 
+// SetUnits fills in units from --metric and the more specific
+// --temperature-unit, --precipitation-unit, and --pressure-unit
+// switches (which win)
+func SetUnits() {
+  switch {
+  case metriconly:
+    units = NewUnits(MetricOnly)
+  case imperialonly:
+    units = NewUnits(ImperialOnly)
+  case metric || conf.Units == "metric":
+    units = NewUnits(MetricOnly)
+  case conf.Units == "imperial":
+    units = NewUnits(ImperialOnly)
+  }
+  switch tempunit {
+  case "":
+  case "f", "c", "k":
+    if metric && tempunit != units.Temperature {
+      fmt.Fprintf(os.Stderr, "Warning: -temperature-unit %s overrides -metric\n", tempunit)
+    }
+    units.Temperature = tempunit
+  default:
+    Fail(InvalidInput, "Usage: wu -temperature-unit [f|c|k]")
+  }
+  units.Readable = readable
+  if locale == "" {
+    locale = conf.Locale
+  }
+  if locale != "" {
+    l, err := NewLocalizer(locale)
+    CheckError(Classify(InvalidInput, err))
+    units.Locale = l
+  }
+  switch precipunit {
+  case "":
+  case "in", "mm":
+    if metric && precipunit != units.Precipitation {
+      fmt.Fprintf(os.Stderr, "Warning: -precipitation-unit %s overrides -metric\n", precipunit)
+    }
+    units.Precipitation = precipunit
+  default:
+    Fail(InvalidInput, "Usage: wu -precipitation-unit [in|mm]")
+  }
+  switch pressunit {
+  case "":
+  case "inhg", "mb", "kpa", "atm":
+    units.Pressure = pressunit
+  default:
+    Fail(InvalidInput, "Usage: wu -pressure-unit [mb|inhg|kpa|atm]")
+  }
+}
+
 // BuildURL returns the URL required by the Weather Underground API
 // from the query type, station id, and API key
 func BuildURL(infoTypes []string, stationId string) string {
 
-  const URLstem = "http://api.wunderground.com/api/"
   const query = "/q/"
   const format = ".json"
 
@@ -178,34 +724,163 @@ func BuildURL(infoTypes []string, stationId string) string {
       infoTypes[i] += "_" + doplanner
     }
   }
-  URL = URLstem + conf.Key + "/" + strings.Join(infoTypes, "/") + query + stationId + format
+  // The API wants personal weather stations as pws:ID
+  if isPWSID(stationId) {
+    stationId = "pws:" + stationId
+  }
+  URL = apiURL + conf.Key + "/" + strings.Join(infoTypes, "/") + query + stationId + format
 
    //fmt.Println(URL) //DEBUG
 
   return URL
 }
 
+// Reports that aren't API features themselves, and the features each
+// is derived from
+var derivedReports = map[string][]string{
+  "moonillumination":     {"astronomy"},
+  "moonphasetext":        {"astronomy"},
+  "yesterdayrainfall":    {"yesterday"},
+  "yesterdaynormal":      {"yesterday", "almanac"},
+  "recentprecip":         {"conditions", "yesterday"},
+  "conditionsdiff":       {"conditions"},
+  "conditionsbadge":      {"conditions"},
+  "conditionsepoch":      {"conditions"},
+  "dewpointcomfort":      {"conditions"},
+  "localtime":            {"conditions"},
+  "emojisummary":         {"conditions"},
+  "windrose":             {"conditions"},
+  "historyanomaly":       {"conditions", "almanac"},
+  "conditionshistory":    {"conditions", "almanac"},
+  "stationlistcsv":       {"geolookup"},
+  "stationinfo":          {"conditions", "geolookup"},
+  "plannerconfidence":    {"planner"},
+  "plannerraindays":      {"planner"},
+  "airportinfo":          {"conditions", "geolookup"},
+  "conditionstrend":      {"conditions"},
+  "forecasthighlow":      {"forecast10day"},
+  "forecastsummary":      {"forecast10day"},
+  "forecastclothing":     {"forecast10day"},
+  "hourlyrainwindow":     {"hourly", "conditions"},
+  "forecastuvpeak":       {"hourly", "conditions"},
+  "forecastpack":         {"forecast10day"},
+  "forecastraintotal":    {"forecast10day"},
+  "forecastbestday":      {"forecast10day"},
+  "forecastweekendscore": {"forecast10day"},
+  "forecastdelta":        {"forecast10day"},
+  "forecasttravelindex":  {"forecast"},
+}
+
+// Dependencies returns the API features that must be requested along
+// with operations in order to satisfy switches that modify a report
+func Dependencies(operations []string) []string {
+  features := make([]string, 0)
+  for _, operation := range operations {
+    if derived, ok := derivedReports[operation]; ok {
+      for _, feature := range derived {
+        features = appendFeature(features, feature)
+      }
+    } else {
+      features = appendFeature(features, operation)
+    }
+  }
+  if doastrodetail || dostationdist || pwscalibration {
+    features = appendFeature(features, "geolookup")
+  }
+  if windchilladv != "" || gustwarn > 0 || pwscalibration || len(onweather) > 0 || agewarn > 0 || dohourlynext || format == "syslog" || format == "csv" || format == "tsv" {
+    features = appendFeature(features, "conditions")
+  }
+  if cron || exitonalert != 0 || notifydesktop {
+    features = appendFeature(features, "alerts")
+  }
+  if freezewarn {
+    features = appendFeature(features, "forecast10day")
+  }
+  if dopercentile {
+    features = appendFeature(features, "almanac")
+  }
+  return features
+}
+
+// appendFeature adds feature to features unless it is already there
+func appendFeature(features []string, feature string) []string {
+  for _, f := range features {
+    if f == feature {
+      return features
+    }
+  }
+  return append(features, feature)
+}
+
 // Fetch does URL processing
 func Fetch(url string) ([]byte, error) {
 //fmt.Println("Calling API") //DEBUG
 
+  start := time.Now()
+  defer func() { metrics.recordFetch(time.Since(start)) }()
   res, err := http.Get(url)
-  CheckError(err)
-  if res.StatusCode != 200 {
-    fmt.Fprintf(os.Stderr, "Bad HTTP Status: %d\n", res.StatusCode)
-    return nil, err
+  if err != nil {
+    return nil, Classify(NetworkError, err)
+  }
+  if res.StatusCode == http.StatusTooManyRequests {
+    res.Body.Close()
+    return nil, Classify(QuotaExceeded, fmt.Errorf("Bad HTTP Status: %d", res.StatusCode))
+  } else if res.StatusCode != 200 {
+    res.Body.Close()
+    return nil, Classify(APIError, fmt.Errorf("Bad HTTP Status: %d", res.StatusCode))
   }
   b, err := ioutil.ReadAll(res.Body)
   res.Body.Close()
-  return b, err
+  return b, Classify(NetworkError, err)
 }
 
-// CheckError exits on error with a message
-func CheckError(err error) {
+// The exit statuses of -api-test
+const (
+  apiTestOK          = 0
+  apiTestKeyRejected = 4 // the key is invalid or has used up its requests
+  apiTestNetworkDown = 5 // Weather Underground couldn't be reached (or made no sense)
+)
+
+// APITest makes a conditions request for stationId and reports on
+// w whether the API key and connection work, returning the status to
+// exit with
+func APITest(stationId string, w io.Writer) int {
+  start := time.Now()
+  b, err := Fetch(BuildURL([]string{"conditions"}, stationId))
+  elapsed := time.Since(start)
   if err != nil {
-    fmt.Fprintf(os.Stderr, "Fatal error\n%v\n", err)
-    os.Exit(1)
+    switch codeOf(err) {
+    case QuotaExceeded:
+      fmt.Fprintln(w, "API request limit reached.  Try again later.")
+      return apiTestKeyRejected
+    case APIError:
+      fmt.Fprintf(w, "Unexpected response from Weather Underground: %v.\n", err)
+      return apiTestNetworkDown
+    }
+    // Leave out the URL, which holds the API key
+    var e *url.Error
+    if errors.As(err, &e) {
+      err = e.Err
+    }
+    fmt.Fprintf(w, "Network error: %v.\n", err)
+    return apiTestNetworkDown
+  }
+  var obs Conditions
+  if err := parseJSON(b, &obs); err != nil {
+    fmt.Fprintf(w, "Unexpected response from Weather Underground: %v.\n", err)
+    return apiTestNetworkDown
   }
+  if err := obs.Response.Err(); err != nil {
+    if codeOf(err) == QuotaExceeded {
+      fmt.Fprintln(w, "API request limit reached.  Try again later.")
+    } else {
+      fmt.Fprintln(w, "API key authentication failed. Check your ~/.condrc.")
+    }
+    return apiTestKeyRejected
+  }
+  fmt.Fprintf(w, "API key is valid. Connection to Weather Underground: OK. Response time: %dms.\n",
+    elapsed.Nanoseconds()/int64(time.Millisecond))
+  return apiTestOK
 }
 
 func init() {
@@ -213,65 +888,488 @@ func init() {
 }
 
 type Conditions struct {
-  Alerts []Alerts
-  Almanac Almanac
-  Current_observation Current
-  Forecast Forecast
-  History History
-  Location SLocation
-  Moon_phase Moon_phase
-  Sunrise Sunrise
-  Sunset Sunset
-  Tide Tide
-  Trip Trip
+  Response            Response       `json:"response"`
+  Air_quality         AQI            `json:"air_quality"`
+  Alerts              []Alerts       `json:"alerts"`
+  Almanac             Almanac        `json:"almanac"`
+  Current_observation Current        `json:"current_observation"`
+  Forecast            Forecast       `json:"forecast"`
+  Hourly_forecast     []HourlyPeriod `json:"hourly_forecast"`
+  History             History        `json:"history"`
+  Location            SLocation      `json:"location"`
+  Moon_phase          Moon_phase     `json:"moon_phase"`
+  Pollen              *Pollen        `json:"pollen"`
+  Sunrise             Sunrise        `json:"sunrise"`
+  Sunset              Sunset         `json:"sunset"`
+  Tide                Tide           `json:"tide"`
+  Trip                Trip           `json:"trip"`
+
+  trendBaseline    *Current        // the cached observation for -conditions-trend
+  previous         *Current        // the last cached observation for -conditions-diff-previous
+  pressureBaseline *Current        // the cached observation for -conditions-pressure-forecast
+  forecastBaseline *CachedForecast // the cached forecast for -forecast-delta
+  freezing         bool            // whether any night of the forecast is below freezing
+  today            []Observations  // today's observations for -conditions-chart and -recent-precip
+  official         *Current        // the nearest official station's conditions for -pws-calibration-warning
 }
 
 // weather prints various weather information for a specified station
 func weather(operations []string, station string) {
-  url := BuildURL(operations, station)
+  features := Dependencies(operations)
+  url := BuildURL(features, station)
   b, err := Fetch(url)
   CheckError(err)
 
   var obs Conditions
-  jsonErr := json.Unmarshal(b, &obs)
+  jsonErr := parseJSON(b, &obs)
   CheckError(jsonErr)
+  CheckError(obs.Response.Err())
+  forecast := false
+  for _, feature := range features {
+    if feature == "forecast" || feature == "forecast10day" {
+      forecast = true
+    }
+    if feature == "conditions" {
+      current := &obs.Current_observation
+      cached := LoadObservations(station)
+      obs.trendBaseline, _ = TrendBaseline(cached, current, time.Duration(trendwindow)*time.Hour)
+      obs.previous, _ = PreviousObservation(cached, current)
+      obs.pressureBaseline, _ = TrendBaseline(cached, current, pressureWindow*time.Hour)
+      CacheObservation(station, current)
+      if dochart || dorecentprecip {
+        today, ok := LocalTime(current)
+        if !ok {
+          today = time.Now()
+        }
+        obs.today, err = FetchObservations(today, station)
+        CheckError(err)
+      }
+      if pwscalibration && isPWSID(current.Station_id) {
+        obs.official, err = FetchOfficialConditions(&obs)
+        if err != nil {
+          fmt.Fprintln(os.Stderr, "PWS calibration check skipped: "+err.Error())
+        }
+      } else if pwscalibration {
+        fmt.Fprintf(os.Stderr, "PWS calibration check skipped: %s is not a personal weather station\n", current.Station_id)
+      }
+    }
+  }
+  // -forecast and -forecast10 share one cache, so it is read (and
+  // then updated) only once, however many forecast features there are
+  if forecast {
+    now := time.Now()
+    obs.forecastBaseline, _ = ForecastBaseline(LoadForecasts(station), now, 24*time.Hour)
+    CacheForecast(station, obs.Forecast.Simpleforecast.Forecastday, now)
+  }
+  if dohourlynext {
+    now, err := time.Parse(time.RFC1123Z, obs.Current_observation.Local_time_rfc822)
+    if err != nil {
+      now = time.Now()
+    }
+    if hourlynext > len(obs.Hourly_forecast) {
+      fmt.Fprintf(os.Stderr, "Note: only %d hours of forecast are available\n", len(obs.Hourly_forecast))
+    }
+    obs.Hourly_forecast = FilterNextHours(obs.Hourly_forecast, hourlynext, now)
+  }
+  if filtercond != "" {
+    days, err := FilterForecast(obs.Forecast.Txt_forecast.Forecastday, filtercond, invertfilter)
+    if err != nil {
+      CheckError(Classify(InvalidInput, fmt.Errorf("Invalid -filter-condition pattern: %v", err)))
+    }
+    obs.Forecast.Txt_forecast.Forecastday = days
+    obs.Forecast.Simpleforecast.Forecastday, _ = FilterSimpleForecast(obs.Forecast.Simpleforecast.Forecastday, filtercond, invertfilter)
+    obs.Hourly_forecast, _ = FilterHourly(obs.Hourly_forecast, filtercond, invertfilter)
+  }
+  if dofreezing || freezewarn {
+    obs.freezing = MarkFreezing(&obs.Forecast)
+  }
+  if doeventday != "" {
+    periods, err := FindForecastDay(&obs.Forecast, eventday)
+    CheckError(Classify(InvalidInput, err))
+    obs.Forecast.Txt_forecast.Forecastday = periods
+  }
+  if doweekend {
+    days := FilterWeekend(obs.Forecast.Txt_forecast.Forecastday)
+    if len(days) == 0 && format == "text" && jsonpath == "" {
+      fmt.Println("No weekend days in the forecast.")
+    }
+    obs.Forecast.Txt_forecast.Forecastday = days
+  }
+  if donight || doday {
+    keep, which := isNightPeriod, "night"
+    if doday {
+      keep, which = isDayPeriod, "daytime"
+    }
+    days := FilterPeriods(obs.Forecast.Txt_forecast.Forecastday, keep)
+    if len(days) == 0 && format == "text" && jsonpath == "" {
+      fmt.Printf("No %s periods in the forecast.\n", which)
+    }
+    obs.Forecast.Txt_forecast.Forecastday = days
+  }
+  if rainrisk > 0 {
+    days := obs.Forecast.Txt_forecast.Forecastday
+    risky := FilterByRainRisk(days, rainrisk)
+    if format == "text" && jsonpath == "" {
+      if len(risky) == 0 {
+        fmt.Printf("No days with ≥%d%% precipitation probability in the forecast.\n", rainrisk)
+      } else if len(risky) == len(days) {
+        fmt.Println("All forecast days have elevated precipitation risk.")
+      }
+    }
+    obs.Forecast.Txt_forecast.Forecastday = risky
+    obs.Forecast.Simpleforecast.Forecastday = FilterSimpleByRainRisk(obs.Forecast.Simpleforecast.Forecastday, rainrisk)
+  }
+  if limit > 0 {
+    LimitForecast(&obs.Forecast, limit)
+  }
+  if cron && !verbose && !Noteworthy(&obs) {
+    return
+  }
+  if jsonpath != "" {
+    if err := PrintJSONPath(&obs, operations, jsonpath, os.Stdout); err != nil {
+      if scriptmode {
+        CheckError(Classify(InvalidInput, err))
+      }
+      fmt.Println()
+      os.Exit(1)
+    }
+    CheckAdvisories(&obs)
+    return
+  }
+  out := io.Writer(os.Stdout)
+  if export != "" {
+    f, err := os.Create(ExportPath(export, format))
+    CheckError(err)
+    defer f.Close()
+    out = f
+  }
+  if format == "json" {
+    CheckError(PrintJSON(&obs, operations, out))
+    CheckAdvisories(&obs)
+    return
+  }
+  if format == "ndjson" {
+    CheckError(PrintNDJSON(&obs, operations, station, out))
+    CheckAdvisories(&obs)
+    return
+  }
+  if format == "html" {
+    CheckError(PrintHTML(&obs, operations, station, htmltheme, &units, out))
+    CheckAdvisories(&obs)
+    return
+  }
+  if format == "csv" || format == "tsv" {
+    CheckError(PrintConditionsCSV(&obs, format, csvheader, out))
+    CheckAdvisories(&obs)
+    return
+  }
+  if format == "syslog" {
+    CheckError(PrintSyslog(&obs, station, sysloghost, os.Stdout))
+    CheckAdvisories(&obs)
+    return
+  }
   for _, operation := range operations {
     operation = strings.Split(operation, "_")[0]
     switch operation {
     case "almanac":
-      PrintAlmanac(&obs, station)
+      PrintAlmanac(&obs, station, &units)
     case "astronomy":
       PrintAstro(&obs, station)
+    case "conditionsepoch":
+      CheckError(Classify(APIError, PrintObservationEpoch(&obs, os.Stdout)))
+    case "dewpointcomfort":
+      PrintDewPointComfort(&obs, &units, os.Stdout)
+    case "localtime":
+      PrintLocalTime(&obs, os.Stdout)
+    case "emojisummary":
+      PrintEmojiSummary(&obs, os.Stdout)
+    case "conditionshistory":
+      PrintConditionsWithHistory(&obs.Current_observation, &obs.Almanac, &units, os.Stdout)
+    case "historyanomaly":
+      PrintAnomaly(&obs, &units, os.Stdout)
+    case "windrose":
+      PrintWindRose(&obs, units.Metric(), os.Stdout)
+    case "conditionsdiff":
+      if obs.previous != nil {
+        PrintDiffPrevious(&obs.Current_observation, obs.previous, &units, os.Stdout)
+      } else {
+        PrintConditions(&obs, &units, os.Stdout)
+      }
+    case "conditionstrend":
+      if obs.trendBaseline != nil {
+        trend := ComputeTrend(&obs.Current_observation, obs.trendBaseline)
+        PrintTrend(trend, &obs.Current_observation, obs.trendBaseline, &units, os.Stdout)
+      }
+    case "moonillumination":
+      PrintMoonIllumination(&obs, os.Stdout, quiet)
+    case "moonphasetext":
+      PrintMoonPhaseText(&obs, os.Stdout)
     case "alerts":
       PrintAlerts(&obs, station)
     case "conditions":
-      PrintConditions(&obs)
+      if dounitsall {
+        PrintConditionsAllUnits(&obs, os.Stdout)
+      } else if dowide {
+        PrintConditionsWide(&obs, &units, os.Stdout)
+      } else {
+        PrintConditions(&obs, &units, os.Stdout)
+      }
+      if obs.official != nil {
+        PrintCalibrationWarning(&obs.Current_observation, obs.official, os.Stdout)
+      }
     case "forecast":
-      PrintForecast(&obs, station)
+      PrintForecast(&obs, station, &units)
     case "forecast10day":
-      PrintForecast10(&obs, station)
+      PrintForecast10(&obs, station, &units)
+    case "hourly":
+      PrintHourly(&obs, station, &units)
     case "yesterday":
-      PrintHistory(&obs, station)
+      PrintHistory(&obs, station, &units)
+    case "yesterdayrainfall":
+      PrintYesterdayRainfall(&obs, units.Precipitation == "mm", os.Stdout)
+    case "recentprecip":
+      PrintRecentPrecip(&obs, os.Stdout)
     case "history":
-      PrintHistory(&obs, station)
+      PrintHistory(&obs, station, &units)
     case "planner":
-      PrintPlanner(&obs, station)
+      PrintPlanner(&obs, station, &units)
     case "tide":
-      PrintTides(&obs, station)
+      if dotidesnext {
+        PrintNextTide(&obs, time.Now(), os.Stdout)
+      } else {
+        PrintTides(&obs, station)
+      }
     case "geolookup":
       PrintLookup(&obs)
+    case "stationinfo":
+      PrintStationInfo(&obs, os.Stdout)
+    case "airportinfo":
+      PrintAirportInfo(&obs, os.Stdout)
+    case "forecastsummary":
+      PrintForecastSummary(&obs, os.Stdout)
+    case "forecastpack":
+      PrintForecastPack(&obs, units.Metric(), os.Stdout)
+    case "airquality":
+      PrintAQI(&obs.Air_quality, os.Stdout)
+    case "pollen":
+      PrintPollen(&obs, pollenallergen, os.Stdout)
+    case "conditionsbadge":
+      PrintMarkdownBadge(&obs.Current_observation, os.Stdout)
+    case "stationlistcsv":
+      CheckError(ExportStationsCSVFile(&obs, flag.Arg(0)))
+    case "yesterdaynormal":
+      PrintYesterdayVsNormal(&obs, &units, os.Stdout)
+    case "hourlyrainwindow":
+      PrintRainWindows(&obs, os.Stdout)
+    case "forecastuvpeak":
+      PrintPeakUV(&obs, os.Stdout)
+    case "forecastclothing":
+      PrintClothing(&obs, units.Metric(), os.Stdout)
+    case "forecastbestday":
+      PrintBestDay(&obs, dobestday, &units, os.Stdout)
+    case "forecastdelta":
+      PrintForecastDelta(&obs, obs.forecastBaseline, &units, os.Stdout)
+    case "forecastweekendscore":
+      PrintWeekendScores(&obs, os.Stdout)
+    case "forecasttravelindex":
+      PrintTravelIndex(&obs, travelmode, os.Stdout)
+    case "forecastraintotal":
+      PrintForecastRainTotal(&obs, units.Precipitation == "mm", os.Stdout)
+    case "forecasthighlow":
+      days := obs.Forecast.Simpleforecast.Forecastday
+      PrintForecastHighLowOnly(&obs, len(days), units.Metric(), os.Stdout)
+    case "plannerconfidence":
+      PrintPlannerConfidence(&obs.Trip, os.Stdout)
+    case "plannerraindays":
+      PrintRainyDays(&obs.Trip, os.Stdout)
     }
   }
+  if notifydesktop && len(obs.Alerts) > 0 {
+    NotifyAlerts(obs.Alerts)
+  }
+  if len(onweather) > 0 {
+    RunHooks(onweather, obs.Current_observation.Weather)
+  }
+  CheckAdvisories(&obs)
+}
+
+// CheckAdvisories runs the threshold checks requested on the command
+// line, any of which may exit with a non-zero status
+func CheckAdvisories(obs *Conditions) {
+  if windchilladv != "" {
+    CheckWindChill(&obs.Current_observation, windchilllimit)
+  }
+  if gustwarn > 0 {
+    CheckGusts(&obs.Current_observation, gustwarn, units.Metric())
+  }
+  if agewarn > 0 {
+    err := CheckObservationAge(obs.Current_observation.Observation_epoch, agewarn)
+    if _, ok := err.(*StaleDataError); ok {
+      fmt.Fprintln(os.Stderr, "⚠ "+err.Error())
+      os.Exit(3)
+    } else if err != nil {
+      fmt.Fprintln(os.Stderr, "Observation age check skipped: "+err.Error())
+    }
+  }
+  if freezewarn && obs.freezing {
+    fmt.Fprintln(os.Stderr, "⚠ Freezing temperatures are forecast")
+    os.Exit(2)
+  }
+  if exitonalert != 0 && len(obs.Alerts) > 0 {
+    os.Exit(exitonalert)
+  }
+}
+
+// Noteworthy reports whether obs has anything -cron should print: an
+// active alert or a crossed advisory threshold
+func Noteworthy(obs *Conditions) bool {
+  if len(obs.Alerts) > 0 {
+    return true
+  }
+  if freezewarn && obs.freezing {
+    return true
+  }
+  if gustwarn > 0 && GustExceeded(&obs.Current_observation, gustwarn, units.Metric()) {
+    return true
+  }
+  if agewarn > 0 {
+    err := CheckObservationAge(obs.Current_observation.Observation_epoch, agewarn)
+    if _, stale := err.(*StaleDataError); stale {
+      return true
+    }
+  }
+  return windchilladv != "" && WindChillExceeded(&obs.Current_observation, windchilllimit)
+}
+
+// historyRange prints (or plots, or averages by weekday) the daily
+// history for --history-range
+func historyRange(station string) {
+  start, end, err := ParseHistoryRange(dohistrange)
+  CheckError(Classify(InvalidInput, err))
+  days, err := FetchHistoryRange(start, end, station)
+  CheckError(err)
+  if doweekdayavg {
+    PrintWeekdayAverages(GroupByWeekday(days), station, &units)
+  } else if format == "csv" || format == "tsv" {
+    CheckError(PrintHistoryCSV(days, format, csvheader, os.Stdout))
+  } else if icalfile != "" {
+    CheckError(ExportHistoryICal(days, station, &units, icalfile))
+  } else if dohistplotprecip {
+    fmt.Printf("Daily precipitation (mm) for %s\n", station)
+    fmt.Print(PrecipBarChart(days, 80, 20))
+  } else if dohistplot {
+    fmt.Printf("Daily high and low temperatures for %s\n", station)
+    fmt.Print(plotTemperatures(days, 80, 20))
+  } else {
+    PrintHistoryRange(days, station, &units)
+  }
+  if domissing {
+    PrintMissingData(days, os.Stderr)
+  }
+}
+
+// historyFreezeDates finds the freeze dates for --history-freeze-dates,
+// looking no further than yesterday
+func historyFreezeDates(station string) {
+  year, err := time.Parse("2006", dofreezedates)
+  if err != nil {
+    Fail(InvalidInput, "Usage: wu -history-freeze-dates=\"YYYY\"")
+  }
+  end := year.AddDate(1, 0, -1)
+  if yesterday := time.Now().AddDate(0, 0, -1); end.After(yesterday) {
+    end = yesterday
+  }
+  if end.Before(year) {
+    Fail(InvalidInput, "No history is available yet for "+dofreezedates)
+  }
+  days, err := FetchHistoryRange(year, end, station)
+  CheckError(err)
+  PrintFreezeDates(days, year.Year(), station)
+}
+
+// historyArgRange parses a range given as YYYYMMDD-YYYYMMDD or, with
+// the end date as the next argument, YYYYMMDD YYYYMMDD
+func historyArgRange(r string) (time.Time, time.Time) {
+  if !strings.Contains(r, "-") && flag.NArg() > 0 {
+    r += "-" + flag.Arg(0)
+  }
+  start, end, err := ParseHistoryRange(r)
+  CheckError(Classify(InvalidInput, err))
+  return start, end
+}
+
+// historySnowfall prints the snowfall for --history-snowfall
+func historySnowfall(station string) {
+  start, end := historyArgRange(dosnowfall)
+  days, err := FetchHistoryRange(start, end, station)
+  CheckError(err)
+  PrintSnowfall(days, station, os.Stdout)
+}
+
+// historyRecordRain prints the wettest days for --history-record-rain
+func historyRecordRain(station string) {
+  start, end := historyArgRange(dorecordrain)
+  days, err := FetchHistoryRange(start, end, station)
+  CheckError(err)
+  PrintRecordRain(days, station, os.Stdout)
+}
+
+// stationUptime checks the last --station-uptime days of history,
+// ending yesterday
+func stationUptime(station string) {
+  end := time.Now().AddDate(0, 0, -1)
+  start := end.AddDate(0, 0, 1-uptimedays)
+  days, err := FetchHistoryRange(start, end, station)
+  CheckError(err)
+  PrintStationUptime(days, station, os.Stdout)
+}
+
+// conditionsSince prints the observation closest to --conditions-since
+func conditionsSince(station string) {
+  observations, err := FetchObservations(since, station)
+  CheckError(err)
+  o, err := FindClosestObservation(observations, since)
+  CheckError(Classify(APIError, err))
+  PrintObservation(o, station, &units)
+}
+
+// almanacDecade compares today's date in the first year of each of the
+// last few decades for --almanac-decade
+func almanacDecade(station string) {
+  const decades = 4
+
+  today := time.Now()
+  days, err := FetchHistoryDays(DecadeDates(today, decades), station)
+  CheckError(err)
+  PrintDecadeAverages(GroupByDecade(days), today, station, &units)
+}
+
+// historyHeatmap draws the --history-heatmap calendar for a month
+func historyHeatmap(station string) {
+  first, err := time.Parse("200601", doheatmap)
+  if err != nil {
+    Fail(InvalidInput, "Usage: wu -history-heatmap=\"YYYYMM\"")
+  }
+  days, err := FetchHistoryRange(first, first.AddDate(0, 1, -1), station)
+  CheckError(err)
+  fmt.Printf("Daily high temperatures for %s\n", station)
+  fmt.Print(CalendarHeatmap(days, first.Year(), int(first.Month()), &units))
 }
 
 func main() {
   stationId := Options()
+  if apitest {
+    os.Exit(APITest(stationId, os.Stdout))
+  }
+  if watchalert {
+    watchAlerts(stationId)
+    return
+  }
   operations := make([]string, 0)
   if dohistory != "" && doplanner != "" {
-    fmt.Println(
+    CheckError(Classify(InvalidInput, errors.New(
       "Weather Underground does not support making a history\n" +
-      "request and a planner request at the same time.")
-    os.Exit(1)
+      "request and a planner request at the same time.")))
   }
   if doall {
     operations = append(operations,"conditions")
@@ -286,41 +1384,189 @@ func main() {
     operations = append(operations,"tide")
     operations = append(operations,"geolookup")
   }
-  if doalerts {
+  if doalerts || doalertdetail {
     operations = append(operations,"alerts")
   }
-  if doalmanac {
+  if doalmanac || doalmanacdetail {
     operations = append(operations,"almanac")
   }
-  if doastro {
+  if doanomaly {
+    operations = append(operations,"historyanomaly")
+  }
+  if docondhistory {
+    operations = append(operations,"conditionshistory")
+  }
+  if doastro || doastrodetail {
     operations = append(operations,"astronomy")
   }
-  if doconditions {
+  if domoonillum {
+    operations = append(operations,"moonillumination")
+  }
+  if domoontext {
+    operations = append(operations,"moonphasetext")
+  }
+  if doconditions || dostationdist || dowide || dounitsall || dochart || dohumidex || doheatindex ||
+    dopressurefcst || translatelang != "" || pwscalibration || omitzero {
     operations = append(operations,"conditions")
   }
+  if dodewcomfort {
+    operations = append(operations,"dewpointcomfort")
+  }
+  if doepoch {
+    operations = append(operations,"conditionsepoch")
+  }
+  if doreporttime {
+    operations = append(operations,"localtime")
+  }
+  if doemoji {
+    operations = append(operations,"emojisummary")
+  }
+  if dowindrose {
+    operations = append(operations,"windrose")
+  }
+  if dodiffprevious {
+    operations = append(operations,"conditionsdiff")
+  }
+  if dotrend {
+    operations = append(operations,"conditionstrend")
+  }
   if doforecast {
     operations = append(operations,"forecast")
   }
-  if doforecast10 {
+  if doforecast10 || doweekend || doeventday != "" {
     operations = append(operations,"forecast10day")
   }
+  if dohighlow {
+    operations = append(operations,"forecasthighlow")
+  }
+  if dosummary {
+    operations = append(operations,"forecastsummary")
+  }
+  if dopack {
+    operations = append(operations,"forecastpack")
+  }
+  if doairquality {
+    operations = append(operations,"airquality")
+  }
+  if dopollen {
+    operations = append(operations,"pollen")
+  }
+  if dobadge {
+    operations = append(operations,"conditionsbadge")
+  }
+  if dostationcsv {
+    operations = append(operations,"stationlistcsv")
+  }
+  if doyestnormal {
+    operations = append(operations,"yesterdaynormal")
+  }
+  if dorainwindow {
+    operations = append(operations,"hourlyrainwindow")
+  }
+  if douvpeak {
+    operations = append(operations,"forecastuvpeak")
+  }
+  if doclothing {
+    operations = append(operations,"forecastclothing")
+  }
+  if doraintotal {
+    operations = append(operations,"forecastraintotal")
+  }
+  if dobestday != "" {
+    operations = append(operations,"forecastbestday")
+  }
+  if doforecastdelta {
+    operations = append(operations,"forecastdelta")
+  }
+  if doweekendscore {
+    operations = append(operations,"forecastweekendscore")
+  }
+  if dotravel {
+    operations = append(operations,"forecasttravelindex")
+  }
+  if dohourly || dohourlynext {
+    operations = append(operations,"hourly")
+  }
   if dohistory != "" {
     operations = append(operations,"history")
   }
   if doyesterday {
     operations = append(operations,"yesterday")
   }
-  if doplanner != "" {
+  if doyestrain {
+    operations = append(operations,"yesterdayrainfall")
+  }
+  if dorecentprecip {
+    operations = append(operations,"recentprecip")
+  }
+  if doplanner != "" && doconfidence {
+    operations = append(operations,"plannerconfidence")
+  }
+  if doplanner != "" && dorainydays {
+    operations = append(operations,"plannerraindays")
+  }
+  if doplanner != "" && !doconfidence && !dorainydays {
     operations = append(operations,"planner")
   }
-  if dotides {
+  if dotides || dotidesnext {
     operations = append(operations,"tide")
   }
   if dolookup {
     operations = append(operations,"geolookup")
   }
+  if dostationinfo {
+    operations = append(operations,"stationinfo")
+  }
+  if doairport {
+    operations = append(operations,"airportinfo")
+    operations = append(operations,"conditions")
+  }
   if flag.NFlag() == 0 {
     operations = append(operations,"conditions")
   }
-  weather(operations, stationId)
+  // The history reports make their own requests, so the features
+  // switches like -format csv depend on are only fetched without them
+  if len(operations) > 0 || !historymode && len(Dependencies(operations)) > 0 {
+    weather(operations, stationId)
+  }
+  // Nothing in these reports can be noteworthy, so -cron skips them
+  if cron && !verbose {
+    return
+  }
+  if dohistrange != "" {
+    historyRange(stationId)
+  }
+  if doheatmap != "" {
+    historyHeatmap(stationId)
+  }
+  if dofreezedates != "" {
+    historyFreezeDates(stationId)
+  }
+  if dosnowfall != "" {
+    historySnowfall(stationId)
+  }
+  if dorecordrain != "" {
+    historyRecordRain(stationId)
+  }
+  if uptimedays > 0 {
+    stationUptime(stationId)
+  }
+  if dosince != "" {
+    conditionsSince(stationId)
+  }
+  if dodecade {
+    almanacDecade(stationId)
+  }
+  if doextremes {
+    almanacs, err := FetchMonthlySummaries(stationId)
+    CheckError(err)
+    PrintExtremes(almanacs, stationId, &units)
+  }
+  if docompare {
+    trips := FetchPlanners(stationId, flag.Arg(0), flag.Arg(1))
+    PrintPlannerComparison(trips[0], trips[1], flag.Arg(0), flag.Arg(1), &units, os.Stdout)
+  }
+  if timing {
+    metrics.PrintTiming(os.Stderr)
+  }
 }
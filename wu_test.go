@@ -0,0 +1,190 @@
+/*
+* wu_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for option handling and the main report loop (wu.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:10:01 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "errors"
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "os/exec"
+  "regexp"
+  "strings"
+  "testing"
+)
+
+// captureStdout returns everything f writes to standard out
+func captureStdout(t *testing.T, f func()) string {
+  r, w, err := os.Pipe()
+  if err != nil {
+    t.Fatal(err)
+  }
+  stdout := os.Stdout
+  os.Stdout = w
+  defer func() { os.Stdout = stdout }()
+  done := make(chan []byte)
+  go func() {
+    b, _ := ioutil.ReadAll(r)
+    done <- b
+  }()
+  f()
+  w.Close()
+  return string(<-done)
+}
+
+// serveAPI points requests at a local server that answers every one
+// with response, until the test ends
+func serveAPI(t *testing.T, response string) {
+  srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    w.Write([]byte(response))
+  }))
+  url := apiURL
+  apiURL = srv.URL + "/api/"
+  t.Cleanup(func() {
+    apiURL = url
+    srv.Close()
+  })
+  t.Setenv("HOME", t.TempDir())
+}
+
+func TestCronQuiet(t *testing.T) {
+  serveAPI(t, `{"current_observation": {"station_id": "KLNK", "temp_f": "72.3", "weather": "Clear",
+    "observation_epoch": "1378062000"}, "alerts": []}`)
+  defer func(c, v, q bool, e int) { cron, verbose, quiet, exitonalert = c, v, q, e }(cron, verbose, quiet, exitonalert)
+  cron, quiet, exitonalert = true, true, 1
+
+  verbose = false
+  if out := captureStdout(t, func() { weather([]string{"conditions", "alerts"}, "KLNK") }); out != "" {
+    t.Errorf("-cron without alerts printed %q", out)
+  }
+  verbose = true
+  if out := captureStdout(t, func() { weather([]string{"conditions", "alerts"}, "KLNK") }); out == "" {
+    t.Error("-cron -verbose printed nothing")
+  }
+}
+
+func TestNoteworthy(t *testing.T) {
+  defer func(f bool, g float64) { freezewarn, gustwarn = f, g }(freezewarn, gustwarn)
+  tests := []struct {
+    name       string
+    obs        Conditions
+    freezewarn bool
+    gustwarn   float64
+    want       bool
+  }{
+    {"nominal", Conditions{}, false, 0, false},
+    {"alert", Conditions{Alerts: []Alerts{{Description: "Tornado Warning"}}}, false, 0, true},
+    {"freezing", Conditions{freezing: true}, true, 0, true},
+    {"freezing unwatched", Conditions{freezing: true}, false, 0, false},
+    {"gusts", Conditions{Current_observation: Current{Wind_gust_mph: "45"}}, false, 40, true},
+    {"light gusts", Conditions{Current_observation: Current{Wind_gust_mph: "25"}}, false, 40, false},
+  }
+  for _, tt := range tests {
+    freezewarn, gustwarn = tt.freezewarn, tt.gustwarn
+    if got := Noteworthy(&tt.obs); got != tt.want {
+      t.Errorf("%s: Noteworthy = %v, want %v", tt.name, got, tt.want)
+    }
+  }
+}
+
+func TestAPITest(t *testing.T) {
+  tests := []struct {
+    name     string
+    response string // "" for an unreachable server
+    status   int
+    want     string
+  }{
+    {"valid", `{"current_observation": {"station_id": "KLNK"}}`, 0,
+      `^API key is valid\. Connection to Weather Underground: OK\. Response time: \d+ms\.\n$`},
+    {"bad key", `{"response": {"error": {"type": "keynotfound", "description": "this key does not exist"}}}`, 4,
+      `^API key authentication failed\. Check your ~/\.condrc\.\n$`},
+    {"quota", `{"response": {"error": {"type": "invalidkey", "description": "this key has exceeded its daily rate limit"}}}`, 4,
+      `^API request limit reached\.  Try again later\.\n$`},
+    {"garbage", `<html>`, 5, `^Unexpected response from Weather Underground: `},
+    {"unreachable", "", 5, `^Network error: .*connection refused\.\n$`},
+  }
+  for _, tt := range tests {
+    t.Run(tt.name, func(t *testing.T) {
+      serveAPI(t, tt.response)
+      if tt.response == "" {
+        srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+        srv.Close()
+        apiURL = srv.URL + "/api/"
+      }
+      var buf bytes.Buffer
+      status := APITest("KLNK", &buf)
+      if status != tt.status {
+        t.Errorf("exit status %d, want %d", status, tt.status)
+      }
+      if !regexp.MustCompile(tt.want).MatchString(buf.String()) {
+        t.Errorf("printed %q, want %s", buf.String(), tt.want)
+      }
+      if strings.Contains(buf.String(), conf.Key) && conf.Key != "" {
+        t.Errorf("printed the API key: %q", buf.String())
+      }
+    })
+  }
+}
+
+const optionsHelperEnv = "WU_TEST_OPTIONS"
+
+// TestOptionsHelper parses the command line in $WU_TEST_OPTIONS (one
+// argument to a line), as if .condrc were in place, and prints the
+// station, for runOptions
+func TestOptionsHelper(t *testing.T) {
+  args, ok := os.LookupEnv(optionsHelperEnv)
+  if !ok {
+    return
+  }
+  conf, noconf, confErr = Config{Key: "0123456789abcdef0123456789abcdef"}, false, nil
+  os.Args = append([]string{"wu"}, strings.Split(args, "\n")...)
+  fmt.Println(Options())
+  os.Exit(0)
+}
+
+// runOptions runs Options on args in a new process (as it may exit)
+// and returns its exit status, standard out, and standard error
+func runOptions(t *testing.T, args ...string) (int, string, string) {
+  cmd := exec.Command(os.Args[0], "-test.run=^TestOptionsHelper$")
+  cmd.Env = append(os.Environ(), optionsHelperEnv+"="+strings.Join(args, "\n"))
+  var stdout, stderr bytes.Buffer
+  cmd.Stdout, cmd.Stderr = &stdout, &stderr
+  err := cmd.Run()
+  status := 0
+  var exit *exec.ExitError
+  if errors.As(err, &exit) {
+    status = exit.ExitCode()
+  } else if err != nil {
+    t.Fatal(err)
+  }
+  return status, stdout.String(), stderr.String()
+}
@@ -0,0 +1,55 @@
+/*
+* yesterday.go
+*
+* This file is part of wu.  It contains functions related to
+* the --yesterday-rainfall switch.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 18:58:21 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+)
+
+// PrintYesterdayRainfall prints yesterday's total precipitation as a
+// bare number, in inches or (if metric) millimeters.  A trace is
+// printed as "T" unless --precip-format zero asks for "0.00".
+func PrintYesterdayRainfall(obs *Conditions, metric bool, w io.Writer) {
+  if len(obs.History.Dailysummary) == 0 {
+    Fail(APIError, "No data available for yesterday")
+  }
+  s := obs.History.Dailysummary[0]
+  precip := s.Precipi
+  if metric {
+    precip = s.Precipm
+  }
+  if precip == "T" && precipformat == "zero" {
+    precip = "0.00"
+  }
+  fmt.Fprint(w, precip)
+  if !quiet {
+    fmt.Fprintln(w)
+  }
+}
@@ -0,0 +1,65 @@
+/*
+* yesterday_test.go
+*
+* This file is part of wu.  It contains functions related to
+* tests for the -yesterday switches (yesterday.go).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Wed Oct 14 21:17:51 CDT 2026
+*
+* Copyright © 2010-2014 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "testing"
+)
+
+func TestPrintYesterdayRainfall(t *testing.T) {
+  saved := precipformat
+  t.Cleanup(func() { precipformat = saved })
+  tests := []struct {
+    name         string
+    precipi      string
+    precipm      string
+    metric       bool
+    precipformat string
+    want         string
+  }{
+    {"normal", "0.34", "8.64", false, "trace", "0.34\n"},
+    {"normal metric", "0.34", "8.64", true, "trace", "8.64\n"},
+    {"zero", "0.00", "0.00", false, "trace", "0.00\n"},
+    {"zero as zero", "0.00", "0.00", false, "zero", "0.00\n"},
+    {"trace", "T", "T", false, "trace", "T\n"},
+    {"trace metric", "T", "T", true, "trace", "T\n"},
+    {"trace as zero", "T", "T", false, "zero", "0.00\n"},
+  }
+  for _, tt := range tests {
+    precipformat = tt.precipformat
+    var obs Conditions
+    obs.History.Dailysummary = []Dailysummary{{Precipi: tt.precipi, Precipm: tt.precipm}}
+    var buf bytes.Buffer
+    PrintYesterdayRainfall(&obs, tt.metric, &buf)
+    if buf.String() != tt.want {
+      t.Errorf("%s: printed %q, want %q", tt.name, buf.String(), tt.want)
+    }
+  }
+}